@@ -5,8 +5,12 @@ import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/jwt"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/metrics"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/memory"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"fmt"
+	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -17,29 +21,149 @@ type App struct {
 	AuthService entity.IAuthService
 	ItemService entity.IItemService
 	UserService entity.IUserService
+
+	TokenManager        jwt.ITokenManager
+	IdempotencyService  entity.IIdempotencyService
+	LoginAttemptService entity.ILoginAttemptService
+	RateLimitService    entity.IRateLimitService
+	HealthService       entity.IHealthService
+
+	Metrics          *metrics.Metrics
+	MetricsSnapshots *metrics.Snapshots // nil if metrics are not shared between prefork processes
 }
 
-func NewApp(db *pgxpool.Pool, cfg *config.Config, logger logger.ILogger) *App {
+func NewApp(db *pgxpool.Pool, cfg *config.Config, logger logger.ILogger) (*App, error) {
+	keys, err := jwtKeySet(&cfg.Jwt)
+	if err != nil {
+		return nil, fmt.Errorf("loading jwt keys: %w", err)
+	}
+	tokenManager := jwt.NewTokenManager(keys, cfg.Jwt.AccessTTL, cfg.Jwt.RefreshTTL)
+
+	appMetrics := metrics.NewMetrics()
+	appMetrics.RegisterPool(db)
+	var snapshots *metrics.Snapshots
+	if cfg.Metrics.Dir != "" {
+		snapshots = metrics.NewSnapshots(appMetrics, cfg.Metrics.Dir, cfg.Metrics.SnapshotInterval)
+	}
+
 	authRepo := postgres.NewAuthRepository(db)
+	tokenRepo := postgres.NewTokenRepository(db)
 	itemRepo := postgres.NewItemRepository(db)
 	userRepo := postgres.NewUserRepository(db)
+	idempotencyRepo := postgres.NewIdempotencyRepository(db)
+	loginAttemptRepo := postgres.NewLoginAttemptRepository(db)
+	rateLimitRepo := postgres.NewRateLimitRepository(db)
+	healthRepo := postgres.NewHealthRepository(db)
+	if cfg.RateLimit.Store == "memory" {
+		rateLimitRepo = memory.NewRateLimitRepository()
+	}
+
+	authService := service.NewAuthService(
+		authRepo,
+		tokenRepo,
+		logger,
+		jwt.NewHashCrypto(jwt.HashParams{
+			Algorithm:  cfg.Hash.Algorithm,
+			BcryptCost: cfg.Hash.BcryptCost,
+			Argon2: jwt.Argon2Params{
+				Time:    cfg.Hash.Argon2.Time,
+				Memory:  cfg.Hash.Argon2.Memory,
+				Threads: cfg.Hash.Argon2.Threads,
+			},
+		}),
+		tokenManager,
+		cfg.Auth.AutoRegister,
+	)
+	itemService := service.NewItemService(
+		itemRepo,
+		logger,
+		cfg.Shop.RefundWindow,
+	)
+	userService := service.NewUserService(
+		userRepo,
+		logger,
+	)
+	idempotencyService := service.NewIdempotencyService(
+		idempotencyRepo,
+		logger,
+	)
+	loginAttemptService := service.NewLoginAttemptService(
+		loginAttemptRepo,
+		logger,
+		entity.BruteForcePolicy{
+			Window:            cfg.Auth.BruteForce.Window,
+			UsernameThreshold: cfg.Auth.BruteForce.UsernameThreshold,
+			IPThreshold:       cfg.Auth.BruteForce.IPThreshold,
+			BaseDelay:         cfg.Auth.BruteForce.BaseDelay,
+			MaxDelay:          cfg.Auth.BruteForce.MaxDelay,
+		},
+	)
+	rateLimitService := service.NewRateLimitService(
+		rateLimitRepo,
+		logger,
+		rateLimitPolicy(&cfg.RateLimit),
+	)
 
 	return &App{
-		Config: cfg,
-		Logger: logger,
-		AuthService: service.NewAuthService(
-			authRepo,
-			logger,
-			jwt.NewHashCrypto(),
-			jwt.NewTokenManager(cfg.Jwt.Key),
-		),
-		ItemService: service.NewItemService(
-			itemRepo,
-			logger,
-		),
-		UserService: service.NewUserService(
-			userRepo,
+		Config:           cfg,
+		Logger:           logger,
+		TokenManager:     tokenManager,
+		Metrics:          appMetrics,
+		MetricsSnapshots: snapshots,
+
+		AuthService:         service.NewAuthServiceMetrics(service.NewAuthServiceTracing(authService), appMetrics),
+		ItemService:         service.NewItemServiceMetrics(service.NewItemServiceTracing(itemService), appMetrics),
+		UserService:         service.NewUserServiceMetrics(service.NewUserServiceTracing(userService), appMetrics),
+		IdempotencyService:  service.NewIdempotencyServiceTracing(idempotencyService),
+		LoginAttemptService: service.NewLoginAttemptServiceTracing(loginAttemptService),
+		RateLimitService:    service.NewRateLimitServiceTracing(rateLimitService),
+		HealthService: service.NewHealthService(
+			healthRepo,
 			logger,
+			postgres.SchemaVersion,
+			cfg.HTTP.HealthCheckTimeout,
 		),
+	}, nil
+}
+
+func rateLimitPolicy(cfg *config.RateLimitConfig) entity.RateLimitPolicy {
+	policy := entity.RateLimitPolicy{
+		Default: entity.RateLimitBudget{
+			Limit:  cfg.Limit,
+			Window: cfg.Window,
+		},
+		Routes: make([]entity.RouteRateLimit, 0, len(cfg.Routes)),
+	}
+	for _, route := range cfg.Routes {
+		policy.Routes = append(policy.Routes, entity.RouteRateLimit{
+			Path: route.Path,
+			RateLimitBudget: entity.RateLimitBudget{
+				Limit:  route.Limit,
+				Window: route.Window,
+			},
+		})
 	}
+	return policy
+}
+
+func jwtKeySet(cfg *config.Jwt) (*jwt.KeySet, error) {
+	signingKeys := make([]*jwt.SigningKey, 0, len(cfg.SigningKeys))
+	for _, keyCfg := range cfg.SigningKeys {
+		pemData := []byte(keyCfg.PEM)
+		if keyCfg.File != "" {
+			var err error
+			pemData, err = os.ReadFile(keyCfg.File)
+			if err != nil {
+				return nil, fmt.Errorf("reading key %s: %w", keyCfg.KID, err)
+			}
+		}
+
+		key, err := jwt.ParseSigningKey(keyCfg.KID, pemData)
+		if err != nil {
+			return nil, err
+		}
+		signingKeys = append(signingKeys, key)
+	}
+
+	return jwt.NewKeySet(cfg.Key, signingKeys...)
 }
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RunCleanup periodically calls cleanup until ctx is done
+func (a *App) RunCleanup(ctx context.Context, interval time.Duration,
+	cleanup func(ctx context.Context, now time.Time) error, onError func(error),
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := cleanup(ctx, time.Now())
+			if err != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// DeleteExpired deletes expired records of all stores, database stores are shared between processes,
+// so it runs in prefork master only and requests do not pay for it
+func (a *App) DeleteExpired(ctx context.Context, now time.Time) error {
+	return errors.Join(
+		a.LoginAttemptService.DeleteExpired(ctx, now),
+		a.RateLimitService.DeleteExpired(ctx, now),
+		a.IdempotencyService.DeleteExpired(ctx, now),
+	)
+}
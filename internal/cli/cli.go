@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const Usage = `usage: shopctl [-o table|json] <command> [flags] <args>
+
+commands:
+  user show <username>                       balance, inventory and coins history
+  coins grant [-comment text] <username> <amount>
+                                             credit coins to user
+  item add [-stock n] <name> <price>         add item to catalog, stock is unlimited by default
+  history export [-direction sent|received] [-from time] [-to time] <username>
+                                             all transactions of user, time is RFC3339`
+
+// historyPageSize is page size used to walk through transactions, maximum allowed by service
+const historyPageSize = 100
+
+type command struct {
+	name string
+	run  func(ctx context.Context, app *app.App, p *printer, args []string) error
+}
+
+var commands = []command{
+	{"user show", userShow},
+	{"coins grant", coinsGrant},
+	{"item add", itemAdd},
+	{"history export", historyExport},
+}
+
+// Run executes shopctl command, args are command line without program name.
+// Commands go through services of app, so validation and transactions are the same as in API
+func Run(ctx context.Context, app *app.App, args []string, out io.Writer) error {
+	fs := newFlagSet("shopctl")
+	format := fs.String("o", FormatTable, "output format: table or json")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *format != FormatTable && *format != FormatJSON {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+
+	args = fs.Args()
+	if len(args) < 2 {
+		return fmt.Errorf(Usage)
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0]+" "+args[1] {
+			return cmd.run(ctx, app, &printer{out: out, format: *format}, args[2:])
+		}
+	}
+	return fmt.Errorf("unknown command %q\n%s", args[0]+" "+args[1], Usage)
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseArgs parses flags placed anywhere among n positional arguments
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	positional := make([]string, 0, n)
+	for {
+		err := fs.Parse(args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fs.Name(), err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != n {
+		return nil, fmt.Errorf("%s: expected %d arguments, got %d", fs.Name(), n, len(positional))
+	}
+	return positional, nil
+}
+
+func parseInt32(name, raw string) (int32, error) {
+	val, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be integer", name)
+	}
+	return int32(val), nil
+}
+
+type userView struct {
+	Username    string           `json:"username"`
+	Coins       int32            `json:"coins"`
+	Inventory   []*models.Item   `json:"inventory"`
+	CoinHistory *coinHistoryView `json:"coinHistory"`
+}
+
+func userShow(ctx context.Context, app *app.App, p *printer, args []string) error {
+	args, err := parseArgs(newFlagSet("user show"), args, 1)
+	if err != nil {
+		return err
+	}
+	username := args[0]
+
+	coins, history, err := app.UserService.GetCoinsHistory(ctx, username)
+	if err != nil {
+		return fmt.Errorf("getting coins history: %w", err)
+	}
+	items, err := app.ItemService.GetInventory(ctx, username)
+	if err != nil {
+		return fmt.Errorf("getting inventory: %w", err)
+	}
+
+	inventory := &table{header: []string{"ITEM", "QUANTITY"}}
+	for _, item := range items {
+		inventory.rows = append(inventory.rows, []string{item.Name, strconv.Itoa(int(item.Quantity))})
+	}
+	transfers := &table{header: []string{"DIRECTION", "USER", "AMOUNT", "COMMENT"}}
+	for _, entry := range history.Received {
+		transfers.rows = append(transfers.rows, historyRow(entity.DirectionReceived, entry))
+	}
+	for _, entry := range history.Sent {
+		transfers.rows = append(transfers.rows, historyRow(entity.DirectionSent, entry))
+	}
+
+	return p.print(
+		&userView{
+			Username:    username,
+			Coins:       coins,
+			Inventory:   models.ToInventoryTransport(items),
+			CoinHistory: toCoinHistoryView(models.ToCoinsHistoryTransport(history)),
+		},
+		&table{
+			header: []string{"USERNAME", "COINS"},
+			rows:   [][]string{{username, strconv.Itoa(int(coins))}},
+		},
+		inventory,
+		transfers,
+	)
+}
+
+func historyRow(direction string, entry *entity.CoinsHistoryEntry) []string {
+	return []string{direction, cell(entry.Username), strconv.Itoa(int(entry.Coins)), cell(entry.Comment)}
+}
+
+type balanceView struct {
+	Username string `json:"username"`
+	Coins    int32  `json:"coins"`
+}
+
+func coinsGrant(ctx context.Context, app *app.App, p *printer, args []string) error {
+	fs := newFlagSet("coins grant")
+	comment := fs.String("comment", "", "comment kept in history")
+	args, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	amount, err := parseInt32("amount", args[1])
+	if err != nil {
+		return err
+	}
+
+	err = app.UserService.GrantCoins(ctx, &entity.GrantCoins{
+		Username: args[0],
+		Amount:   amount,
+		Comment:  *comment,
+	})
+	if err != nil {
+		return fmt.Errorf("granting coins: %w", err)
+	}
+
+	coins, _, err := app.UserService.GetCoinsHistory(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("getting balance: %w", err)
+	}
+	return p.print(
+		&balanceView{Username: args[0], Coins: coins},
+		&table{
+			header: []string{"USERNAME", "COINS"},
+			rows:   [][]string{{args[0], strconv.Itoa(int(coins))}},
+		},
+	)
+}
+
+func itemAdd(ctx context.Context, app *app.App, p *printer, args []string) error {
+	fs := newFlagSet("item add")
+	rawStock := fs.String("stock", "", "units in stock, unlimited if not set")
+	args, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	item := &entity.Item{Name: args[0]}
+	item.Price, err = parseInt32("price", args[1])
+	if err != nil {
+		return err
+	}
+	stock := "unlimited"
+	if *rawStock != "" {
+		val, err := parseInt32("stock", *rawStock)
+		if err != nil {
+			return err
+		}
+		item.Stock = &val
+		stock = *rawStock
+	}
+
+	err = app.ItemService.CreateItem(ctx, item)
+	if err != nil {
+		return fmt.Errorf("creating item: %w", err)
+	}
+	return p.print(
+		models.ToCatalogItemTransport(item),
+		&table{
+			header: []string{"NAME", "PRICE", "STOCK"},
+			rows:   [][]string{{item.Name, strconv.Itoa(int(item.Price)), stock}},
+		},
+	)
+}
+
+func historyExport(ctx context.Context, app *app.App, p *printer, args []string) error {
+	fs := newFlagSet("history export")
+	direction := fs.String("direction", "", "sent, received or empty for both")
+	rawFrom := fs.String("from", "", "only transactions at or after time")
+	rawTo := fs.String("to", "", "only transactions before time")
+	args, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	filter := &entity.TransactionsFilter{
+		Username:  args[0],
+		Direction: *direction,
+		Limit:     historyPageSize,
+	}
+	filter.From, err = optionalTime("from", *rawFrom)
+	if err != nil {
+		return err
+	}
+	filter.To, err = optionalTime("to", *rawTo)
+	if err != nil {
+		return err
+	}
+
+	transactions := make([]*transactionView, 0)
+	for {
+		page, err := app.UserService.GetTransactions(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("getting transactions: %w", err)
+		}
+		for _, transaction := range page.Transactions {
+			transactions = append(transactions, &transactionView{
+				Transaction: models.ToTransactionTransport(transaction),
+				Source:      transferSource(transaction.FromUser),
+			})
+		}
+		if page.Next == nil {
+			break
+		}
+		filter.After = page.Next
+	}
+
+	history := &table{header: []string{"TIME", "ID", "SOURCE", "FROM", "TO", "AMOUNT", "COMMENT"}}
+	for _, transaction := range transactions {
+		history.rows = append(history.rows, []string{
+			transaction.Time.Format(time.RFC3339),
+			transaction.ID,
+			transaction.Source,
+			cell(transaction.FromUser), // empty for granted coins
+			transaction.ToUser,
+			strconv.Itoa(int(transaction.Amount)),
+			cell(transaction.Comment),
+		})
+	}
+	return p.print(transactions, history)
+}
+
+func optionalTime(name, raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	val, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be RFC3339 time", name)
+	}
+	return &val, nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// table is a block of table output, rows are already formatted
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+type printer struct {
+	out    io.Writer
+	format string
+}
+
+// print writes value as JSON or tables separated by empty line
+func (p *printer) print(value interface{}, tables ...*table) error {
+	if p.format == FormatJSON {
+		enc := json.NewEncoder(p.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	for i, t := range tables {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+	}
+	return w.Flush()
+}
+
+// cell replaces empty value so columns of table stay aligned
+func cell(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+const (
+	sourceUser  = "user"
+	sourceGrant = "grant" // coins granted by administrator have no sender
+)
+
+func transferSource(fromUser string) string {
+	if fromUser == "" {
+		return sourceGrant
+	}
+	return sourceUser
+}
+
+// transactionView is API transaction with source, so granted coins are told from transfers
+type transactionView struct {
+	*models.Transaction
+	Source string `json:"source"`
+}
+
+type receivedTransferView struct {
+	*models.CoinReceivedTransfer
+	Source string `json:"source"`
+}
+
+type coinHistoryView struct {
+	Received []*receivedTransferView    `json:"received,omitempty"`
+	Sent     []*models.CoinSentTransfer `json:"sent,omitempty"`
+}
+
+func toCoinHistoryView(history *models.CoinHistory) *coinHistoryView {
+	view := &coinHistoryView{
+		Received: make([]*receivedTransferView, len(history.Received)),
+		Sent:     history.Sent,
+	}
+	for i, transfer := range history.Received {
+		view.Received[i] = &receivedTransferView{
+			CoinReceivedTransfer: transfer,
+			Source:               transferSource(transfer.FromUser),
+		}
+	}
+	return view
+}
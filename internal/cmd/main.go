@@ -2,26 +2,29 @@ package main
 
 import (
 	appPackage "Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
 	loggerPackage "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/tracing"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/web/handlers"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/web/middlewares"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/server"
 	"context"
 	"fmt"
 	"os"
-	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 const (
 	GracefulShutdownSeconds = 30
+	CleanupInterval         = time.Minute
 )
 
 func main() {
@@ -30,6 +33,13 @@ func main() {
 	if err != nil {
 		log.Fatalf("reading config error: %v\n", err)
 	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err = runMigrate(cfg, os.Args[2:], os.Stdout)
+		if err != nil {
+			log.Fatalf("migrate: %v\n", err)
+		}
+		return
+	}
 
 	logFile, err := os.OpenFile(cfg.Logger.File, os.O_APPEND|os.O_WRONLY|os.O_CREATE, os.ModeAppend)
 	if err != nil {
@@ -43,46 +53,129 @@ func main() {
 	}(logFile)
 	svcLogger := loggerPackage.NewLogger(cfg.Logger.Level, logFile)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Setting up tracing error: %v\n", err)
+	}
+
 	pool, err := postgres.NewConn(context.Background(), &cfg.Database)
 	if err != nil {
 		log.Fatalf("Connecting to database error: %v\n", err)
 	}
+	// prefork children are started after master listens, so migrations are applied once before serving
+	if cfg.Database.MigrateOnStart && !fiber.IsChild() {
+		err = migrateOnStart(pool)
+		if err != nil {
+			log.Fatalf("Applying migrations error: %v\n", err)
+		}
+	}
+
+	app, err := appPackage.NewApp(pool, cfg, svcLogger)
+	if err != nil {
+		log.Fatalf("Creating app error: %v\n", err)
+	}
 
-	app := appPackage.NewApp(pool, cfg, svcLogger)
+	// prefork master only clears snapshots of previous run and deletes expired records,
+	// children serve requests and write snapshots
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	var backgroundWG sync.WaitGroup
+	if !fiber.IsChild() {
+		backgroundWG.Add(1)
+		go func() {
+			defer backgroundWG.Done()
+			app.RunCleanup(backgroundCtx, CleanupInterval, app.DeleteExpired, func(err error) {
+				svcLogger.Warnf("deleting expired records: %v", err)
+			})
+		}()
+	} else if cfg.RateLimit.Store == "memory" {
+		// memory counters belong to the process, so every child cleans its own
+		backgroundWG.Add(1)
+		go func() {
+			defer backgroundWG.Done()
+			app.RunCleanup(backgroundCtx, CleanupInterval, app.RateLimitService.DeleteExpired, func(err error) {
+				svcLogger.Warnf("deleting expired rate limit counters: %v", err)
+			})
+		}()
+	}
+	if app.MetricsSnapshots != nil && !fiber.IsChild() {
+		err = app.MetricsSnapshots.Reset()
+		if err != nil {
+			log.Fatalf("Resetting metrics snapshots error: %v\n", err)
+		}
+	} else if app.MetricsSnapshots != nil {
+		backgroundWG.Add(1)
+		go func() {
+			defer backgroundWG.Done()
+			app.MetricsSnapshots.Run(backgroundCtx, cfg.Metrics.SnapshotInterval, func(err error) {
+				svcLogger.Warnf("writing metrics snapshot: %v", err)
+			})
+		}()
+	}
 
 	r := fiber.New(fiber.Config{
 		Prefork:       true,
 		ServerHeader:  "Avito-shop",
 		CaseSensitive: true,
+		ErrorHandler:  middlewares.ErrorHandler(app),
 	})
-	r.Use(logger.New())
+	r.Use(middlewares.RequestIDMiddleware())
+	r.Use(middlewares.AccessLogMiddleware(app))
 	r.Use(cors.New())
+	r.Use(middlewares.TracingMiddleware())
+	r.Use(middlewares.MetricsMiddleware(app))
+
+	r.Get("/.well-known/jwks.json", handlers.JWKSHandler(app))
+	r.Get("/metrics", handlers.MetricsHandler(app))
+	r.Get("/healthz", handlers.LivenessHandler(app))
+	r.Get("/readyz", handlers.ReadinessHandler(app))
 
 	r.Route("/api", func(r fiber.Router) {
-		r.Post("/auth", handlers.AuthHandler(app))
+		ipRateLimit := middlewares.RateLimitMiddleware(app, middlewares.RateLimitByIP)
+		r.Post("/register", ipRateLimit, handlers.RegisterHandler(app))
+		r.Post("/auth", ipRateLimit, handlers.AuthHandler(app))
+		r.Post("/auth/refresh", ipRateLimit, handlers.RefreshHandler(app))
+		r.Get("/items", ipRateLimit, handlers.GetItemsHandler(app))
+		r.Get("/items/:name", ipRateLimit, handlers.GetItemHandler(app))
 
-		r.Use(middlewares.JwtMiddleware(cfg.Jwt.Key))
-		r.Get("/buy/:item", handlers.BuyItemHandler(app))
+		r.Use(middlewares.JwtMiddleware(app))
+		r.Use(middlewares.RateLimitMiddleware(app, middlewares.RateLimitByUser))
+		r.Post("/auth/logout", handlers.LogoutHandler(app))
+		r.Post("/auth/password", handlers.ChangePasswordHandler(app))
+		r.Get("/buy/:item", middlewares.IdempotencyMiddleware(app), handlers.BuyItemHandler(app))
+		r.Post("/buy", middlewares.IdempotencyMiddleware(app), handlers.BuyItemsHandler(app))
 
-		r.Post("/sendCoin", handlers.SendCoinsHandler(app))
+		r.Post("/sendCoin", middlewares.IdempotencyMiddleware(app), handlers.SendCoinsHandler(app))
 		r.Get("/info", handlers.GetUserInfoHandler(app))
+		r.Get("/transactions", handlers.GetTransactionsHandler(app))
+		r.Get("/purchases", handlers.GetPurchasesHandler(app))
+		r.Post("/purchases/:id/refund", middlewares.IdempotencyMiddleware(app), handlers.RefundPurchaseHandler(app))
+
+		r.Route("/admin", func(r fiber.Router) {
+			r.Use(middlewares.RoleMiddleware(entity.RoleAdmin))
+			r.Post("/items", handlers.CreateItemHandler(app))
+			r.Put("/items/:name", handlers.UpdateItemPriceHandler(app))
+			r.Put("/items/:name/stock", handlers.UpdateItemStockHandler(app))
+			r.Delete("/items/:name", handlers.RetireItemHandler(app))
+			r.Put("/users/:username/password", handlers.ResetPasswordHandler(app))
+		})
 	})
 
-	go func() {
-		if err := r.Listen(fmt.Sprintf(":%d", cfg.HTTP.Port)); err != nil {
-			log.Panic(err)
+	srv := server.New(r, app.HealthService, cfg.HTTP.ShutdownDelay, GracefulShutdownSeconds*time.Second)
+	err = srv.Run(fmt.Sprintf(":%d", cfg.HTTP.Port), func() {
+		log.Info(syscall.Getpid(), " requests finished, stopping background work...")
+		stopBackground()
+		backgroundWG.Wait()
+		tracingErr := shutdownTracing(context.Background())
+		if tracingErr != nil {
+			log.Error("Flushing traces error: ", tracingErr)
 		}
-	}()
-
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
-
-	<-sig
-	log.Info(syscall.Getpid(), " gracefully shutting down...")
-	err = r.ShutdownWithTimeout(GracefulShutdownSeconds * time.Second)
+	}, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	if err != nil {
 		log.Fatal(err)
-	} else {
-		log.Info(syscall.Getpid(), " successful graceful shutdown!")
+	}
+	log.Info(syscall.Getpid(), " successful graceful shutdown!")
+	err = srv.Done()
+	if err != nil {
+		log.Error(err)
 	}
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status | version"
+
+// runMigrate runs migrate subcommand with args following it
+func runMigrate(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) == 0 || len(args) > 2 || len(args) == 2 && args[0] != "down" {
+		return fmt.Errorf(migrateUsage)
+	}
+	steps := 1
+	if len(args) == 2 {
+		var err error
+		steps, err = strconv.Atoi(args[1])
+		if err != nil || steps <= 0 {
+			return fmt.Errorf("steps must be positive number")
+		}
+	}
+
+	pool, err := postgres.NewConn(context.Background(), &cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	migrator, err := postgres.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = migrator.Close()
+	}()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down(steps)
+	case "status":
+		return printMigrationsStatus(migrator, out)
+	case "version":
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+	if err != nil {
+		return err
+	}
+	return printSchemaVersion(migrator, out)
+}
+
+func printSchemaVersion(migrator *postgres.Migrator, out io.Writer) error {
+	version, dirty, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		_, err = fmt.Fprintf(out, "%d (dirty)\n", version)
+	} else {
+		_, err = fmt.Fprintf(out, "%d\n", version)
+	}
+	return err
+}
+
+func printMigrationsStatus(migrator *postgres.Migrator, out io.Writer) error {
+	list, dirty, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for i, migration := range list {
+		status := "pending"
+		if migration.Applied {
+			status = "applied"
+			// only the last applied migration may fail
+			if dirty && (i+1 == len(list) || !list[i+1].Applied) {
+				status = "dirty"
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", migration.Version, migration.Name, status)
+	}
+	return w.Flush()
+}
+
+// migrateOnStart applies pending migrations before server starts
+func migrateOnStart(pool *pgxpool.Pool) error {
+	migrator, err := postgres.NewMigrator(pool)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = migrator.Close()
+	}()
+	return migrator.Up()
+}
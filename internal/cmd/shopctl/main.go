@@ -0,0 +1,51 @@
+package main
+
+import (
+	appPackage "Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/cli"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
+	loggerPackage "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, cli.Usage)
+		os.Exit(2)
+	}
+
+	err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "shopctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg, err := config.ReadConfig(os.Getenv("AVITO_SHOP_CONFIG_PATH"))
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	// service logs go to stderr so that stdout keeps only command output
+	logger := loggerPackage.NewLogger(cfg.Logger.Level, os.Stderr)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := postgres.NewConn(ctx, &cfg.Database)
+	if err != nil {
+		return fmt.Errorf("connecting to database: %w", err)
+	}
+	defer pool.Close()
+
+	app, err := appPackage.NewApp(pool, cfg, logger)
+	if err != nil {
+		return fmt.Errorf("creating app: %w", err)
+	}
+	return cli.Run(ctx, app, os.Args[1:], os.Stdout)
+}
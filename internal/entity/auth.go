@@ -2,16 +2,39 @@ package entity
 
 import "context"
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type Auth struct {
 	Username string
 	Password string
+	Role     string
+}
+
+type PasswordChange struct {
+	Username    string
+	OldPassword string
+	NewPassword string
 }
 
 type IAuthRepository interface {
 	GetByUsername(ctx context.Context, username string) (*Auth, error)
 	Register(ctx context.Context, authInfo *Auth) error
+	// UpdatePassword sets new password hash and invalidates all user tokens
+	UpdatePassword(ctx context.Context, authInfo *Auth) error
+	// UpdatePasswordHash replaces oldHash with authInfo.Password, keeping user tokens valid;
+	// does nothing if password was changed after oldHash was read
+	UpdatePasswordHash(ctx context.Context, authInfo *Auth, oldHash string) error
 }
 
 type IAuthService interface {
-	Auth(ctx context.Context, authInfo *Auth) (string, error) // sing up if not exists
+	Auth(ctx context.Context, authInfo *Auth) (*TokenPair, error) // sing up if not exists and auto registration enabled
+	Register(ctx context.Context, authInfo *Auth) (*TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, logout *Logout) error
+	IsTokenRevoked(ctx context.Context, token *AccessToken) (bool, error)
+	ChangePassword(ctx context.Context, change *PasswordChange) (*TokenPair, error)
+	ResetPassword(ctx context.Context, authInfo *Auth) error // admin sets new password
 }
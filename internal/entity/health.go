@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+type HealthCheck struct {
+	Name     string
+	Status   string
+	Error    string // empty if check passed
+	Duration time.Duration
+}
+
+// HealthReport is ok only if all its checks are ok
+type HealthReport struct {
+	Status string
+	Checks []*HealthCheck
+}
+
+// SchemaVersion is state of migrations applied to database
+type SchemaVersion struct {
+	Version int64
+	Dirty   bool // last migration failed in the middle
+}
+
+type IHealthRepository interface {
+	Ping(ctx context.Context) error
+	GetSchemaVersion(ctx context.Context) (*SchemaVersion, error)
+}
+
+type IHealthService interface {
+	// Live reports that process is able to serve requests, dependencies are not checked
+	Live(ctx context.Context) *HealthReport
+	// Ready checks dependencies, not ready after SetShuttingDown
+	Ready(ctx context.Context) *HealthReport
+	SetShuttingDown()
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+type IdempotencyRecord struct {
+	Username    string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Response    []byte
+	ReservedAt  time.Time // set when key is reserved, identifies reservation
+}
+
+type IIdempotencyRepository interface {
+	// Reserve returns nil record if key was reserved for the caller, otherwise stored record.
+	// Key left in progress longer than lease is reserved again
+	Reserve(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error)
+	// InTransaction runs fn in transaction shared by repositories called with ctx passed to fn
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	SaveResponse(ctx context.Context, record *IdempotencyRecord) error
+	Release(ctx context.Context, record *IdempotencyRecord) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+type IIdempotencyService interface {
+	Reserve(ctx context.Context, record *IdempotencyRecord) (*IdempotencyRecord, error)
+	// Execute runs handle, which sets response of record, and saves the response in the same transaction
+	// with writes of handle, so key is completed if and only if the writes are committed
+	Execute(ctx context.Context, record *IdempotencyRecord, handle func(ctx context.Context) error) error
+	Release(ctx context.Context, record *IdempotencyRecord) error
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
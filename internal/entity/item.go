@@ -1,11 +1,20 @@
 package entity
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+const (
+	SortByPriceAsc  = "price_asc"
+	SortByPriceDesc = "price_desc"
+)
 
 type Item struct {
 	Name     string
 	Price    int32
 	Quantity int32
+	Stock    *int32 // nil means unlimited
 }
 
 type Purchase struct {
@@ -13,12 +22,80 @@ type Purchase struct {
 	ItemName string
 }
 
+// PurchaseRecord is a single bought unit with the price paid at purchase time
+type PurchaseRecord struct {
+	ID       string
+	Time     time.Time
+	ItemName string
+	Price    int32
+	Refunded bool
+}
+
+type Refund struct {
+	Username   string
+	PurchaseID string
+	Window     time.Duration // refund allowed only within window after purchase
+}
+
+// PurchasesCursor points to the last purchase of the previous page
+type PurchasesCursor struct {
+	Time time.Time
+	ID   string
+}
+
+type PurchasesFilter struct {
+	Username string
+	From     *time.Time
+	To       *time.Time
+	After    *PurchasesCursor
+	Limit    int32
+}
+
+type PurchasesPage struct {
+	Purchases []*PurchaseRecord
+	Next      *PurchasesCursor // nil on the last page
+}
+
+type CartLine struct {
+	ItemName string
+	Quantity int32
+}
+
+type Cart struct {
+	Username string
+	Lines    []*CartLine
+}
+
+type ItemsFilter struct {
+	MinPrice *int32
+	MaxPrice *int32
+	Sort     string // SortByPriceAsc, SortByPriceDesc or empty (order by name)
+}
+
 type IItemRepository interface {
 	GetInventory(ctx context.Context, username string) ([]*Item, error)
 	BuyItem(ctx context.Context, purchase *Purchase) error
+	BuyItems(ctx context.Context, cart *Cart) error
+	GetItems(ctx context.Context, filter *ItemsFilter) ([]*Item, error)
+	GetItem(ctx context.Context, name string) (*Item, error)
+	CreateItem(ctx context.Context, item *Item) error
+	UpdateItemPrice(ctx context.Context, item *Item) error
+	UpdateItemStock(ctx context.Context, item *Item) error
+	RetireItem(ctx context.Context, name string) error
+	GetPurchases(ctx context.Context, filter *PurchasesFilter) ([]*PurchaseRecord, error)
+	RefundPurchase(ctx context.Context, refund *Refund) (*PurchaseRecord, error)
 }
 
 type IItemService interface {
 	GetInventory(ctx context.Context, username string) ([]*Item, error)
 	BuyItem(ctx context.Context, purchase *Purchase) error
+	BuyItems(ctx context.Context, cart *Cart) error
+	GetItems(ctx context.Context, filter *ItemsFilter) ([]*Item, error)
+	GetItem(ctx context.Context, name string) (*Item, error)
+	CreateItem(ctx context.Context, item *Item) error
+	UpdateItemPrice(ctx context.Context, item *Item) error
+	UpdateItemStock(ctx context.Context, item *Item) error
+	RetireItem(ctx context.Context, name string) error
+	GetPurchases(ctx context.Context, filter *PurchasesFilter) (*PurchasesPage, error)
+	RefundPurchase(ctx context.Context, username string, purchaseID string) (*PurchaseRecord, error)
 }
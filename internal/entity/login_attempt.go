@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+type LoginAttempt struct {
+	ID       int64 // set when attempt is reserved
+	Username string
+	IP       string
+	Time     time.Time
+}
+
+// LoginFailures counts failed attempts since some time, zero last failure if there were none
+type LoginFailures struct {
+	UsernameCount       int
+	UsernameLastFailure time.Time
+	IPCount             int
+	IPLastFailure       time.Time
+}
+
+// BruteForcePolicy delays next login attempt by BaseDelay after Threshold failed attempts
+// within Window and doubles delay for every next failure, up to MaxDelay (lockout)
+type BruteForcePolicy struct {
+	Window            time.Duration
+	UsernameThreshold int // zero disables per username limit
+	IPThreshold       int // zero disables per ip limit
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+}
+
+type ILoginAttemptRepository interface {
+	// ReserveAttempt saves attempt as failure and returns failures saved before it since given time.
+	// Attempts with the same username or ip are serialized, so parallel attempts count each other
+	ReserveAttempt(ctx context.Context, attempt *LoginAttempt, since time.Time) (*LoginFailures, error)
+	DeleteAttempt(ctx context.Context, id int64) error
+	// ClearUsernameFailures stops counting failures for username, they still count for their ip
+	ClearUsernameFailures(ctx context.Context, username string) error
+	DeleteFailuresBefore(ctx context.Context, before time.Time) error
+}
+
+type ILoginAttemptService interface {
+	// Reserve counts attempt as failed before password is verified, so parallel attempts can not exceed limits.
+	// Returns errs.TooManyAttempts wrapped in *errs.RetryAfterError if attempt must be rejected
+	Reserve(ctx context.Context, attempt *LoginAttempt) error
+	// Release uncounts reserved attempt that failed not because of credentials
+	Release(ctx context.Context, attempt *LoginAttempt) error
+	Succeeded(ctx context.Context, attempt *LoginAttempt) error
+	// DeleteExpired removes failures out of window, it runs periodically instead of on every login
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+type RateLimitBudget struct {
+	Limit  int // zero means unlimited
+	Window time.Duration
+}
+
+// RouteRateLimit budget applies to requests with path equal to Path or nested in it
+type RouteRateLimit struct {
+	Path string
+	RateLimitBudget
+}
+
+// RateLimitPolicy uses the most specific route budget, otherwise Default
+type RateLimitPolicy struct {
+	Default RateLimitBudget
+	Routes  []RouteRateLimit
+}
+
+// RateLimitRequest is counted per Subject (username or ip) and matched budget
+type RateLimitRequest struct {
+	Subject string
+	Path    string
+	Time    time.Time
+}
+
+type RateLimitStatus struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+type IRateLimitRepository interface {
+	// Increment counts request for key in fixed window and returns requests count in the window
+	Increment(ctx context.Context, key string, windowStart time.Time) (int, error)
+	// DeleteWindowsBefore deletes counters of windows started before time
+	DeleteWindowsBefore(ctx context.Context, before time.Time) error
+}
+
+type IRateLimitService interface {
+	// Allow returns nil status if no budget applies to request
+	Allow(ctx context.Context, request *RateLimitRequest) (*RateLimitStatus, error)
+	// DeleteExpired deletes counters of windows ended before now for every budget
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
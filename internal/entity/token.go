@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// RefreshToken is stored only as hash, raw token is known to the client only
+type RefreshToken struct {
+	Username  string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+// RevokedToken is access token rejected until it expires
+type RevokedToken struct {
+	JTI       string
+	Username  string
+	ExpiresAt time.Time
+}
+
+// AccessToken is access token checked for revocation
+type AccessToken struct {
+	JTI      string
+	Username string
+	IssuedAt time.Time
+}
+
+type Logout struct {
+	Username     string
+	JTI          string
+	ExpiresAt    time.Time
+	RefreshToken string // optional, revoked with access token
+}
+
+type ITokenRepository interface {
+	SaveRefreshToken(ctx context.Context, token *RefreshToken) error
+	// RotateRefreshToken revokes token with oldHash, saves newToken for the same user and returns this user
+	RotateRefreshToken(ctx context.Context, oldHash string, newToken *RefreshToken) (*Auth, error)
+	RevokeRefreshToken(ctx context.Context, username string, tokenHash string) error
+	RevokeAccessToken(ctx context.Context, token *RevokedToken) error
+	// IsAccessTokenRevoked checks token revoked on logout or issued before user password change
+	IsAccessTokenRevoked(ctx context.Context, token *AccessToken) (bool, error)
+}
@@ -1,29 +1,86 @@
 package entity
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
 
 type User struct {
 	Username string
 	Coins    int32
 }
 
+// CoinsHistoryEntry is a transfer counterparty with transferred coins
+type CoinsHistoryEntry struct {
+	Username string
+	Coins    int32
+	Comment  string
+}
+
 type CoinsHistory struct {
-	Received []*User
-	Sent     []*User
+	Received []*CoinsHistoryEntry
+	Sent     []*CoinsHistoryEntry
 }
 
 type TransferCoins struct {
 	FromUser string
 	ToUser   string
 	Amount   int32
+	Comment  string // optional
+}
+
+// GrantCoins credits coins to user on behalf of operator,
+// it is kept in history as transaction without sender
+type GrantCoins struct {
+	Username string
+	Amount   int32
+	Comment  string // optional
+}
+
+type Transaction struct {
+	ID       string
+	Time     time.Time
+	FromUser string
+	ToUser   string
+	Amount   int32
+	Comment  string
+}
+
+// TransactionsCursor points to the last transaction of the previous page
+type TransactionsCursor struct {
+	Time time.Time
+	ID   string
+}
+
+type TransactionsFilter struct {
+	Username  string
+	Direction string // DirectionSent, DirectionReceived or empty for both
+	From      *time.Time
+	To        *time.Time
+	After     *TransactionsCursor
+	Limit     int32
+}
+
+type TransactionsPage struct {
+	Transactions []*Transaction
+	Next         *TransactionsCursor // nil on the last page
 }
 
 type IUserRepository interface {
 	SendCoins(ctx context.Context, transfer *TransferCoins) error
+	GrantCoins(ctx context.Context, grant *GrantCoins) error
 	GetCoinsHistory(ctx context.Context, username string) (int32, *CoinsHistory, error)
+	GetTransactions(ctx context.Context, filter *TransactionsFilter) ([]*Transaction, error)
 }
 
 type IUserService interface {
 	SendCoins(ctx context.Context, transfer *TransferCoins) error
+	GrantCoins(ctx context.Context, grant *GrantCoins) error // admin credits coins
 	GetCoinsHistory(ctx context.Context, username string) (int32, *CoinsHistory, error)
+	GetTransactions(ctx context.Context, filter *TransactionsFilter) (*TransactionsPage, error)
 }
@@ -64,6 +64,34 @@ func (mr *MockIAuthRepositoryMockRecorder) Register(ctx, authInfo interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockIAuthRepository)(nil).Register), ctx, authInfo)
 }
 
+// UpdatePassword mocks base method.
+func (m *MockIAuthRepository) UpdatePassword(ctx context.Context, authInfo *entity.Auth) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, authInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockIAuthRepositoryMockRecorder) UpdatePassword(ctx, authInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockIAuthRepository)(nil).UpdatePassword), ctx, authInfo)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockIAuthRepository) UpdatePasswordHash(ctx context.Context, authInfo *entity.Auth, oldHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, authInfo, oldHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockIAuthRepositoryMockRecorder) UpdatePasswordHash(ctx, authInfo, oldHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockIAuthRepository)(nil).UpdatePasswordHash), ctx, authInfo, oldHash)
+}
+
 // MockIAuthService is a mock of IAuthService interface.
 type MockIAuthService struct {
 	ctrl     *gomock.Controller
@@ -88,10 +116,10 @@ func (m *MockIAuthService) EXPECT() *MockIAuthServiceMockRecorder {
 }
 
 // Auth mocks base method.
-func (m *MockIAuthService) Auth(ctx context.Context, authInfo *entity.Auth) (string, error) {
+func (m *MockIAuthService) Auth(ctx context.Context, authInfo *entity.Auth) (*entity.TokenPair, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Auth", ctx, authInfo)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(*entity.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -101,3 +129,91 @@ func (mr *MockIAuthServiceMockRecorder) Auth(ctx, authInfo interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Auth", reflect.TypeOf((*MockIAuthService)(nil).Auth), ctx, authInfo)
 }
+
+// ChangePassword mocks base method.
+func (m *MockIAuthService) ChangePassword(ctx context.Context, change *entity.PasswordChange) (*entity.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, change)
+	ret0, _ := ret[0].(*entity.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockIAuthServiceMockRecorder) ChangePassword(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockIAuthService)(nil).ChangePassword), ctx, change)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockIAuthService) IsTokenRevoked(ctx context.Context, token *entity.AccessToken) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, token)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockIAuthServiceMockRecorder) IsTokenRevoked(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockIAuthService)(nil).IsTokenRevoked), ctx, token)
+}
+
+// Logout mocks base method.
+func (m *MockIAuthService) Logout(ctx context.Context, logout *entity.Logout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, logout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockIAuthServiceMockRecorder) Logout(ctx, logout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockIAuthService)(nil).Logout), ctx, logout)
+}
+
+// Refresh mocks base method.
+func (m *MockIAuthService) Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(*entity.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockIAuthServiceMockRecorder) Refresh(ctx, refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockIAuthService)(nil).Refresh), ctx, refreshToken)
+}
+
+// Register mocks base method.
+func (m *MockIAuthService) Register(ctx context.Context, authInfo *entity.Auth) (*entity.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, authInfo)
+	ret0, _ := ret[0].(*entity.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockIAuthServiceMockRecorder) Register(ctx, authInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockIAuthService)(nil).Register), ctx, authInfo)
+}
+
+// ResetPassword mocks base method.
+func (m *MockIAuthService) ResetPassword(ctx context.Context, authInfo *entity.Auth) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, authInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockIAuthServiceMockRecorder) ResetPassword(ctx, authInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockIAuthService)(nil).ResetPassword), ctx, authInfo)
+}
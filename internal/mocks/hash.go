@@ -48,6 +48,20 @@ func (mr *MockIHashCryptoMockRecorder) HashPassword(password interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashPassword", reflect.TypeOf((*MockIHashCrypto)(nil).HashPassword), password)
 }
 
+// NeedsRehash mocks base method.
+func (m *MockIHashCrypto) NeedsRehash(hash string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRehash", hash)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRehash indicates an expected call of NeedsRehash.
+func (mr *MockIHashCryptoMockRecorder) NeedsRehash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRehash", reflect.TypeOf((*MockIHashCrypto)(nil).NeedsRehash), hash)
+}
+
 // VerifyPassword mocks base method.
 func (m *MockIHashCrypto) VerifyPassword(password, hash string) bool {
 	m.ctrl.T.Helper()
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/health.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIHealthRepository is a mock of IHealthRepository interface.
+type MockIHealthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHealthRepositoryMockRecorder
+}
+
+// MockIHealthRepositoryMockRecorder is the mock recorder for MockIHealthRepository.
+type MockIHealthRepositoryMockRecorder struct {
+	mock *MockIHealthRepository
+}
+
+// NewMockIHealthRepository creates a new mock instance.
+func NewMockIHealthRepository(ctrl *gomock.Controller) *MockIHealthRepository {
+	mock := &MockIHealthRepository{ctrl: ctrl}
+	mock.recorder = &MockIHealthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHealthRepository) EXPECT() *MockIHealthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSchemaVersion mocks base method.
+func (m *MockIHealthRepository) GetSchemaVersion(ctx context.Context) (*entity.SchemaVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSchemaVersion", ctx)
+	ret0, _ := ret[0].(*entity.SchemaVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSchemaVersion indicates an expected call of GetSchemaVersion.
+func (mr *MockIHealthRepositoryMockRecorder) GetSchemaVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSchemaVersion", reflect.TypeOf((*MockIHealthRepository)(nil).GetSchemaVersion), ctx)
+}
+
+// Ping mocks base method.
+func (m *MockIHealthRepository) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockIHealthRepositoryMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockIHealthRepository)(nil).Ping), ctx)
+}
+
+// MockIHealthService is a mock of IHealthService interface.
+type MockIHealthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIHealthServiceMockRecorder
+}
+
+// MockIHealthServiceMockRecorder is the mock recorder for MockIHealthService.
+type MockIHealthServiceMockRecorder struct {
+	mock *MockIHealthService
+}
+
+// NewMockIHealthService creates a new mock instance.
+func NewMockIHealthService(ctrl *gomock.Controller) *MockIHealthService {
+	mock := &MockIHealthService{ctrl: ctrl}
+	mock.recorder = &MockIHealthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIHealthService) EXPECT() *MockIHealthServiceMockRecorder {
+	return m.recorder
+}
+
+// Live mocks base method.
+func (m *MockIHealthService) Live(ctx context.Context) *entity.HealthReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Live", ctx)
+	ret0, _ := ret[0].(*entity.HealthReport)
+	return ret0
+}
+
+// Live indicates an expected call of Live.
+func (mr *MockIHealthServiceMockRecorder) Live(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Live", reflect.TypeOf((*MockIHealthService)(nil).Live), ctx)
+}
+
+// Ready mocks base method.
+func (m *MockIHealthService) Ready(ctx context.Context) *entity.HealthReport {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(*entity.HealthReport)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockIHealthServiceMockRecorder) Ready(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockIHealthService)(nil).Ready), ctx)
+}
+
+// SetShuttingDown mocks base method.
+func (m *MockIHealthService) SetShuttingDown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetShuttingDown")
+}
+
+// SetShuttingDown indicates an expected call of SetShuttingDown.
+func (mr *MockIHealthServiceMockRecorder) SetShuttingDown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetShuttingDown", reflect.TypeOf((*MockIHealthService)(nil).SetShuttingDown))
+}
@@ -0,0 +1,188 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/idempotency.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIIdempotencyRepository is a mock of IIdempotencyRepository interface.
+type MockIIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdempotencyRepositoryMockRecorder
+}
+
+// MockIIdempotencyRepositoryMockRecorder is the mock recorder for MockIIdempotencyRepository.
+type MockIIdempotencyRepositoryMockRecorder struct {
+	mock *MockIIdempotencyRepository
+}
+
+// NewMockIIdempotencyRepository creates a new mock instance.
+func NewMockIIdempotencyRepository(ctrl *gomock.Controller) *MockIIdempotencyRepository {
+	mock := &MockIIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdempotencyRepository) EXPECT() *MockIIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIIdempotencyRepositoryMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIIdempotencyRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// InTransaction mocks base method.
+func (m *MockIIdempotencyRepository) InTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InTransaction indicates an expected call of InTransaction.
+func (mr *MockIIdempotencyRepositoryMockRecorder) InTransaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InTransaction", reflect.TypeOf((*MockIIdempotencyRepository)(nil).InTransaction), ctx, fn)
+}
+
+// Release mocks base method.
+func (m *MockIIdempotencyRepository) Release(ctx context.Context, record *entity.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIIdempotencyRepositoryMockRecorder) Release(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIIdempotencyRepository)(nil).Release), ctx, record)
+}
+
+// Reserve mocks base method.
+func (m *MockIIdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, record)
+	ret0, _ := ret[0].(*entity.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIIdempotencyRepositoryMockRecorder) Reserve(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIIdempotencyRepository)(nil).Reserve), ctx, record)
+}
+
+// SaveResponse mocks base method.
+func (m *MockIIdempotencyRepository) SaveResponse(ctx context.Context, record *entity.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResponse", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResponse indicates an expected call of SaveResponse.
+func (mr *MockIIdempotencyRepositoryMockRecorder) SaveResponse(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResponse", reflect.TypeOf((*MockIIdempotencyRepository)(nil).SaveResponse), ctx, record)
+}
+
+// MockIIdempotencyService is a mock of IIdempotencyService interface.
+type MockIIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdempotencyServiceMockRecorder
+}
+
+// MockIIdempotencyServiceMockRecorder is the mock recorder for MockIIdempotencyService.
+type MockIIdempotencyServiceMockRecorder struct {
+	mock *MockIIdempotencyService
+}
+
+// NewMockIIdempotencyService creates a new mock instance.
+func NewMockIIdempotencyService(ctrl *gomock.Controller) *MockIIdempotencyService {
+	mock := &MockIIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdempotencyService) EXPECT() *MockIIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIIdempotencyService) DeleteExpired(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIIdempotencyServiceMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIIdempotencyService)(nil).DeleteExpired), ctx, now)
+}
+
+// Execute mocks base method.
+func (m *MockIIdempotencyService) Execute(ctx context.Context, record *entity.IdempotencyRecord, handle func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, record, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockIIdempotencyServiceMockRecorder) Execute(ctx, record, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockIIdempotencyService)(nil).Execute), ctx, record, handle)
+}
+
+// Release mocks base method.
+func (m *MockIIdempotencyService) Release(ctx context.Context, record *entity.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIIdempotencyServiceMockRecorder) Release(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIIdempotencyService)(nil).Release), ctx, record)
+}
+
+// Reserve mocks base method.
+func (m *MockIIdempotencyService) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, record)
+	ret0, _ := ret[0].(*entity.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIIdempotencyServiceMockRecorder) Reserve(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIIdempotencyService)(nil).Reserve), ctx, record)
+}
@@ -49,6 +49,34 @@ func (mr *MockIItemRepositoryMockRecorder) BuyItem(ctx, purchase interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockIItemRepository)(nil).BuyItem), ctx, purchase)
 }
 
+// BuyItems mocks base method.
+func (m *MockIItemRepository) BuyItems(ctx context.Context, cart *entity.Cart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItems", ctx, cart)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuyItems indicates an expected call of BuyItems.
+func (mr *MockIItemRepositoryMockRecorder) BuyItems(ctx, cart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItems", reflect.TypeOf((*MockIItemRepository)(nil).BuyItems), ctx, cart)
+}
+
+// CreateItem mocks base method.
+func (m *MockIItemRepository) CreateItem(ctx context.Context, item *entity.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockIItemRepositoryMockRecorder) CreateItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockIItemRepository)(nil).CreateItem), ctx, item)
+}
+
 // GetInventory mocks base method.
 func (m *MockIItemRepository) GetInventory(ctx context.Context, username string) ([]*entity.Item, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +92,108 @@ func (mr *MockIItemRepositoryMockRecorder) GetInventory(ctx, username interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventory", reflect.TypeOf((*MockIItemRepository)(nil).GetInventory), ctx, username)
 }
 
+// GetItem mocks base method.
+func (m *MockIItemRepository) GetItem(ctx context.Context, name string) (*entity.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, name)
+	ret0, _ := ret[0].(*entity.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockIItemRepositoryMockRecorder) GetItem(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockIItemRepository)(nil).GetItem), ctx, name)
+}
+
+// GetItems mocks base method.
+func (m *MockIItemRepository) GetItems(ctx context.Context, filter *entity.ItemsFilter) ([]*entity.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, filter)
+	ret0, _ := ret[0].([]*entity.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockIItemRepositoryMockRecorder) GetItems(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockIItemRepository)(nil).GetItems), ctx, filter)
+}
+
+// GetPurchases mocks base method.
+func (m *MockIItemRepository) GetPurchases(ctx context.Context, filter *entity.PurchasesFilter) ([]*entity.PurchaseRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchases", ctx, filter)
+	ret0, _ := ret[0].([]*entity.PurchaseRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchases indicates an expected call of GetPurchases.
+func (mr *MockIItemRepositoryMockRecorder) GetPurchases(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchases", reflect.TypeOf((*MockIItemRepository)(nil).GetPurchases), ctx, filter)
+}
+
+// RefundPurchase mocks base method.
+func (m *MockIItemRepository) RefundPurchase(ctx context.Context, refund *entity.Refund) (*entity.PurchaseRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundPurchase", ctx, refund)
+	ret0, _ := ret[0].(*entity.PurchaseRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundPurchase indicates an expected call of RefundPurchase.
+func (mr *MockIItemRepositoryMockRecorder) RefundPurchase(ctx, refund interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPurchase", reflect.TypeOf((*MockIItemRepository)(nil).RefundPurchase), ctx, refund)
+}
+
+// RetireItem mocks base method.
+func (m *MockIItemRepository) RetireItem(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockIItemRepositoryMockRecorder) RetireItem(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockIItemRepository)(nil).RetireItem), ctx, name)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockIItemRepository) UpdateItemPrice(ctx context.Context, item *entity.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockIItemRepositoryMockRecorder) UpdateItemPrice(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockIItemRepository)(nil).UpdateItemPrice), ctx, item)
+}
+
+// UpdateItemStock mocks base method.
+func (m *MockIItemRepository) UpdateItemStock(ctx context.Context, item *entity.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemStock", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemStock indicates an expected call of UpdateItemStock.
+func (mr *MockIItemRepositoryMockRecorder) UpdateItemStock(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemStock", reflect.TypeOf((*MockIItemRepository)(nil).UpdateItemStock), ctx, item)
+}
+
 // MockIItemService is a mock of IItemService interface.
 type MockIItemService struct {
 	ctrl     *gomock.Controller
@@ -101,6 +231,34 @@ func (mr *MockIItemServiceMockRecorder) BuyItem(ctx, purchase interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItem", reflect.TypeOf((*MockIItemService)(nil).BuyItem), ctx, purchase)
 }
 
+// BuyItems mocks base method.
+func (m *MockIItemService) BuyItems(ctx context.Context, cart *entity.Cart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyItems", ctx, cart)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BuyItems indicates an expected call of BuyItems.
+func (mr *MockIItemServiceMockRecorder) BuyItems(ctx, cart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyItems", reflect.TypeOf((*MockIItemService)(nil).BuyItems), ctx, cart)
+}
+
+// CreateItem mocks base method.
+func (m *MockIItemService) CreateItem(ctx context.Context, item *entity.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockIItemServiceMockRecorder) CreateItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockIItemService)(nil).CreateItem), ctx, item)
+}
+
 // GetInventory mocks base method.
 func (m *MockIItemService) GetInventory(ctx context.Context, username string) ([]*entity.Item, error) {
 	m.ctrl.T.Helper()
@@ -115,3 +273,105 @@ func (mr *MockIItemServiceMockRecorder) GetInventory(ctx, username interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventory", reflect.TypeOf((*MockIItemService)(nil).GetInventory), ctx, username)
 }
+
+// GetItem mocks base method.
+func (m *MockIItemService) GetItem(ctx context.Context, name string) (*entity.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItem", ctx, name)
+	ret0, _ := ret[0].(*entity.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItem indicates an expected call of GetItem.
+func (mr *MockIItemServiceMockRecorder) GetItem(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItem", reflect.TypeOf((*MockIItemService)(nil).GetItem), ctx, name)
+}
+
+// GetItems mocks base method.
+func (m *MockIItemService) GetItems(ctx context.Context, filter *entity.ItemsFilter) ([]*entity.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, filter)
+	ret0, _ := ret[0].([]*entity.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockIItemServiceMockRecorder) GetItems(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockIItemService)(nil).GetItems), ctx, filter)
+}
+
+// GetPurchases mocks base method.
+func (m *MockIItemService) GetPurchases(ctx context.Context, filter *entity.PurchasesFilter) (*entity.PurchasesPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchases", ctx, filter)
+	ret0, _ := ret[0].(*entity.PurchasesPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchases indicates an expected call of GetPurchases.
+func (mr *MockIItemServiceMockRecorder) GetPurchases(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchases", reflect.TypeOf((*MockIItemService)(nil).GetPurchases), ctx, filter)
+}
+
+// RefundPurchase mocks base method.
+func (m *MockIItemService) RefundPurchase(ctx context.Context, username, purchaseID string) (*entity.PurchaseRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundPurchase", ctx, username, purchaseID)
+	ret0, _ := ret[0].(*entity.PurchaseRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundPurchase indicates an expected call of RefundPurchase.
+func (mr *MockIItemServiceMockRecorder) RefundPurchase(ctx, username, purchaseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPurchase", reflect.TypeOf((*MockIItemService)(nil).RefundPurchase), ctx, username, purchaseID)
+}
+
+// RetireItem mocks base method.
+func (m *MockIItemService) RetireItem(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireItem", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireItem indicates an expected call of RetireItem.
+func (mr *MockIItemServiceMockRecorder) RetireItem(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireItem", reflect.TypeOf((*MockIItemService)(nil).RetireItem), ctx, name)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockIItemService) UpdateItemPrice(ctx context.Context, item *entity.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockIItemServiceMockRecorder) UpdateItemPrice(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockIItemService)(nil).UpdateItemPrice), ctx, item)
+}
+
+// UpdateItemStock mocks base method.
+func (m *MockIItemService) UpdateItemStock(ctx context.Context, item *entity.Item) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemStock", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemStock indicates an expected call of UpdateItemStock.
+func (mr *MockIItemServiceMockRecorder) UpdateItemStock(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemStock", reflect.TypeOf((*MockIItemService)(nil).UpdateItemStock), ctx, item)
+}
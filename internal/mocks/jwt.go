@@ -5,9 +5,11 @@
 package mocks
 
 import (
+	jwt "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/jwt"
 	reflect "reflect"
+	time "time"
 
-	jwt "github.com/golang-jwt/jwt/v5"
+	jwt0 "github.com/golang-jwt/jwt/v5"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,26 +36,71 @@ func (m *MockITokenManager) EXPECT() *MockITokenManagerMockRecorder {
 	return m.recorder
 }
 
+// CreateRefreshToken mocks base method.
+func (m *MockITokenManager) CreateRefreshToken() (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockITokenManagerMockRecorder) CreateRefreshToken() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockITokenManager)(nil).CreateRefreshToken))
+}
+
 // CreateToken mocks base method.
-func (m *MockITokenManager) CreateToken(username string) (string, error) {
+func (m *MockITokenManager) CreateToken(username, role string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateToken", username)
+	ret := m.ctrl.Call(m, "CreateToken", username, role)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateToken indicates an expected call of CreateToken.
-func (mr *MockITokenManagerMockRecorder) CreateToken(username interface{}) *gomock.Call {
+func (mr *MockITokenManagerMockRecorder) CreateToken(username, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockITokenManager)(nil).CreateToken), username, role)
+}
+
+// Keyfunc mocks base method.
+func (m *MockITokenManager) Keyfunc(token *jwt0.Token) (interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keyfunc", token)
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Keyfunc indicates an expected call of Keyfunc.
+func (mr *MockITokenManagerMockRecorder) Keyfunc(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keyfunc", reflect.TypeOf((*MockITokenManager)(nil).Keyfunc), token)
+}
+
+// PublicKeys mocks base method.
+func (m *MockITokenManager) PublicKeys() []jwt.PublicKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys")
+	ret0, _ := ret[0].([]jwt.PublicKey)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockITokenManagerMockRecorder) PublicKeys() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateToken", reflect.TypeOf((*MockITokenManager)(nil).CreateToken), username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockITokenManager)(nil).PublicKeys))
 }
 
 // VerifyToken mocks base method.
-func (m *MockITokenManager) VerifyToken(tokenString string) (*jwt.Token, error) {
+func (m *MockITokenManager) VerifyToken(tokenString string) (*jwt0.Token, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "VerifyToken", tokenString)
-	ret0, _ := ret[0].(*jwt.Token)
+	ret0, _ := ret[0].(*jwt0.Token)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -1,6 +1,9 @@
 package mocks
 
-import "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"context"
+)
 
 type mockLogger struct{}
 
@@ -17,3 +20,11 @@ func (m *mockLogger) Errorf(message string, args ...interface{}) {}
 func (m *mockLogger) Fatalf(message string, args ...interface{}) {}
 
 func (m *mockLogger) Debugf(message string, args ...interface{}) {}
+
+func (m *mockLogger) WithContext(ctx context.Context) logger.ILogger {
+	return m
+}
+
+func (m *mockLogger) WithFields(fields map[string]interface{}) logger.ILogger {
+	return m
+}
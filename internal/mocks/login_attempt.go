@@ -0,0 +1,173 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/login_attempt.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockILoginAttemptRepository is a mock of ILoginAttemptRepository interface.
+type MockILoginAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockILoginAttemptRepositoryMockRecorder
+}
+
+// MockILoginAttemptRepositoryMockRecorder is the mock recorder for MockILoginAttemptRepository.
+type MockILoginAttemptRepositoryMockRecorder struct {
+	mock *MockILoginAttemptRepository
+}
+
+// NewMockILoginAttemptRepository creates a new mock instance.
+func NewMockILoginAttemptRepository(ctrl *gomock.Controller) *MockILoginAttemptRepository {
+	mock := &MockILoginAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockILoginAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILoginAttemptRepository) EXPECT() *MockILoginAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClearUsernameFailures mocks base method.
+func (m *MockILoginAttemptRepository) ClearUsernameFailures(ctx context.Context, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearUsernameFailures", ctx, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearUsernameFailures indicates an expected call of ClearUsernameFailures.
+func (mr *MockILoginAttemptRepositoryMockRecorder) ClearUsernameFailures(ctx, username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearUsernameFailures", reflect.TypeOf((*MockILoginAttemptRepository)(nil).ClearUsernameFailures), ctx, username)
+}
+
+// DeleteAttempt mocks base method.
+func (m *MockILoginAttemptRepository) DeleteAttempt(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAttempt", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAttempt indicates an expected call of DeleteAttempt.
+func (mr *MockILoginAttemptRepositoryMockRecorder) DeleteAttempt(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAttempt", reflect.TypeOf((*MockILoginAttemptRepository)(nil).DeleteAttempt), ctx, id)
+}
+
+// DeleteFailuresBefore mocks base method.
+func (m *MockILoginAttemptRepository) DeleteFailuresBefore(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFailuresBefore", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFailuresBefore indicates an expected call of DeleteFailuresBefore.
+func (mr *MockILoginAttemptRepositoryMockRecorder) DeleteFailuresBefore(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFailuresBefore", reflect.TypeOf((*MockILoginAttemptRepository)(nil).DeleteFailuresBefore), ctx, before)
+}
+
+// ReserveAttempt mocks base method.
+func (m *MockILoginAttemptRepository) ReserveAttempt(ctx context.Context, attempt *entity.LoginAttempt, since time.Time) (*entity.LoginFailures, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveAttempt", ctx, attempt, since)
+	ret0, _ := ret[0].(*entity.LoginFailures)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveAttempt indicates an expected call of ReserveAttempt.
+func (mr *MockILoginAttemptRepositoryMockRecorder) ReserveAttempt(ctx, attempt, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveAttempt", reflect.TypeOf((*MockILoginAttemptRepository)(nil).ReserveAttempt), ctx, attempt, since)
+}
+
+// MockILoginAttemptService is a mock of ILoginAttemptService interface.
+type MockILoginAttemptService struct {
+	ctrl     *gomock.Controller
+	recorder *MockILoginAttemptServiceMockRecorder
+}
+
+// MockILoginAttemptServiceMockRecorder is the mock recorder for MockILoginAttemptService.
+type MockILoginAttemptServiceMockRecorder struct {
+	mock *MockILoginAttemptService
+}
+
+// NewMockILoginAttemptService creates a new mock instance.
+func NewMockILoginAttemptService(ctrl *gomock.Controller) *MockILoginAttemptService {
+	mock := &MockILoginAttemptService{ctrl: ctrl}
+	mock.recorder = &MockILoginAttemptServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILoginAttemptService) EXPECT() *MockILoginAttemptServiceMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockILoginAttemptService) DeleteExpired(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockILoginAttemptServiceMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockILoginAttemptService)(nil).DeleteExpired), ctx, now)
+}
+
+// Release mocks base method.
+func (m *MockILoginAttemptService) Release(ctx context.Context, attempt *entity.LoginAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockILoginAttemptServiceMockRecorder) Release(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockILoginAttemptService)(nil).Release), ctx, attempt)
+}
+
+// Reserve mocks base method.
+func (m *MockILoginAttemptService) Reserve(ctx context.Context, attempt *entity.LoginAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockILoginAttemptServiceMockRecorder) Reserve(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockILoginAttemptService)(nil).Reserve), ctx, attempt)
+}
+
+// Succeeded mocks base method.
+func (m *MockILoginAttemptService) Succeeded(ctx context.Context, attempt *entity.LoginAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Succeeded", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Succeeded indicates an expected call of Succeeded.
+func (mr *MockILoginAttemptServiceMockRecorder) Succeeded(ctx, attempt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Succeeded", reflect.TypeOf((*MockILoginAttemptService)(nil).Succeeded), ctx, attempt)
+}
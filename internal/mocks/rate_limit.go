@@ -0,0 +1,118 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/rate_limit.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRateLimitRepository is a mock of IRateLimitRepository interface.
+type MockIRateLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRateLimitRepositoryMockRecorder
+}
+
+// MockIRateLimitRepositoryMockRecorder is the mock recorder for MockIRateLimitRepository.
+type MockIRateLimitRepositoryMockRecorder struct {
+	mock *MockIRateLimitRepository
+}
+
+// NewMockIRateLimitRepository creates a new mock instance.
+func NewMockIRateLimitRepository(ctrl *gomock.Controller) *MockIRateLimitRepository {
+	mock := &MockIRateLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockIRateLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRateLimitRepository) EXPECT() *MockIRateLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteWindowsBefore mocks base method.
+func (m *MockIRateLimitRepository) DeleteWindowsBefore(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWindowsBefore", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWindowsBefore indicates an expected call of DeleteWindowsBefore.
+func (mr *MockIRateLimitRepositoryMockRecorder) DeleteWindowsBefore(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWindowsBefore", reflect.TypeOf((*MockIRateLimitRepository)(nil).DeleteWindowsBefore), ctx, before)
+}
+
+// Increment mocks base method.
+func (m *MockIRateLimitRepository) Increment(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, windowStart)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockIRateLimitRepositoryMockRecorder) Increment(ctx, key, windowStart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockIRateLimitRepository)(nil).Increment), ctx, key, windowStart)
+}
+
+// MockIRateLimitService is a mock of IRateLimitService interface.
+type MockIRateLimitService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRateLimitServiceMockRecorder
+}
+
+// MockIRateLimitServiceMockRecorder is the mock recorder for MockIRateLimitService.
+type MockIRateLimitServiceMockRecorder struct {
+	mock *MockIRateLimitService
+}
+
+// NewMockIRateLimitService creates a new mock instance.
+func NewMockIRateLimitService(ctrl *gomock.Controller) *MockIRateLimitService {
+	mock := &MockIRateLimitService{ctrl: ctrl}
+	mock.recorder = &MockIRateLimitServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRateLimitService) EXPECT() *MockIRateLimitServiceMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockIRateLimitService) Allow(ctx context.Context, request *entity.RateLimitRequest) (*entity.RateLimitStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, request)
+	ret0, _ := ret[0].(*entity.RateLimitStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockIRateLimitServiceMockRecorder) Allow(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockIRateLimitService)(nil).Allow), ctx, request)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockIRateLimitService) DeleteExpired(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockIRateLimitServiceMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockIRateLimitService)(nil).DeleteExpired), ctx, now)
+}
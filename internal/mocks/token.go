@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/entity/token.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	entity "Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockITokenRepository is a mock of ITokenRepository interface.
+type MockITokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockITokenRepositoryMockRecorder
+}
+
+// MockITokenRepositoryMockRecorder is the mock recorder for MockITokenRepository.
+type MockITokenRepositoryMockRecorder struct {
+	mock *MockITokenRepository
+}
+
+// NewMockITokenRepository creates a new mock instance.
+func NewMockITokenRepository(ctrl *gomock.Controller) *MockITokenRepository {
+	mock := &MockITokenRepository{ctrl: ctrl}
+	mock.recorder = &MockITokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITokenRepository) EXPECT() *MockITokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// IsAccessTokenRevoked mocks base method.
+func (m *MockITokenRepository) IsAccessTokenRevoked(ctx context.Context, token *entity.AccessToken) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsAccessTokenRevoked", ctx, token)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsAccessTokenRevoked indicates an expected call of IsAccessTokenRevoked.
+func (mr *MockITokenRepositoryMockRecorder) IsAccessTokenRevoked(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAccessTokenRevoked", reflect.TypeOf((*MockITokenRepository)(nil).IsAccessTokenRevoked), ctx, token)
+}
+
+// RevokeAccessToken mocks base method.
+func (m *MockITokenRepository) RevokeAccessToken(ctx context.Context, token *entity.RevokedToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccessToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAccessToken indicates an expected call of RevokeAccessToken.
+func (mr *MockITokenRepositoryMockRecorder) RevokeAccessToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessToken", reflect.TypeOf((*MockITokenRepository)(nil).RevokeAccessToken), ctx, token)
+}
+
+// RevokeRefreshToken mocks base method.
+func (m *MockITokenRepository) RevokeRefreshToken(ctx context.Context, username, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshToken", ctx, username, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshToken indicates an expected call of RevokeRefreshToken.
+func (mr *MockITokenRepositoryMockRecorder) RevokeRefreshToken(ctx, username, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshToken", reflect.TypeOf((*MockITokenRepository)(nil).RevokeRefreshToken), ctx, username, tokenHash)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockITokenRepository) RotateRefreshToken(ctx context.Context, oldHash string, newToken *entity.RefreshToken) (*entity.Auth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, oldHash, newToken)
+	ret0, _ := ret[0].(*entity.Auth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockITokenRepositoryMockRecorder) RotateRefreshToken(ctx, oldHash, newToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockITokenRepository)(nil).RotateRefreshToken), ctx, oldHash, newToken)
+}
+
+// SaveRefreshToken mocks base method.
+func (m *MockITokenRepository) SaveRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRefreshToken indicates an expected call of SaveRefreshToken.
+func (mr *MockITokenRepositoryMockRecorder) SaveRefreshToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockITokenRepository)(nil).SaveRefreshToken), ctx, token)
+}
@@ -51,6 +51,35 @@ func (mr *MockIUserRepositoryMockRecorder) GetCoinsHistory(ctx, username interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinsHistory", reflect.TypeOf((*MockIUserRepository)(nil).GetCoinsHistory), ctx, username)
 }
 
+// GetTransactions mocks base method.
+func (m *MockIUserRepository) GetTransactions(ctx context.Context, filter *entity.TransactionsFilter) ([]*entity.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, filter)
+	ret0, _ := ret[0].([]*entity.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockIUserRepositoryMockRecorder) GetTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockIUserRepository)(nil).GetTransactions), ctx, filter)
+}
+
+// GrantCoins mocks base method.
+func (m *MockIUserRepository) GrantCoins(ctx context.Context, grant *entity.GrantCoins) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantCoins", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantCoins indicates an expected call of GrantCoins.
+func (mr *MockIUserRepositoryMockRecorder) GrantCoins(ctx, grant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantCoins", reflect.TypeOf((*MockIUserRepository)(nil).GrantCoins), ctx, grant)
+}
+
 // SendCoins mocks base method.
 func (m *MockIUserRepository) SendCoins(ctx context.Context, transfer *entity.TransferCoins) error {
 	m.ctrl.T.Helper()
@@ -104,6 +133,35 @@ func (mr *MockIUserServiceMockRecorder) GetCoinsHistory(ctx, username interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinsHistory", reflect.TypeOf((*MockIUserService)(nil).GetCoinsHistory), ctx, username)
 }
 
+// GetTransactions mocks base method.
+func (m *MockIUserService) GetTransactions(ctx context.Context, filter *entity.TransactionsFilter) (*entity.TransactionsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, filter)
+	ret0, _ := ret[0].(*entity.TransactionsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockIUserServiceMockRecorder) GetTransactions(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockIUserService)(nil).GetTransactions), ctx, filter)
+}
+
+// GrantCoins mocks base method.
+func (m *MockIUserService) GrantCoins(ctx context.Context, grant *entity.GrantCoins) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantCoins", ctx, grant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrantCoins indicates an expected call of GrantCoins.
+func (mr *MockIUserServiceMockRecorder) GrantCoins(ctx, grant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantCoins", reflect.TypeOf((*MockIUserService)(nil).GrantCoins), ctx, grant)
+}
+
 // SendCoins mocks base method.
 func (m *MockIUserService) SendCoins(ctx context.Context, transfer *entity.TransferCoins) error {
 	m.ctrl.T.Helper()
@@ -1,50 +1,363 @@
 package config
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type Config struct {
-	Logger   LoggerConfig   `yaml:"logger"`
-	HTTP     HTTPConfig     `yaml:"http"`
-	Database PostgresConfig `yaml:"database"`
-	Jwt      Jwt            `yaml:"jwt"`
+	Logger    LoggerConfig    `mapstructure:"logger"`
+	HTTP      HTTPConfig      `mapstructure:"http"`
+	Database  PostgresConfig  `mapstructure:"database"`
+	Jwt       Jwt             `mapstructure:"jwt"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	Shop      ShopConfig      `mapstructure:"shop"`
+	Hash      HashConfig      `mapstructure:"hash"`
+	RateLimit RateLimitConfig `mapstructure:"rateLimit"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	Tracing   TracingConfig   `mapstructure:"tracing"`
 }
 
 type LoggerConfig struct {
-	Level string `yaml:"level"`
-	File  string `yaml:"file"`
+	Level string `mapstructure:"level"`
+	File  string `mapstructure:"file"`
 }
 
 type HTTPConfig struct {
-	Port int `yaml:"port"`
+	Port int `mapstructure:"port"`
+	// ShutdownDelay is how long /readyz reports not ready before server stops accepting connections,
+	// so load balancer stops sending new requests
+	ShutdownDelay      time.Duration `mapstructure:"shutdownDelay"`
+	HealthCheckTimeout time.Duration `mapstructure:"healthCheckTimeout"` // per readiness check, zero means no timeout
 }
 
 type PostgresConfig struct {
-	Driver   string `yaml:"driver"`
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	User     string `yaml:"username"`
-	Password string `yaml:"password"`
-	DBName   string `yaml:"dbname"`
+	Driver   string `mapstructure:"driver"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	DBName   string `mapstructure:"dbname"`
+	// MigrateOnStart applies embedded migrations before serving, otherwise they are applied by migrate subcommand
+	MigrateOnStart bool `mapstructure:"migrateOnStart"`
 }
 
 type Jwt struct {
-	Key string `yaml:"key"`
+	Key         string             `mapstructure:"key"`         // HS256 secret, only verifies old tokens if signing keys are set
+	SigningKeys []SigningKeyConfig `mapstructure:"signingKeys"` // first key signs new tokens, others only verify
+	AccessTTL   time.Duration      `mapstructure:"accessTTL"`
+	RefreshTTL  time.Duration      `mapstructure:"refreshTTL"`
+}
+
+// SigningKeyConfig is PEM encoded RSA (RS256) or Ed25519 (EdDSA) private key
+type SigningKeyConfig struct {
+	KID  string `mapstructure:"kid"`
+	File string `mapstructure:"file"`
+	PEM  string `mapstructure:"pem"` // used if file is empty
+}
+
+type AuthConfig struct {
+	AutoRegister bool             `mapstructure:"autoRegister"` // register unknown users on /api/auth
+	BruteForce   BruteForceConfig `mapstructure:"bruteForce"`
+}
+
+// BruteForceConfig delays login after threshold failed attempts within window,
+// delay starts from baseDelay and doubles for every next failure up to maxDelay
+type BruteForceConfig struct {
+	Window            time.Duration `mapstructure:"window"`
+	UsernameThreshold int           `mapstructure:"usernameThreshold"` // zero disables per username limit
+	IPThreshold       int           `mapstructure:"ipThreshold"`       // zero disables per ip limit
+	BaseDelay         time.Duration `mapstructure:"baseDelay"`
+	MaxDelay          time.Duration `mapstructure:"maxDelay"`
+}
+
+type ShopConfig struct {
+	RefundWindow time.Duration `mapstructure:"refundWindow"` // zero disables refunds
+}
+
+type HashConfig struct {
+	Algorithm  string       `mapstructure:"algorithm"`  // bcrypt (default) or argon2id
+	BcryptCost int          `mapstructure:"bcryptCost"` // 4-31
+	Argon2     Argon2Config `mapstructure:"argon2"`
+}
+
+type Argon2Config struct {
+	Time    uint32 `mapstructure:"time"`
+	Memory  uint32 `mapstructure:"memory"` // KiB
+	Threads uint8  `mapstructure:"threads"`
+}
+
+// RateLimitConfig default budget applies to routes without own budget, zero limit disables it
+type RateLimitConfig struct {
+	Store  string                 `mapstructure:"store"` // postgres (default, shared by prefork processes) or memory
+	Limit  int                    `mapstructure:"limit"`
+	Window time.Duration          `mapstructure:"window"`
+	Routes []RouteRateLimitConfig `mapstructure:"routes"`
+}
+
+// RouteRateLimitConfig budget applies to path and paths nested in it
+type RouteRateLimitConfig struct {
+	Path   string        `mapstructure:"path"`
+	Limit  int           `mapstructure:"limit"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+// MetricsConfig dir is where prefork processes share metrics snapshots,
+// empty dir means /metrics returns metrics of the process that handled the scrape only
+type MetricsConfig struct {
+	Dir              string        `mapstructure:"dir"`
+	SnapshotInterval time.Duration `mapstructure:"snapshotInterval"`
+}
+
+// TracingConfig exporter is none (default), stdout, file (stdout format written to file,
+// works offline) or otlp (OTLP over HTTP to collector)
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"`
+	File        string  `mapstructure:"file"`
+	Endpoint    string  `mapstructure:"endpoint"`    // otlp collector url, e.g. http://otel-collector:4318
+	ServiceName string  `mapstructure:"serviceName"` // avito-shop by default
+	SampleRatio float64 `mapstructure:"sampleRatio"` // share of traced requests started here, zero means all
 }
 
+// ReadConfig reads YAML file at configPath (optional, skipped if empty) over defaults,
+// then applies environment overrides (see env.go) and validates the result
 func ReadConfig(configPath string) (*Config, error) {
-	var config Config
-	viper.SetConfigFile(configPath)
+	config := defaultConfig()
+	if configPath != "" {
+		v := viper.New()
+		v.SetConfigFile(configPath)
+		err := v.ReadInConfig()
+		if err != nil {
+			return nil, err
+		}
+		// unknown keys are rejected, so misspelled option is not silently ignored
+		err = v.UnmarshalExact(config)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	err := viper.ReadInConfig()
-	if err != nil {
-		return nil, err
+	verr := &ValidationError{}
+	applyEnv(reflect.ValueOf(config).Elem(), EnvPrefix, "", verr)
+	config.validate(verr)
+	if len(verr.Fields) > 0 {
+		return nil, verr
 	}
-	err = viper.Unmarshal(&config)
-	if err != nil {
-		return nil, err
+	return config, nil
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Logger: LoggerConfig{
+			Level: "info",
+			File:  "logs.log",
+		},
+		HTTP: HTTPConfig{
+			Port:               8080,
+			ShutdownDelay:      5 * time.Second,
+			HealthCheckTimeout: 2 * time.Second,
+		},
+		Database: PostgresConfig{
+			Driver: "postgres",
+			Host:   "localhost",
+			Port:   5432,
+		},
+		Jwt: Jwt{
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: 720 * time.Hour,
+		},
+		Auth: AuthConfig{
+			AutoRegister: true,
+			BruteForce: BruteForceConfig{
+				Window:            15 * time.Minute,
+				UsernameThreshold: 5,
+				IPThreshold:       20,
+				BaseDelay:         time.Second,
+				MaxDelay:          15 * time.Minute,
+			},
+		},
+		Hash: HashConfig{
+			Algorithm:  "bcrypt",
+			BcryptCost: bcrypt.DefaultCost,
+			Argon2: Argon2Config{
+				Time:    2,
+				Memory:  19456,
+				Threads: 1,
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Store: "postgres",
+		},
+		Metrics: MetricsConfig{
+			SnapshotInterval: 5 * time.Second,
+		},
+		Tracing: TracingConfig{
+			Exporter: "none",
+		},
+	}
+}
+
+// ValidationError lists every invalid field, so all of them are fixed at once
+type ValidationError struct {
+	Fields []string // "field: reason"
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config: %s", strings.Join(e.Fields, "; "))
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, fmt.Sprintf("%s: %s", field, fmt.Sprintf(format, args...)))
+}
+
+func (e *ValidationError) nonNegative(field string, d time.Duration) {
+	if d < 0 {
+		e.add(field, "must not be negative")
 	}
+}
 
-	return &config, nil
+func (c *Config) validate(e *ValidationError) {
+	switch c.Logger.Level {
+	case "error", "warn", "info", "debug":
+	default:
+		e.add("logger.level", "unknown level \"%s\"", c.Logger.Level)
+	}
+	if c.Logger.File == "" {
+		e.add("logger.file", "must not be empty")
+	}
+
+	if c.HTTP.Port < 1 || c.HTTP.Port > 65535 {
+		e.add("http.port", "must be 1-65535")
+	}
+	e.nonNegative("http.shutdownDelay", c.HTTP.ShutdownDelay)
+	e.nonNegative("http.healthCheckTimeout", c.HTTP.HealthCheckTimeout)
+
+	c.Database.validate(e)
+	c.Jwt.validate(e)
+	c.Auth.BruteForce.validate(e)
+	e.nonNegative("shop.refundWindow", c.Shop.RefundWindow)
+	c.Hash.validate(e)
+	c.RateLimit.validate(e)
+	if c.Metrics.Dir != "" && c.Metrics.SnapshotInterval <= 0 {
+		e.add("metrics.snapshotInterval", "must be positive if dir is set")
+	}
+	c.Tracing.validate(e)
+}
+
+func (c PostgresConfig) validate(e *ValidationError) {
+	required := []struct {
+		field string
+		value string
+	}{
+		{"database.driver", c.Driver},
+		{"database.host", c.Host},
+		{"database.user", c.User},
+		{"database.dbname", c.DBName},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			e.add(r.field, "must not be empty")
+		}
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		e.add("database.port", "must be 1-65535")
+	}
+}
+
+func (c Jwt) validate(e *ValidationError) {
+	if c.Key == "" && len(c.SigningKeys) == 0 {
+		e.add("jwt.key", "key or signing keys must be set")
+	}
+	for i, key := range c.SigningKeys {
+		if key.KID == "" {
+			e.add(fmt.Sprintf("jwt.signingKeys[%d].kid", i), "must not be empty")
+		}
+		if key.File == "" && key.PEM == "" {
+			e.add(fmt.Sprintf("jwt.signingKeys[%d].file", i), "file or pem must be set")
+		}
+	}
+	e.nonNegative("jwt.accessTTL", c.AccessTTL)
+	e.nonNegative("jwt.refreshTTL", c.RefreshTTL)
+}
+
+func (c BruteForceConfig) validate(e *ValidationError) {
+	if c.UsernameThreshold < 0 {
+		e.add("auth.bruteForce.usernameThreshold", "must not be negative")
+	}
+	if c.IPThreshold < 0 {
+		e.add("auth.bruteForce.ipThreshold", "must not be negative")
+	}
+	if c.UsernameThreshold == 0 && c.IPThreshold == 0 {
+		return
+	}
+	if c.Window <= 0 {
+		e.add("auth.bruteForce.window", "must be positive")
+	}
+	if c.BaseDelay <= 0 {
+		e.add("auth.bruteForce.baseDelay", "must be positive")
+	}
+	if c.MaxDelay < c.BaseDelay {
+		e.add("auth.bruteForce.maxDelay", "must not be less than base delay")
+	}
+}
+
+func (c HashConfig) validate(e *ValidationError) {
+	switch c.Algorithm {
+	case "", "bcrypt", "argon2id":
+	default:
+		e.add("hash.algorithm", "unknown algorithm \"%s\"", c.Algorithm)
+	}
+	// bcrypt silently replaces cost below minimal with default one
+	if c.BcryptCost != 0 && (c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost) {
+		e.add("hash.bcryptCost", "must be %d-%d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+}
+
+func (c RateLimitConfig) validate(e *ValidationError) {
+	switch c.Store {
+	case "", "postgres", "memory":
+	default:
+		e.add("rateLimit.store", "unknown store \"%s\"", c.Store)
+	}
+	if c.Limit < 0 {
+		e.add("rateLimit.limit", "must not be negative")
+	}
+	if c.Limit > 0 && c.Window <= 0 {
+		e.add("rateLimit.window", "must be positive if limit is set")
+	}
+	for i, route := range c.Routes {
+		if route.Path == "" {
+			e.add(fmt.Sprintf("rateLimit.routes[%d].path", i), "must not be empty")
+		}
+		if route.Limit < 0 {
+			e.add(fmt.Sprintf("rateLimit.routes[%d].limit", i), "must not be negative")
+		}
+		if route.Limit > 0 && route.Window <= 0 {
+			e.add(fmt.Sprintf("rateLimit.routes[%d].window", i), "must be positive if limit is set")
+		}
+	}
+}
+
+func (c TracingConfig) validate(e *ValidationError) {
+	switch c.Exporter {
+	case "", "none", "stdout":
+	case "file":
+		if c.File == "" {
+			e.add("tracing.file", "must not be empty for file exporter")
+		}
+	case "otlp":
+		if c.Endpoint == "" {
+			e.add("tracing.endpoint", "must not be empty for otlp exporter")
+		}
+	default:
+		e.add("tracing.exporter", "unknown exporter \"%s\"", c.Exporter)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		e.add("tracing.sampleRatio", "must be 0-1")
+	}
 }
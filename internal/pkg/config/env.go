@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// EnvPrefix starts names of variables overriding config fields. Name is built from key path
+// in snake case, e.g. AVITO_SHOP_DATABASE_PASSWORD for database.password and
+// AVITO_SHOP_AUTH_BRUTE_FORCE_MAX_DELAY for auth.bruteForce.maxDelay. Slice elements are
+// addressed by index: AVITO_SHOP_RATE_LIMIT_ROUTES_0_PATH. Variable with _FILE suffix
+// (AVITO_SHOP_JWT_KEY_FILE) reads value from file, e.g. docker or kubernetes secret
+const EnvPrefix = "AVITO_SHOP"
+
+const envFileSuffix = "_FILE"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// envName converts camel case key to upper snake case, acronyms are kept together (accessTTL -> ACCESS_TTL)
+func envName(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// applyEnv overrides fields of struct v with environment variables, path is key path for errors
+func applyEnv(v reflect.Value, name, path string, verr *ValidationError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		applyEnvValue(v.Field(i), name+"_"+envName(key), fieldPath, verr)
+	}
+}
+
+func applyEnvValue(v reflect.Value, name, path string, verr *ValidationError) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		applyEnv(v, name, path, verr)
+		return
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		// elements are extended while variables of the next index exist
+		for i := 0; i < v.Len() || hasEnvPrefix(fmt.Sprintf("%s_%d_", name, i)); i++ {
+			if i >= v.Len() {
+				v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+			}
+			applyEnv(v.Index(i), fmt.Sprintf("%s_%d", name, i), fmt.Sprintf("%s[%d]", path, i), verr)
+		}
+		return
+	}
+
+	raw, ok, err := lookupEnv(name)
+	if err != nil {
+		verr.add(path, "%v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	err = setValue(v, raw)
+	if err != nil {
+		verr.add(path, "%s: %v", name, err)
+	}
+}
+
+// lookupEnv returns value of variable name or content of file named by name_FILE
+func lookupEnv(name string) (string, bool, error) {
+	raw, ok := os.LookupEnv(name)
+	file, fileOk := os.LookupEnv(name + envFileSuffix)
+	if !fileOk {
+		return raw, ok, nil
+	}
+	if ok {
+		return "", false, fmt.Errorf("both %s and %s%s are set", name, name, envFileSuffix)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", false, fmt.Errorf("%s%s: %w", name, envFileSuffix, err)
+	}
+	// editors and echo add trailing newline, it is never part of secret
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+func hasEnvPrefix(prefix string) bool {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Codes are stable identifiers of failures, clients branch on them instead of messages
+const (
+	CodeInvalidData        = "invalid_data"
+	CodeInternalError      = "internal_error"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeInvalidToken       = "invalid_token"
+	CodeTokenRevoked       = "token_revoked"
+	CodeNotEnoughCoins     = "not_enough_coins"
+	CodeUserNotFound       = "user_not_found"
+	CodeItemNotFound       = "item_not_found"
+	CodeUserAlreadyExists  = "user_already_exists"
+	CodeItemAlreadyExists  = "item_already_exists"
+	CodeOutOfStock         = "out_of_stock"
+	CodeKeyReused          = "idempotency_key_reused"
+	CodeRequestInProgress  = "request_in_progress"
+	CodePermissionDenied   = "permission_denied"
+	CodePurchaseNotFound   = "purchase_not_found"
+	CodeAlreadyRefunded    = "already_refunded"
+	CodeRefundExpired      = "refund_expired"
+	CodeTooManyAttempts    = "too_many_attempts"
+	CodeRateLimitExceeded  = "rate_limit_exceeded"
+)
+
+type kind struct {
+	err    error
+	code   string
+	status int
+}
+
+// kinds are default codes and statuses of sentinel errors, handlers may override status for their context
+var kinds = []kind{
+	{InvalidData, CodeInvalidData, http.StatusBadRequest},
+	{InvalidCredentials, CodeInvalidCredentials, http.StatusUnauthorized},
+	{InvalidToken, CodeInvalidToken, http.StatusUnauthorized},
+	{TokenRevoked, CodeTokenRevoked, http.StatusUnauthorized},
+	{NotEnoughCoins, CodeNotEnoughCoins, http.StatusBadRequest},
+	{UserNotFound, CodeUserNotFound, http.StatusNotFound},
+	{ItemNotFound, CodeItemNotFound, http.StatusNotFound},
+	{UserAlreadyExists, CodeUserAlreadyExists, http.StatusConflict},
+	{ItemAlreadyExists, CodeItemAlreadyExists, http.StatusConflict},
+	{OutOfStock, CodeOutOfStock, http.StatusConflict},
+	{KeyReused, CodeKeyReused, http.StatusUnprocessableEntity},
+	{RequestInProgress, CodeRequestInProgress, http.StatusConflict},
+	{PermissionDenied, CodePermissionDenied, http.StatusForbidden},
+	{PurchaseNotFound, CodePurchaseNotFound, http.StatusNotFound},
+	{AlreadyRefunded, CodeAlreadyRefunded, http.StatusConflict},
+	{RefundExpired, CodeRefundExpired, http.StatusConflict},
+	{TooManyAttempts, CodeTooManyAttempts, http.StatusTooManyRequests},
+	{RateLimitExceeded, CodeRateLimitExceeded, http.StatusTooManyRequests},
+}
+
+// Error is failure reported to client: Code and Status are stable, Message is human readable.
+// Cause is kept for logs and errors.Is/As, but is never sent to client
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	cause   error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// New describes err of operation named by prompt for client. Known errors get their code and status,
+// any other error is reported as internal error without details
+func New(prompt string, err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	res := &Error{
+		Code:    CodeInternalError,
+		Status:  http.StatusInternalServerError,
+		Message: InternalError.Error(),
+		cause:   err,
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			res.Code = k.code
+			res.Status = k.status
+			res.Message = err.Error()
+			break
+		}
+	}
+	if prompt != "" {
+		res.Message = fmt.Sprintf("%s: %s", prompt, res.Message)
+	}
+	return res
+}
+
+// NewStatus reports failure not caused by domain errors (unknown route, too large body, ...),
+// code is derived from status text
+func NewStatus(status int, message string) *Error {
+	res := &Error{
+		Code:    strings.ToLower(strings.NewReplacer(" ", "_", "-", "_").Replace(http.StatusText(status))),
+		Status:  status,
+		Message: message,
+	}
+	if status >= http.StatusInternalServerError {
+		res.Code = CodeInternalError
+		res.Message = InternalError.Error()
+	}
+	return res
+}
+
+// WithStatus overrides status if error is caused by any of targets
+func (e *Error) WithStatus(status int, targets ...error) *Error {
+	for _, target := range targets {
+		if errors.Is(e.cause, target) {
+			res := *e
+			res.Status = status
+			return &res
+		}
+	}
+	return e
+}
@@ -1,6 +1,9 @@
 package errs
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const (
 	UniqueConstraintSQLState = "23505"
@@ -10,8 +13,34 @@ var (
 	InvalidData        = fmt.Errorf("invalid data")
 	InternalError      = fmt.Errorf("internal error")
 	InvalidCredentials = fmt.Errorf("invalid credentials")
+	InvalidToken       = fmt.Errorf("invalid token")
+	TokenRevoked       = fmt.Errorf("token revoked")
 	NotEnoughCoins     = fmt.Errorf("not enough coins")
 	UserNotFound       = fmt.Errorf("user not found")
 	ItemNotFound       = fmt.Errorf("item not found")
 	UserAlreadyExists  = fmt.Errorf("user already exists")
+	ItemAlreadyExists  = fmt.Errorf("item already exists")
+	OutOfStock         = fmt.Errorf("item out of stock")
+	KeyReused          = fmt.Errorf("idempotency key reused with different request")
+	RequestInProgress  = fmt.Errorf("request with same idempotency key in progress")
+	PermissionDenied   = fmt.Errorf("permission denied")
+	PurchaseNotFound   = fmt.Errorf("purchase not found")
+	AlreadyRefunded    = fmt.Errorf("purchase already refunded")
+	RefundExpired      = fmt.Errorf("refund window expired")
+	TooManyAttempts    = fmt.Errorf("too many failed attempts")
+	RateLimitExceeded  = fmt.Errorf("rate limit exceeded")
 )
+
+// RetryAfterError tells when request rejected with Err may be retried
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
@@ -1,26 +1,85 @@
 package jwt
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-const hashCost = bcrypt.MinCost
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// defaults for zero config values, argon2id ones are OWASP recommendation
+const (
+	defaultBcryptCost     = bcrypt.DefaultCost
+	defaultArgon2Time     = 2
+	defaultArgon2Memory   = 19 * 1024 // KiB
+	defaultArgon2Threads  = 1
+	defaultArgon2KeyLen   = 32
+	defaultArgon2SaltLen  = 16
+	argon2idHashPrefix    = "$argon2id$"
+	argon2idHashPartCount = 6 // "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+)
 
 type IHashCrypto interface {
 	HashPassword(password string) (string, error)
 	VerifyPassword(password, hash string) bool
+	// NeedsRehash reports whether hash was created by other algorithm or with other parameters
+	NeedsRehash(hash string) bool
+}
+
+type Argon2Params struct {
+	Time    uint32
+	Memory  uint32 // KiB
+	Threads uint8
+}
+
+type HashParams struct {
+	Algorithm  string // AlgorithmBcrypt (default) or AlgorithmArgon2id
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// HashCrypto produces self-describing hashes (modular crypt format),
+// so hashes made with any supported algorithm and parameters can be verified
+type HashCrypto struct {
+	params HashParams
 }
 
-type HashCrypto struct{}
+func NewHashCrypto(params HashParams) IHashCrypto {
+	if params.Algorithm == "" {
+		params.Algorithm = AlgorithmBcrypt
+	}
+	if params.BcryptCost == 0 {
+		params.BcryptCost = defaultBcryptCost
+	}
+	if params.Argon2.Time == 0 {
+		params.Argon2.Time = defaultArgon2Time
+	}
+	if params.Argon2.Memory == 0 {
+		params.Argon2.Memory = defaultArgon2Memory
+	}
+	if params.Argon2.Threads == 0 {
+		params.Argon2.Threads = defaultArgon2Threads
+	}
 
-func NewHashCrypto() IHashCrypto {
-	return HashCrypto{}
+	return HashCrypto{
+		params: params,
+	}
 }
 
 func (c HashCrypto) HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), hashCost)
+	if c.params.Algorithm == AlgorithmArgon2id {
+		return c.hashArgon2id(password)
+	}
+
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), c.params.BcryptCost)
 	if err != nil {
 		return "", fmt.Errorf("generating hash: %w", err)
 	}
@@ -28,6 +87,89 @@ func (c HashCrypto) HashPassword(password string) (string, error) {
 }
 
 func (c HashCrypto) VerifyPassword(password string, hash string) bool {
+	if strings.HasPrefix(hash, argon2idHashPrefix) {
+		params, salt, key, err := decodeArgon2idHash(hash)
+		if err != nil {
+			return false
+		}
+		otherKey := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, otherKey) == 1
+	}
+
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+func (c HashCrypto) NeedsRehash(hash string) bool {
+	if strings.HasPrefix(hash, argon2idHashPrefix) {
+		if c.params.Algorithm != AlgorithmArgon2id {
+			return true
+		}
+		params, _, _, err := decodeArgon2idHash(hash)
+		return err != nil || params != c.params.Argon2
+	}
+
+	if c.params.Algorithm != AlgorithmBcrypt {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != c.params.BcryptCost
+}
+
+// hashArgon2id encodes hash as $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+func (c HashCrypto) hashArgon2id(password string) (string, error) {
+	salt, err := randomBytes(defaultArgon2SaltLen)
+	if err != nil {
+		return "", fmt.Errorf("generating salt: %w", err)
+	}
+
+	p := c.params.Argon2
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, defaultArgon2KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idHashPrefix,
+		argon2.Version,
+		p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func decodeArgon2idHash(hash string) (params Argon2Params, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != argon2idHashPartCount {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash format")
+	}
+
+	var version int
+	_, err = fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("parsing argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads)
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("parsing argon2id parameters: %w", err)
+	}
+	// argon2 panics on zero time or threads
+	if params.Memory == 0 || params.Time == 0 || params.Threads == 0 {
+		return params, nil, nil, fmt.Errorf("zero argon2id parameter")
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("decoding argon2id salt: %w", err)
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("decoding argon2id key: %w", err)
+	}
+	if len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("empty argon2id key")
+	}
+
+	return params, salt, key, nil
+}
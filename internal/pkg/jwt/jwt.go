@@ -1,48 +1,85 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	jtiBytes          = 16
+	refreshTokenBytes = 32
+)
+
 type ITokenManager interface {
-	CreateToken(username string) (string, error)
+	CreateToken(username, role string) (string, error)
+	// CreateRefreshToken returns opaque random token and its expiration time
+	CreateRefreshToken() (string, time.Time, error)
 	VerifyToken(tokenString string) (*jwt.Token, error)
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	PublicKeys() []PublicKey
 }
 
 type TokenManager struct {
-	jwtKey string
+	keys       *KeySet
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
-func NewTokenManager(jwtKey string) ITokenManager {
+// NewTokenManager uses default TTL for zero accessTTL or refreshTTL
+func NewTokenManager(keys *KeySet, accessTTL, refreshTTL time.Duration) ITokenManager {
+	if accessTTL <= 0 {
+		accessTTL = defaultAccessTokenTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = defaultRefreshTokenTTL
+	}
 	return &TokenManager{
-		jwtKey: jwtKey,
+		keys:       keys,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
 	}
 }
 
-func (m *TokenManager) CreateToken(username string) (string, error) {
-	token := jwt.NewWithClaims(
-		jwt.SigningMethodHS256,
-		jwt.MapClaims{
-			"sub": username,
-			"iss": "AvitoShop",
-			"exp": time.Now().Add(time.Hour * 24).Unix(),
-			"iat": time.Now().Unix(),
-		})
+func (m *TokenManager) CreateToken(username, role string) (string, error) {
+	jti, err := randomBytes(jtiBytes)
+	if err != nil {
+		return "", fmt.Errorf("generating token id: %w", err)
+	}
 
-	tokenString, err := token.SignedString([]byte(m.jwtKey))
+	now := time.Now()
+	tokenString, err := m.keys.sign(jwt.MapClaims{
+		"sub":  username,
+		"role": role,
+		"jti":  hex.EncodeToString(jti),
+		"iss":  "AvitoShop",
+		"exp":  now.Add(m.accessTTL).Unix(),
+		// microseconds, so that tokens issued just before and just after password change are told apart
+		"iat": float64(now.UnixMicro()) / 1e6,
+	})
 	if err != nil {
 		return "", fmt.Errorf("signing token: %w", err)
 	}
 	return tokenString, nil
 }
 
+func (m *TokenManager) CreateRefreshToken() (string, time.Time, error) {
+	token, err := randomBytes(refreshTokenBytes)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("generating refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(token), time.Now().Add(m.refreshTTL), nil
+}
+
 func (m *TokenManager) VerifyToken(tokenString string) (*jwt.Token, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(m.jwtKey), nil
-	})
+	token, err := jwt.Parse(tokenString, m.keys.Keyfunc)
 	if err != nil {
 		return nil, fmt.Errorf("parsing token: %w", err)
 	}
@@ -52,3 +89,20 @@ func (m *TokenManager) VerifyToken(tokenString string) (*jwt.Token, error) {
 
 	return token, nil
 }
+
+func (m *TokenManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	return m.keys.Keyfunc(token)
+}
+
+func (m *TokenManager) PublicKeys() []PublicKey {
+	return m.keys.PublicKeys()
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,134 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is RSA (RS256) or Ed25519 (EdDSA) private key identified by kid
+type SigningKey struct {
+	KID    string
+	Key    crypto.Signer
+	Method jwt.SigningMethod
+}
+
+// PublicKey is verification key published in JWKS
+type PublicKey struct {
+	KID string
+	Alg string
+	Key crypto.PublicKey
+}
+
+// ParseSigningKey parses PEM encoded PKCS#8 (RSA or Ed25519) or PKCS#1 (RSA) private key
+func ParseSigningKey(kid string, pemData []byte) (*SigningKey, error) {
+	if kid == "" {
+		return nil, fmt.Errorf("empty kid")
+	}
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data", kid)
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM type \"%s\"", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: parsing private key: %w", kid, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{KID: kid, Key: k, Method: jwt.SigningMethodRS256}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{KID: kid, Key: k, Method: jwt.SigningMethodEdDSA}, nil
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T", kid, key)
+	}
+}
+
+// KeySet signs new tokens with the first signing key (or HS256 secret if there are no signing keys)
+// and verifies tokens signed by any of its keys, so keys can be rotated by adding new key first
+// and removing old one after all its tokens expire
+type KeySet struct {
+	secret      []byte
+	signingKeys []*SigningKey
+	byKID       map[string]*SigningKey
+}
+
+// NewKeySet with both secret and signing keys still accepts HS256 tokens without kid,
+// so switching from HS256 does not invalidate already issued tokens
+func NewKeySet(secret string, signingKeys ...*SigningKey) (*KeySet, error) {
+	if secret == "" && len(signingKeys) == 0 {
+		return nil, fmt.Errorf("no keys")
+	}
+
+	set := &KeySet{
+		secret:      []byte(secret),
+		signingKeys: signingKeys,
+		byKID:       make(map[string]*SigningKey, len(signingKeys)),
+	}
+	for _, key := range signingKeys {
+		if _, ok := set.byKID[key.KID]; ok {
+			return nil, fmt.Errorf("duplicate kid %s", key.KID)
+		}
+		set.byKID[key.KID] = key
+	}
+	return set, nil
+}
+
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	if len(s.signingKeys) == 0 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	}
+
+	key := s.signingKeys[0]
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.KID
+	return token.SignedString(key.Key)
+}
+
+// Keyfunc checks that token algorithm matches the key, preventing algorithm confusion
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		if len(s.secret) == 0 || token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return s.secret, nil
+	}
+
+	key, ok := s.byKID[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %s", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v for kid %s", token.Header["alg"], kid)
+	}
+	return key.Key.Public(), nil
+}
+
+// PublicKeys returns verification keys of asymmetric signing keys, HS256 secret is never published
+func (s *KeySet) PublicKeys() []PublicKey {
+	keys := make([]PublicKey, 0, len(s.signingKeys))
+	for _, key := range s.signingKeys {
+		keys = append(keys, PublicKey{
+			KID: key.KID,
+			Alg: key.Method.Alg(),
+			Key: key.Key.Public(),
+		})
+	}
+	return keys
+}
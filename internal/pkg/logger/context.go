@@ -0,0 +1,32 @@
+package logger
+
+import "context"
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	usernameKey
+)
+
+// WithRequestID returns ctx carrying request id, it is added to every record of logger.WithContext(ctx)
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns empty string if ctx has no request id
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithUsername returns ctx carrying authenticated user, it is added to every record of logger.WithContext(ctx)
+func WithUsername(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, usernameKey, username)
+}
+
+// UsernameFromContext returns empty string if request is not authenticated
+func UsernameFromContext(ctx context.Context) string {
+	username, _ := ctx.Value(usernameKey).(string)
+	return username
+}
@@ -1,10 +1,12 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"os"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -20,6 +22,10 @@ type ILogger interface {
 	Errorf(message string, args ...interface{})
 	Fatalf(message string, args ...interface{})
 	Debugf(message string, args ...interface{})
+	// WithContext returns logger adding request id, username and trace id from ctx to every record
+	WithContext(ctx context.Context) ILogger
+	// WithFields returns logger adding fields to every record
+	WithFields(fields map[string]interface{}) ILogger
 }
 
 type Logger struct {
@@ -72,3 +78,27 @@ func (l *Logger) Fatalf(message string, args ...interface{}) {
 func (l *Logger) Debugf(message string, args ...interface{}) {
 	l.logger.Debug().Msgf(message, args...)
 }
+
+func (l *Logger) WithContext(ctx context.Context) ILogger {
+	fields := l.logger.With()
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = fields.Str("request_id", requestID)
+	}
+	if username := UsernameFromContext(ctx); username != "" {
+		fields = fields.Str("username", username)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields = fields.Str("trace_id", spanContext.TraceID().String())
+	}
+	logger := fields.Logger()
+	return &Logger{
+		logger: &logger,
+	}
+}
+
+func (l *Logger) WithFields(fields map[string]interface{}) ILogger {
+	logger := l.logger.With().Fields(fields).Logger()
+	return &Logger{
+		logger: &logger,
+	}
+}
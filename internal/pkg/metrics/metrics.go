@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// failureReasons are errors reported as failure reason label, others are reported as internal error
+var failureReasons = []error{
+	errs.InvalidData,
+	errs.InvalidCredentials,
+	errs.InvalidToken,
+	errs.NotEnoughCoins,
+	errs.UserNotFound,
+	errs.ItemNotFound,
+	errs.UserAlreadyExists,
+	errs.ItemAlreadyExists,
+	errs.OutOfStock,
+	errs.PermissionDenied,
+	errs.PurchaseNotFound,
+	errs.AlreadyRefunded,
+	errs.RefundExpired,
+}
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequestDuration *prometheus.HistogramVec
+	Purchases           *prometheus.CounterVec
+	CoinsTransferred    prometheus.Counter
+	Failures            *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		HTTPRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route and status.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"method", "route", "status"}),
+		Purchases: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "shop_purchases_total",
+			Help: "Purchased items units by item.",
+		}, []string{"item"}),
+		CoinsTransferred: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "shop_coins_transferred_total",
+			Help: "Coins sent between users.",
+		}),
+		Failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "shop_failures_total",
+			Help: "Failed business operations by operation and reason.",
+		}, []string{"operation", "reason"}),
+	}
+	m.registry.MustRegister(m.HTTPRequestDuration, m.Purchases, m.CoinsTransferred, m.Failures)
+	return m
+}
+
+// RegisterPool exports pool statistics, collected on every scrape
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(newPoolCollector(pool))
+}
+
+// ObserveFailure does nothing for nil err
+func (m *Metrics) ObserveFailure(operation string, err error) {
+	if err == nil {
+		return
+	}
+	reason := errs.InternalError
+	for _, failureReason := range failureReasons {
+		if errors.Is(err, failureReason) {
+			reason = failureReason
+			break
+		}
+	}
+	m.Failures.WithLabelValues(operation, reason.Error()).Inc()
+}
+
+// Gather returns metrics of current process only, see Snapshots for prefork
+func (m *Metrics) Gather() ([]*dto.MetricFamily, error) {
+	return m.registry.Gather()
+}
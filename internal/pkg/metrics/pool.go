@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+}
+
+func newPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	return &poolCollector{
+		pool: pool,
+		acquiredConns: prometheus.NewDesc("pgxpool_acquired_conns",
+			"Currently acquired connections.", nil, nil),
+		idleConns: prometheus.NewDesc("pgxpool_idle_conns",
+			"Currently idle connections.", nil, nil),
+		totalConns: prometheus.NewDesc("pgxpool_total_conns",
+			"Total connections in the pool.", nil, nil),
+		maxConns: prometheus.NewDesc("pgxpool_max_conns",
+			"Maximum size of the pool.", nil, nil),
+		acquireCount: prometheus.NewDesc("pgxpool_acquire_total",
+			"Successful connection acquires.", nil, nil),
+		acquireDuration: prometheus.NewDesc("pgxpool_acquire_duration_seconds_total",
+			"Total time spent acquiring connections.", nil, nil),
+		emptyAcquireCount: prometheus.NewDesc("pgxpool_empty_acquire_total",
+			"Acquires that waited for a connection because the pool was empty.", nil, nil),
+		canceledAcquireCount: prometheus.NewDesc("pgxpool_canceled_acquire_total",
+			"Acquires canceled by context.", nil, nil),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireCount
+	ch <- c.canceledAcquireCount
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue,
+		float64(stat.CanceledAcquireCount()))
+}
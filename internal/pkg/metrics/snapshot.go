@@ -0,0 +1,249 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	snapshotExt = ".prom"
+	// staleSnapshotIntervals snapshot not replaced for this many write intervals belongs to exited process
+	staleSnapshotIntervals = 3
+)
+
+// Snapshots shares metrics between prefork processes: every process periodically writes
+// its metrics to <dir>/<pid>.prom, scrape handled by any process merges own metrics
+// with snapshots of the others, so counters and histograms are summed over all processes.
+// Snapshots of exited processes are not merged, so restarted children are not counted twice
+type Snapshots struct {
+	metrics    *Metrics
+	dir        string
+	pid        int
+	staleAfter time.Duration
+}
+
+// NewSnapshots interval is period of Run, snapshots older than a few intervals are considered stale
+func NewSnapshots(metrics *Metrics, dir string, interval time.Duration) *Snapshots {
+	return &Snapshots{
+		metrics:    metrics,
+		dir:        dir,
+		pid:        os.Getpid(),
+		staleAfter: staleSnapshotIntervals * interval,
+	}
+}
+
+// Reset removes snapshots of previous runs, must be called by prefork master before children start
+func (s *Snapshots) Reset() error {
+	err := os.RemoveAll(s.dir)
+	if err != nil {
+		return fmt.Errorf("removing snapshots dir: %w", err)
+	}
+	return os.MkdirAll(s.dir, 0o755)
+}
+
+// Run writes snapshot every interval until ctx is done, final snapshot is written on exit
+func (s *Snapshots) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Write(); err != nil {
+				onError(err)
+			}
+			return
+		case <-ticker.C:
+			if err := s.Write(); err != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Write atomically replaces snapshot of current process
+func (s *Snapshots) Write() error {
+	families, err := s.metrics.Gather()
+	if err != nil {
+		return fmt.Errorf("gathering metrics: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, strconv.Itoa(s.pid)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("creating snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	encoder := expfmt.NewEncoder(tmp, expfmt.NewFormat(expfmt.TypeProtoDelim))
+	for _, family := range families {
+		err = encoder.Encode(family)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("encoding snapshot: %w", err)
+		}
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), filepath.Join(s.dir, strconv.Itoa(s.pid)+snapshotExt))
+	if err != nil {
+		return fmt.Errorf("replacing snapshot: %w", err)
+	}
+	return nil
+}
+
+// Gather returns metrics of current process merged with snapshots of other processes
+func (s *Snapshots) Gather() ([]*dto.MetricFamily, error) {
+	families, err := s.metrics.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*"+snapshotExt))
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	own := strconv.Itoa(s.pid) + snapshotExt
+	for _, path := range paths {
+		if filepath.Base(path) == own {
+			continue
+		}
+		if s.isStale(path) {
+			// best effort, snapshot may be removed by another process meanwhile
+			_ = os.Remove(path)
+			continue
+		}
+		snapshot, err := readSnapshot(path)
+		if err != nil {
+			return nil, err
+		}
+		families = Merge(families, snapshot)
+	}
+	return families, nil
+}
+
+// isStale reports whether snapshot was not replaced for staleAfter, its process is not alive then
+func (s *Snapshots) isStale(path string) bool {
+	if s.staleAfter <= 0 {
+		return false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false // missing snapshot is skipped by readSnapshot
+	}
+	return time.Since(info.ModTime()) > s.staleAfter
+}
+
+func readSnapshot(path string) ([]*dto.MetricFamily, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) { // process replaced its snapshot meanwhile
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening snapshot: %w", err)
+	}
+	defer file.Close()
+
+	var families []*dto.MetricFamily
+	decoder := expfmt.NewDecoder(file, expfmt.NewFormat(expfmt.TypeProtoDelim))
+	for {
+		family := &dto.MetricFamily{}
+		err = decoder.Decode(family)
+		if errors.Is(err, io.EOF) {
+			return families, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding snapshot %s: %w", path, err)
+		}
+		families = append(families, family)
+	}
+}
+
+// Merge adds metrics of other to families: counters, gauges and untyped values are summed,
+// histograms are summed bucket by bucket, metrics are matched by name and labels
+func Merge(families, other []*dto.MetricFamily) []*dto.MetricFamily {
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	for _, otherFamily := range other {
+		family, ok := byName[otherFamily.GetName()]
+		if !ok {
+			byName[otherFamily.GetName()] = otherFamily
+			families = append(families, otherFamily)
+			continue
+		}
+		if family.GetType() != otherFamily.GetType() {
+			continue
+		}
+
+		byLabels := make(map[string]*dto.Metric, len(family.Metric))
+		for _, metric := range family.Metric {
+			byLabels[labelsKey(metric)] = metric
+		}
+		for _, otherMetric := range otherFamily.Metric {
+			metric, ok := byLabels[labelsKey(otherMetric)]
+			if !ok {
+				byLabels[labelsKey(otherMetric)] = otherMetric
+				family.Metric = append(family.Metric, otherMetric)
+				continue
+			}
+			mergeMetric(family.GetType(), metric, otherMetric)
+		}
+	}
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].GetName() < families[j].GetName()
+	})
+	return families
+}
+
+func mergeMetric(metricType dto.MetricType, metric, other *dto.Metric) {
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		metric.Counter.Value = proto.Float64(metric.GetCounter().GetValue() + other.GetCounter().GetValue())
+	case dto.MetricType_GAUGE:
+		metric.Gauge.Value = proto.Float64(metric.GetGauge().GetValue() + other.GetGauge().GetValue())
+	case dto.MetricType_UNTYPED:
+		metric.Untyped.Value = proto.Float64(metric.GetUntyped().GetValue() + other.GetUntyped().GetValue())
+	case dto.MetricType_HISTOGRAM:
+		h, o := metric.GetHistogram(), other.GetHistogram()
+		h.SampleCount = proto.Uint64(h.GetSampleCount() + o.GetSampleCount())
+		h.SampleSum = proto.Float64(h.GetSampleSum() + o.GetSampleSum())
+
+		byBound := make(map[float64]*dto.Bucket, len(h.Bucket))
+		for _, bucket := range h.Bucket {
+			byBound[bucket.GetUpperBound()] = bucket
+		}
+		for _, otherBucket := range o.Bucket {
+			bucket, ok := byBound[otherBucket.GetUpperBound()]
+			if !ok {
+				continue // buckets are the same in all processes
+			}
+			bucket.CumulativeCount = proto.Uint64(bucket.GetCumulativeCount() + otherBucket.GetCumulativeCount())
+		}
+	default: // summaries quantiles can not be merged
+	}
+}
+
+func labelsKey(metric *dto.Metric) string {
+	key := ""
+	for _, label := range metric.Label {
+		key += label.GetName() + "=" + label.GetValue() + "\xff"
+	}
+	return key
+}
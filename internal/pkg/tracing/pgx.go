@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RowsAffectedKey = attribute.Key("db.rows_affected")
+
+// QueryTracer creates span for every query, including begin/commit of transactions,
+// so time spent waiting for row locks is visible as long query span.
+// Query arguments are not recorded as they may contain password hashes and tokens
+type QueryTracer struct{}
+
+func NewQueryTracer() pgx.QueryTracer {
+	return QueryTracer{}
+}
+
+func (t QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn,
+	data pgx.TraceQueryStartData,
+) context.Context {
+	operation := queryOperation(data.SQL)
+	ctx, _ = Tracer().Start(ctx, "postgres "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (t QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(RowsAffectedKey.Int64(data.CommandTag.RowsAffected()))
+	}
+	End(span, data.Err)
+}
+
+func queryOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "query"
+	}
+	return strings.ToLower(fields[0])
+}
@@ -6,24 +6,47 @@ import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/jwt"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"regexp"
+	"sync"
+	"unicode"
 )
 
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72 // bcrypt ignores bytes after 72
+)
+
+// dummyPassword hash is verified for unknown users, so response time does not tell whether username exists
+const dummyPassword = "dummy-password-0"
+
+var usernameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,32}$`)
+
 type AuthService struct {
 	logger       logger.ILogger
 	authRepo     entity.IAuthRepository
+	tokenRepo    entity.ITokenRepository
 	hasher       jwt.IHashCrypto
 	tokenManager jwt.ITokenManager
+	autoRegister bool
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
-func NewAuthService(repo entity.IAuthRepository, logger logger.ILogger,
-	hasher jwt.IHashCrypto, tokenManager jwt.ITokenManager,
+func NewAuthService(repo entity.IAuthRepository, tokenRepo entity.ITokenRepository, logger logger.ILogger,
+	hasher jwt.IHashCrypto, tokenManager jwt.ITokenManager, autoRegister bool,
 ) entity.IAuthService {
 	return &AuthService{
 		logger:       logger,
 		authRepo:     repo,
+		tokenRepo:    tokenRepo,
 		hasher:       hasher,
 		tokenManager: tokenManager,
+		autoRegister: autoRegister,
 	}
 }
 
@@ -40,54 +63,358 @@ func isValid(authInfo *entity.Auth) error {
 	return nil
 }
 
-func (s *AuthService) Auth(ctx context.Context, authInfo *entity.Auth) (string, error) {
+func (s *AuthService) Auth(ctx context.Context, authInfo *entity.Auth) (*entity.TokenPair, error) {
 	err := isValid(authInfo)
 	if err != nil {
-		s.logger.Warnf("User sent invalid data: %v", err)
-		return "", errs.InvalidData
+		s.logger.WithContext(ctx).Warnf("User sent invalid data: %v", err)
+		return nil, errs.InvalidData
 	}
-	s.logger.Infof("User %s trying to login", authInfo.Username)
+	s.logger.WithContext(ctx).Infof("User %s trying to login", authInfo.Username)
 
 	userDb, err := s.authRepo.GetByUsername(ctx, authInfo.Username)
 	if err != nil {
-		s.logger.Warnf("User %s trying to login: %v", authInfo.Username, err)
-		return "", errs.InternalError
+		s.logger.WithContext(ctx).Warnf("User %s trying to login: %v", authInfo.Username, err)
+		return nil, errs.InternalError
 	}
+	role := entity.RoleUser
 	if userDb == nil {
-		s.logger.Infof("User %s not exists, trying to register", authInfo.Username)
+		if !s.autoRegister {
+			s.logger.WithContext(ctx).Warnf("User %s not exists", authInfo.Username)
+			s.verifyDummyPassword(ctx, authInfo.Password)
+			return nil, errs.InvalidCredentials
+		}
+		s.logger.WithContext(ctx).Infof("User %s not exists, trying to register", authInfo.Username)
 		err = s.register(ctx, authInfo)
 		if err != nil {
-			s.logger.Warnf("User %s trying to register: %v", authInfo.Username, err)
-			return "", errs.InternalError
+			s.logger.WithContext(ctx).Warnf("User %s trying to register: %v", authInfo.Username, err)
+			return nil, errs.InternalError
 		}
 	} else {
 		if !s.hasher.VerifyPassword(authInfo.Password, userDb.Password) {
-			s.logger.Warnf("User %s trying to login with invalid pass", authInfo.Username)
-			return "", errs.InvalidCredentials
+			s.logger.WithContext(ctx).Warnf("User %s trying to login with invalid pass", authInfo.Username)
+			return nil, errs.InvalidCredentials
+		}
+		role = userDb.Role
+
+		if s.hasher.NeedsRehash(userDb.Password) {
+			s.rehashPassword(ctx, authInfo, userDb.Password)
+		}
+	}
+
+	tokens, err := s.createTokenPair(ctx, authInfo.Username, role)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s trying to login: creating auth tokens error (%v)",
+			authInfo.Username, err)
+		return nil, errs.InternalError
+	}
+
+	return tokens, nil
+}
+
+// verifyDummyPassword takes as long as verifying password of existing user, hash is created once
+// with current hashing parameters
+func (s *AuthService) verifyDummyPassword(ctx context.Context, password string) {
+	s.dummyHashOnce.Do(func() {
+		hash, err := s.hasher.HashPassword(dummyPassword)
+		if err != nil {
+			s.logger.WithContext(ctx).Warnf("Hashing dummy pass: %v", err)
+			return
+		}
+		s.dummyHash = hash
+	})
+	_ = s.hasher.VerifyPassword(password, s.dummyHash)
+}
+
+// rehashPassword upgrades hash to current hashing parameters, login does not fail on its errors
+func (s *AuthService) rehashPassword(ctx context.Context, authInfo *entity.Auth, oldHash string) {
+	s.logger.WithContext(ctx).Infof("User %s password hash has outdated parameters, rehashing", authInfo.Username)
+
+	hashedPass, err := s.hasher.HashPassword(authInfo.Password)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s rehashing pass: %v", authInfo.Username, err)
+		return
+	}
+
+	err = s.authRepo.UpdatePasswordHash(ctx, &entity.Auth{
+		Username: authInfo.Username,
+		Password: hashedPass,
+	}, oldHash)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s updating rehashed pass: %v", authInfo.Username, err)
+	}
+}
+
+// createTokenPair creates access token and saves new refresh token for user
+func (s *AuthService) createTokenPair(ctx context.Context, username, role string) (*entity.TokenPair, error) {
+	accessToken, err := s.tokenManager.CreateToken(username, role)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, expiresAt, err := s.tokenManager.CreateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.tokenRepo.SaveRefreshToken(ctx, &entity.RefreshToken{
+		Username:  username,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// hashRefreshToken is enough for random tokens, there is nothing to brute force unlike passwords
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	if refreshToken == "" {
+		s.logger.WithContext(ctx).Warnf("Refreshing tokens with empty refresh token")
+		return nil, errs.InvalidData
+	}
+
+	newRefreshToken, expiresAt, err := s.tokenManager.CreateRefreshToken()
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Refreshing tokens: creating refresh token error (%v)", err)
+		return nil, errs.InternalError
+	}
+
+	user, err := s.tokenRepo.RotateRefreshToken(ctx, hashRefreshToken(refreshToken), &entity.RefreshToken{
+		TokenHash: hashRefreshToken(newRefreshToken),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Refreshing tokens: %v", err)
+		if errors.Is(err, errs.InvalidToken) {
+			return nil, err
+		}
+		return nil, errs.InternalError
+	}
+	s.logger.WithContext(ctx).Infof("User %s refreshed tokens", user.Username)
+
+	accessToken, err := s.tokenManager.CreateToken(user.Username, user.Role)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s refreshing tokens: creating auth token error (%v)", user.Username, err)
+		return nil, errs.InternalError
+	}
+
+	return &entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, logout *entity.Logout) error {
+	if logout == nil || logout.Username == "" || logout.JTI == "" {
+		s.logger.WithContext(ctx).Warnf("Logout invalid data")
+		return errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("User %s logging out", logout.Username)
+
+	err := s.tokenRepo.RevokeAccessToken(ctx, &entity.RevokedToken{
+		JTI:       logout.JTI,
+		Username:  logout.Username,
+		ExpiresAt: logout.ExpiresAt,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s logging out: %v", logout.Username, err)
+		return errs.InternalError
+	}
+
+	if logout.RefreshToken != "" {
+		err = s.tokenRepo.RevokeRefreshToken(ctx, logout.Username, hashRefreshToken(logout.RefreshToken))
+		if err != nil {
+			s.logger.WithContext(ctx).Warnf("User %s logging out: %v", logout.Username, err)
+			return errs.InternalError
+		}
+	}
+
+	return nil
+}
+
+func (s *AuthService) IsTokenRevoked(ctx context.Context, token *entity.AccessToken) (bool, error) {
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(ctx, token)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Checking user %s token %s revocation: %v", token.Username, token.JTI, err)
+		return false, errs.InternalError
+	}
+	return revoked, nil
+}
+
+func isValidPasswordChange(change *entity.PasswordChange) error {
+	if change == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if change.Username == "" {
+		return fmt.Errorf("empty username")
+	}
+	if change.OldPassword == "" {
+		return fmt.Errorf("empty old password")
+	}
+	if change.OldPassword == change.NewPassword {
+		return fmt.Errorf("new password same as old")
+	}
+	return isStrongPassword(change.NewPassword)
+}
+
+// ChangePassword returns new tokens, as all previously issued tokens are invalidated
+func (s *AuthService) ChangePassword(ctx context.Context, change *entity.PasswordChange) (*entity.TokenPair, error) {
+	err := isValidPasswordChange(change)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Changing password invalid data: %v", err)
+		return nil, errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("User %s trying to change password", change.Username)
+
+	userDb, err := s.authRepo.GetByUsername(ctx, change.Username)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s trying to change password: %v", change.Username, err)
+		return nil, errs.InternalError
+	}
+	if userDb == nil {
+		s.logger.WithContext(ctx).Warnf("User %s trying to change password: user not exists", change.Username)
+		return nil, errs.UserNotFound
+	}
+	if !s.hasher.VerifyPassword(change.OldPassword, userDb.Password) {
+		s.logger.WithContext(ctx).Warnf("User %s trying to change password with invalid old pass", change.Username)
+		return nil, errs.InvalidCredentials
+	}
+
+	err = s.updatePassword(ctx, change.Username, change.NewPassword)
+	if err != nil {
+		if errors.Is(err, errs.UserNotFound) {
+			return nil, err
+		}
+		return nil, errs.InternalError
+	}
+
+	tokens, err := s.createTokenPair(ctx, change.Username, userDb.Role)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s changed password: creating auth tokens error (%v)",
+			change.Username, err)
+		return nil, errs.InternalError
+	}
+
+	return tokens, nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, authInfo *entity.Auth) error {
+	if authInfo == nil || authInfo.Username == "" {
+		s.logger.WithContext(ctx).Warnf("Resetting password invalid data: empty username")
+		return errs.InvalidData
+	}
+	err := isStrongPassword(authInfo.Password)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Resetting password invalid data: %v", err)
+		return errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Resetting user %s password", authInfo.Username)
+
+	err = s.updatePassword(ctx, authInfo.Username, authInfo.Password)
+	if err != nil {
+		if errors.Is(err, errs.UserNotFound) {
+			return err
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *AuthService) updatePassword(ctx context.Context, username, password string) error {
+	hashedPass, err := s.hasher.HashPassword(password)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s hashing pass: %v", username, err)
+		return err
+	}
+
+	err = s.authRepo.UpdatePassword(ctx, &entity.Auth{
+		Username: username,
+		Password: hashedPass,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s updating password: %v", username, err)
+		return err
+	}
+
+	return nil
+}
+
+func isValidRegistration(authInfo *entity.Auth) error {
+	if authInfo == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if !usernameRegexp.MatchString(authInfo.Username) {
+		return fmt.Errorf("username must be 1-32 latin letters, digits, '_', '.' or '-'")
+	}
+	return isStrongPassword(authInfo.Password)
+}
+
+func isStrongPassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return fmt.Errorf("password length must be %d-%d bytes", minPasswordLength, maxPasswordLength)
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return fmt.Errorf("password must contain letters and digits")
+	}
+	return nil
+}
+
+func (s *AuthService) Register(ctx context.Context, authInfo *entity.Auth) (*entity.TokenPair, error) {
+	err := isValidRegistration(authInfo)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User sent invalid registration data: %v", err)
+		return nil, errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("User %s trying to register", authInfo.Username)
+
+	err = s.register(ctx, authInfo)
+	if err != nil {
+		if errors.Is(err, errs.UserAlreadyExists) {
+			return nil, err
 		}
+		return nil, errs.InternalError
 	}
 
-	token, err := s.tokenManager.CreateToken(authInfo.Username)
+	tokens, err := s.createTokenPair(ctx, authInfo.Username, entity.RoleUser)
 	if err != nil {
-		s.logger.Warnf("User %s trying to login: creating auth token error (%v)",
+		s.logger.WithContext(ctx).Warnf("User %s registered: creating auth tokens error (%v)",
 			authInfo.Username, err)
-		return "", errs.InternalError
+		return nil, errs.InternalError
 	}
 
-	return token, nil
+	return tokens, nil
 }
 
 func (s *AuthService) register(ctx context.Context, authInfo *entity.Auth) error {
 	hashedPass, err := s.hasher.HashPassword(authInfo.Password)
 	if err != nil {
-		s.logger.Warnf("User %s hashing pass: %v", authInfo.Username, err)
+		s.logger.WithContext(ctx).Warnf("User %s hashing pass: %v", authInfo.Username, err)
 		return err
 	}
 	authInfo.Password = hashedPass
 
 	err = s.authRepo.Register(ctx, authInfo)
 	if err != nil {
-		s.logger.Warnf("User %s trying to login: %v", authInfo.Username, err)
+		s.logger.WithContext(ctx).Warnf("User %s trying to login: %v", authInfo.Username, err)
 		return err
 	}
 
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	HealthCheckShutdown   = "shutdown"
+	HealthCheckDatabase   = "database"
+	HealthCheckMigrations = "migrations"
+)
+
+// healthCheckErrors are reported to client instead of check errors, which may expose internals and are logged
+var healthCheckErrors = map[string]string{
+	HealthCheckShutdown:   "server is shutting down",
+	HealthCheckDatabase:   "database is unavailable",
+	HealthCheckMigrations: "schema version is not up to date",
+}
+
+type HealthService struct {
+	logger        logger.ILogger
+	healthRepo    entity.IHealthRepository
+	schemaVersion int64
+	checkTimeout  time.Duration
+	shuttingDown  atomic.Bool
+}
+
+func NewHealthService(repo entity.IHealthRepository, logger logger.ILogger,
+	schemaVersion int64, checkTimeout time.Duration,
+) entity.IHealthService {
+	return &HealthService{
+		logger:        logger,
+		healthRepo:    repo,
+		schemaVersion: schemaVersion,
+		checkTimeout:  checkTimeout,
+	}
+}
+
+func (s *HealthService) Live(_ context.Context) *entity.HealthReport {
+	return &entity.HealthReport{
+		Status: entity.HealthStatusOK,
+		Checks: []*entity.HealthCheck{},
+	}
+}
+
+func (s *HealthService) Ready(ctx context.Context) *entity.HealthReport {
+	report := &entity.HealthReport{
+		Status: entity.HealthStatusOK,
+		Checks: []*entity.HealthCheck{
+			s.check(ctx, HealthCheckShutdown, s.checkShutdown),
+			s.check(ctx, HealthCheckDatabase, s.healthRepo.Ping),
+			s.check(ctx, HealthCheckMigrations, s.checkMigrations),
+		},
+	}
+	for _, check := range report.Checks {
+		if check.Status != entity.HealthStatusOK {
+			report.Status = entity.HealthStatusFail
+		}
+	}
+	return report
+}
+
+func (s *HealthService) SetShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+func (s *HealthService) check(ctx context.Context, name string,
+	check func(ctx context.Context) error,
+) *entity.HealthCheck {
+	if s.checkTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.checkTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := check(ctx)
+	result := &entity.HealthCheck{
+		Name:     name,
+		Status:   entity.HealthStatusOK,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("readiness check %s failed: %v", name, err)
+		result.Status = entity.HealthStatusFail
+		result.Error = healthCheckErrors[name]
+	}
+	return result
+}
+
+func (s *HealthService) checkShutdown(_ context.Context) error {
+	if s.shuttingDown.Load() {
+		return fmt.Errorf("shutting down")
+	}
+	return nil
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	version, err := s.healthRepo.GetSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version.Dirty {
+		return fmt.Errorf("migration %d failed, database is dirty", version.Version)
+	}
+	if version.Version != s.schemaVersion {
+		return fmt.Errorf("schema version is %d, expected %d", version.Version, s.schemaVersion)
+	}
+	return nil
+}
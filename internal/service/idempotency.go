@@ -0,0 +1,132 @@
+package service
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const maxIdempotencyKeyLength = 64
+
+type IdempotencyService struct {
+	logger          logger.ILogger
+	idempotencyRepo entity.IIdempotencyRepository
+}
+
+func NewIdempotencyService(repo entity.IIdempotencyRepository, logger logger.ILogger) entity.IIdempotencyService {
+	return &IdempotencyService{
+		logger:          logger,
+		idempotencyRepo: repo,
+	}
+}
+
+func (s *IdempotencyService) isValid(record *entity.IdempotencyRecord) error {
+	if record == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if record.Username == "" {
+		return fmt.Errorf("empty username")
+	}
+	if record.Key == "" {
+		return fmt.Errorf("empty idempotency key")
+	}
+	if len(record.Key) > maxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency key longer than %d", maxIdempotencyKeyLength)
+	}
+	for _, c := range record.Key {
+		if c < '!' || c > '~' {
+			return fmt.Errorf("idempotency key contains non printable characters")
+		}
+	}
+	return nil
+}
+
+func (s *IdempotencyService) Reserve(ctx context.Context,
+	record *entity.IdempotencyRecord,
+) (*entity.IdempotencyRecord, error) {
+	err := s.isValid(record)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Reserving idempotency key invalid data: %v", err)
+		return nil, errs.InvalidData
+	}
+	if record.RequestHash == "" {
+		s.logger.WithContext(ctx).Warnf("Reserving idempotency key \"%s\" with empty request hash", record.Key)
+		return nil, errs.InvalidData
+	}
+
+	stored, err := s.idempotencyRepo.Reserve(ctx, record)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User \"%s\" reserving idempotency key \"%s\": %v", record.Username, record.Key, err)
+		if errors.Is(err, errs.KeyReused) || errors.Is(err, errs.RequestInProgress) {
+			return nil, err
+		}
+		return nil, errs.InternalError
+	}
+	if stored != nil {
+		s.logger.WithContext(ctx).Infof("User \"%s\" replaying request with idempotency key \"%s\"", record.Username, record.Key)
+	}
+
+	return stored, nil
+}
+
+func (s *IdempotencyService) Execute(ctx context.Context, record *entity.IdempotencyRecord,
+	handle func(ctx context.Context) error,
+) error {
+	err := s.isValid(record)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Executing idempotent request invalid data: %v", err)
+		return errs.InvalidData
+	}
+
+	var handleErr error
+	err = s.idempotencyRepo.InTransaction(ctx, func(ctx context.Context) error {
+		handleErr = handle(ctx)
+		if handleErr != nil {
+			return handleErr
+		}
+		return s.idempotencyRepo.SaveResponse(ctx, record)
+	})
+	if handleErr != nil {
+		return handleErr
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User \"%s\" saving idempotency response for key \"%s\": %v",
+			record.Username, record.Key, err)
+		if errors.Is(err, errs.RequestInProgress) {
+			return errs.RequestInProgress
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *IdempotencyService) Release(ctx context.Context, record *entity.IdempotencyRecord) error {
+	err := s.isValid(record)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Releasing idempotency key invalid data: %v", err)
+		return errs.InvalidData
+	}
+
+	err = s.idempotencyRepo.Release(ctx, record)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User \"%s\" releasing idempotency key \"%s\": %v", record.Username, record.Key, err)
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *IdempotencyService) DeleteExpired(ctx context.Context, now time.Time) error {
+	err := s.idempotencyRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Deleting expired idempotency keys: %v", err)
+		return errs.InternalError
+	}
+
+	return nil
+}
@@ -7,17 +7,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"time"
 )
 
+const (
+	maxItemNameLength = 32
+	maxCartLines      = 50
+	maxItemQuantity   = 1000
+	maxCartUnits      = 1000 // total units of all lines, also bounds quantity of merged line
+
+	maxPurchasesPageSize = 100
+)
+
+var purchaseIDRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
 type ItemService struct {
-	logger   logger.ILogger
-	itemRepo entity.IItemRepository
+	logger       logger.ILogger
+	itemRepo     entity.IItemRepository
+	refundWindow time.Duration
 }
 
-func NewItemService(repo entity.IItemRepository, logger logger.ILogger) entity.IItemService {
+func NewItemService(repo entity.IItemRepository, logger logger.ILogger,
+	refundWindow time.Duration,
+) entity.IItemService {
 	return &ItemService{
-		logger:   logger,
-		itemRepo: repo,
+		logger:       logger,
+		itemRepo:     repo,
+		refundWindow: refundWindow,
 	}
 }
 
@@ -37,16 +54,92 @@ func (s *ItemService) isValid(purchase *entity.Purchase) error {
 func (s *ItemService) BuyItem(ctx context.Context, purchase *entity.Purchase) error {
 	err := s.isValid(purchase)
 	if err != nil {
-		s.logger.Warnf("buying item invalid data: %v", err)
+		s.logger.WithContext(ctx).Warnf("buying item invalid data: %v", err)
 		return errs.InvalidData
 	}
-	s.logger.Infof("User %s trying to buy item %s", purchase.Username, purchase.ItemName)
+	s.logger.WithContext(ctx).Infof("User %s trying to buy item %s", purchase.Username, purchase.ItemName)
 
 	err = s.itemRepo.BuyItem(ctx, purchase)
 	if err != nil {
-		s.logger.Warnf("User %s trying to buy item %s: %v", purchase.Username, purchase.ItemName, err)
-		if errors.Is(err, errs.ItemNotFound) ||
-			errors.Is(err, errs.UserNotFound) || errors.Is(err, errs.NotEnoughCoins) {
+		s.logger.WithContext(ctx).Warnf("User %s trying to buy item %s: %v", purchase.Username, purchase.ItemName, err)
+		if errors.Is(err, errs.ItemNotFound) || errors.Is(err, errs.UserNotFound) ||
+			errors.Is(err, errs.NotEnoughCoins) || errors.Is(err, errs.OutOfStock) {
+			return err
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *ItemService) isValidCart(cart *entity.Cart) error {
+	if cart == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if cart.Username == "" {
+		return fmt.Errorf("empty username")
+	}
+	if len(cart.Lines) == 0 {
+		return fmt.Errorf("empty cart")
+	}
+	if len(cart.Lines) > maxCartLines {
+		return fmt.Errorf("more than %d lines in cart", maxCartLines)
+	}
+	var units int32
+	for _, line := range cart.Lines {
+		if line == nil {
+			return fmt.Errorf("nil cart line")
+		}
+		if line.ItemName == "" {
+			return fmt.Errorf("empty item name")
+		}
+		if line.Quantity <= 0 || line.Quantity > maxItemQuantity {
+			return fmt.Errorf("item \"%s\" quantity out of range (1..%d)", line.ItemName, maxItemQuantity)
+		}
+		units += line.Quantity
+	}
+	if units > maxCartUnits {
+		return fmt.Errorf("more than %d units in cart", maxCartUnits)
+	}
+	return nil
+}
+
+// mergeCartLines sums quantities of lines with the same item
+func mergeCartLines(lines []*entity.CartLine) []*entity.CartLine {
+	merged := make([]*entity.CartLine, 0, len(lines))
+	byName := make(map[string]*entity.CartLine, len(lines))
+	for _, line := range lines {
+		if existing, ok := byName[line.ItemName]; ok {
+			existing.Quantity += line.Quantity
+			continue
+		}
+		tmp := &entity.CartLine{
+			ItemName: line.ItemName,
+			Quantity: line.Quantity,
+		}
+		byName[line.ItemName] = tmp
+		merged = append(merged, tmp)
+	}
+	return merged
+}
+
+func (s *ItemService) BuyItems(ctx context.Context, cart *entity.Cart) error {
+	err := s.isValidCart(cart)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Buying items invalid data: %v", err)
+		return errs.InvalidData
+	}
+	cart = &entity.Cart{
+		Username: cart.Username,
+		Lines:    mergeCartLines(cart.Lines),
+	}
+	s.logger.WithContext(ctx).Infof("User %s trying to buy %d different items", cart.Username, len(cart.Lines))
+
+	err = s.itemRepo.BuyItems(ctx, cart)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User %s trying to buy items: %v", cart.Username, err)
+		if errors.Is(err, errs.ItemNotFound) || errors.Is(err, errs.UserNotFound) ||
+			errors.Is(err, errs.NotEnoughCoins) || errors.Is(err, errs.OutOfStock) {
 			return err
 		}
 		return errs.InternalError
@@ -56,17 +149,255 @@ func (s *ItemService) BuyItem(ctx context.Context, purchase *entity.Purchase) er
 }
 
 func (s *ItemService) GetInventory(ctx context.Context, username string) ([]*entity.Item, error) {
-	s.logger.Infof("User \"%s\" getting his inventory", username)
+	s.logger.WithContext(ctx).Infof("User \"%s\" getting his inventory", username)
 	if username == "" {
-		s.logger.Warnf("Getting inventory for empty username")
+		s.logger.WithContext(ctx).Warnf("Getting inventory for empty username")
 		return nil, errs.InvalidData
 	}
 
 	items, err := s.itemRepo.GetInventory(ctx, username)
 	if err != nil {
-		s.logger.Warnf("User \"%s\" getting his inventory: %v", username, err)
+		s.logger.WithContext(ctx).Warnf("User \"%s\" getting his inventory: %v", username, err)
+		return nil, errs.InternalError
+	}
+
+	return items, nil
+}
+
+func (s *ItemService) isValidFilter(filter *entity.ItemsFilter) error {
+	if filter == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if filter.MinPrice != nil && *filter.MinPrice < 0 {
+		return fmt.Errorf("negative min price")
+	}
+	if filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		return fmt.Errorf("negative max price")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return fmt.Errorf("min price greater than max price")
+	}
+	if filter.Sort != "" && filter.Sort != entity.SortByPriceAsc && filter.Sort != entity.SortByPriceDesc {
+		return fmt.Errorf("unknown sort order \"%s\"", filter.Sort)
+	}
+	return nil
+}
+
+func (s *ItemService) GetItems(ctx context.Context, filter *entity.ItemsFilter) ([]*entity.Item, error) {
+	err := s.isValidFilter(filter)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Getting items invalid filter: %v", err)
+		return nil, errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Getting items catalog")
+
+	items, err := s.itemRepo.GetItems(ctx, filter)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Getting items catalog: %v", err)
 		return nil, errs.InternalError
 	}
 
 	return items, nil
 }
+
+func (s *ItemService) GetItem(ctx context.Context, name string) (*entity.Item, error) {
+	if name == "" {
+		s.logger.WithContext(ctx).Warnf("Getting item with empty name")
+		return nil, errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Getting item \"%s\"", name)
+
+	item, err := s.itemRepo.GetItem(ctx, name)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Getting item \"%s\": %v", name, err)
+		if errors.Is(err, errs.ItemNotFound) {
+			return nil, err
+		}
+		return nil, errs.InternalError
+	}
+
+	return item, nil
+}
+
+func (s *ItemService) isValidItem(item *entity.Item) error {
+	if item == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if item.Name == "" {
+		return fmt.Errorf("empty item name")
+	}
+	if len(item.Name) > maxItemNameLength {
+		return fmt.Errorf("item name longer than %d", maxItemNameLength)
+	}
+	if item.Price < 0 {
+		return fmt.Errorf("negative item price")
+	}
+	if item.Stock != nil && *item.Stock < 0 {
+		return fmt.Errorf("negative item stock")
+	}
+	return nil
+}
+
+func (s *ItemService) CreateItem(ctx context.Context, item *entity.Item) error {
+	err := s.isValidItem(item)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Creating item invalid data: %v", err)
+		return errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Creating item \"%s\" with price %d", item.Name, item.Price)
+
+	err = s.itemRepo.CreateItem(ctx, item)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Creating item \"%s\": %v", item.Name, err)
+		if errors.Is(err, errs.ItemAlreadyExists) {
+			return err
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *ItemService) UpdateItemPrice(ctx context.Context, item *entity.Item) error {
+	err := s.isValidItem(item)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Updating item price invalid data: %v", err)
+		return errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Updating item \"%s\" price to %d", item.Name, item.Price)
+
+	err = s.itemRepo.UpdateItemPrice(ctx, item)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Updating item \"%s\" price: %v", item.Name, err)
+		if errors.Is(err, errs.ItemNotFound) {
+			return err
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *ItemService) UpdateItemStock(ctx context.Context, item *entity.Item) error {
+	if item == nil || item.Name == "" {
+		s.logger.WithContext(ctx).Warnf("Updating item stock with empty item")
+		return errs.InvalidData
+	}
+	if item.Stock != nil && *item.Stock < 0 {
+		s.logger.WithContext(ctx).Warnf("Updating item \"%s\" stock to negative value", item.Name)
+		return errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Updating item \"%s\" stock", item.Name)
+
+	err := s.itemRepo.UpdateItemStock(ctx, item)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Updating item \"%s\" stock: %v", item.Name, err)
+		if errors.Is(err, errs.ItemNotFound) {
+			return err
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *ItemService) RetireItem(ctx context.Context, name string) error {
+	if name == "" {
+		s.logger.WithContext(ctx).Warnf("Retiring item with empty name")
+		return errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Retiring item \"%s\"", name)
+
+	err := s.itemRepo.RetireItem(ctx, name)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Retiring item \"%s\": %v", name, err)
+		if errors.Is(err, errs.ItemNotFound) {
+			return err
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *ItemService) isValidPurchasesFilter(filter *entity.PurchasesFilter) error {
+	if filter == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if filter.Username == "" {
+		return fmt.Errorf("empty username")
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return fmt.Errorf("from date after to date")
+	}
+	if filter.After != nil && filter.After.ID == "" {
+		return fmt.Errorf("empty cursor id")
+	}
+	if filter.Limit <= 0 || filter.Limit > maxPurchasesPageSize {
+		return fmt.Errorf("page size out of range (1..%d)", maxPurchasesPageSize)
+	}
+	return nil
+}
+
+func (s *ItemService) GetPurchases(ctx context.Context,
+	filter *entity.PurchasesFilter,
+) (*entity.PurchasesPage, error) {
+	err := s.isValidPurchasesFilter(filter)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Getting purchases invalid filter: %v", err)
+		return nil, errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Getting purchases for user \"%s\"", filter.Username)
+
+	repoFilter := *filter
+	repoFilter.Limit++ // one more purchase to know if there is next page
+	purchases, err := s.itemRepo.GetPurchases(ctx, &repoFilter)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Getting purchases for user \"%s\": %v", filter.Username, err)
+		return nil, errs.InternalError
+	}
+
+	page := &entity.PurchasesPage{
+		Purchases: purchases,
+	}
+	if len(purchases) > int(filter.Limit) {
+		page.Purchases = purchases[:filter.Limit]
+		last := page.Purchases[len(page.Purchases)-1]
+		page.Next = &entity.PurchasesCursor{
+			Time: last.Time,
+			ID:   last.ID,
+		}
+	}
+
+	return page, nil
+}
+
+func (s *ItemService) RefundPurchase(ctx context.Context,
+	username string, purchaseID string,
+) (*entity.PurchaseRecord, error) {
+	if username == "" || !purchaseIDRegexp.MatchString(purchaseID) {
+		s.logger.WithContext(ctx).Warnf("Refunding purchase invalid data: user \"%s\", purchase \"%s\"", username, purchaseID)
+		return nil, errs.InvalidData
+	}
+	if s.refundWindow <= 0 {
+		s.logger.WithContext(ctx).Warnf("User \"%s\" trying to refund purchase \"%s\": refunds disabled", username, purchaseID)
+		return nil, errs.RefundExpired
+	}
+	s.logger.WithContext(ctx).Infof("User \"%s\" trying to refund purchase \"%s\"", username, purchaseID)
+
+	purchase, err := s.itemRepo.RefundPurchase(ctx, &entity.Refund{
+		Username:   username,
+		PurchaseID: purchaseID,
+		Window:     s.refundWindow,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("User \"%s\" trying to refund purchase \"%s\": %v", username, purchaseID, err)
+		if errors.Is(err, errs.PurchaseNotFound) || errors.Is(err, errs.AlreadyRefunded) ||
+			errors.Is(err, errs.RefundExpired) {
+			return nil, err
+		}
+		return nil, errs.InternalError
+	}
+
+	return purchase, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+)
+
+// LoginAttemptService keeps failed attempts in repository,
+// so limits are shared between all processes (fiber prefork)
+type LoginAttemptService struct {
+	logger      logger.ILogger
+	attemptRepo entity.ILoginAttemptRepository
+	policy      entity.BruteForcePolicy
+}
+
+func NewLoginAttemptService(repo entity.ILoginAttemptRepository, logger logger.ILogger,
+	policy entity.BruteForcePolicy,
+) entity.ILoginAttemptService {
+	return &LoginAttemptService{
+		logger:      logger,
+		attemptRepo: repo,
+		policy:      policy,
+	}
+}
+
+func (s *LoginAttemptService) enabled() bool {
+	return s.policy.UsernameThreshold > 0 || s.policy.IPThreshold > 0
+}
+
+func isValidLoginAttempt(attempt *entity.LoginAttempt) error {
+	if attempt == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if attempt.Username == "" {
+		return fmt.Errorf("empty username")
+	}
+	if attempt.IP == "" {
+		return fmt.Errorf("empty ip")
+	}
+	if attempt.Time.IsZero() {
+		return fmt.Errorf("empty attempt time")
+	}
+	return nil
+}
+
+func (s *LoginAttemptService) Reserve(ctx context.Context, attempt *entity.LoginAttempt) error {
+	if !s.enabled() {
+		return nil
+	}
+	err := isValidLoginAttempt(attempt)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Reserving login attempt invalid data: %v", err)
+		return errs.InvalidData
+	}
+
+	failures, err := s.attemptRepo.ReserveAttempt(ctx, attempt, attempt.Time.Add(-s.policy.Window))
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Reserving user %s (ip %s) login attempt: %v", attempt.Username, attempt.IP, err)
+		return errs.InternalError
+	}
+
+	retryAfter := max(
+		s.retryAfter(attempt.Time, failures.UsernameCount, failures.UsernameLastFailure, s.policy.UsernameThreshold),
+		s.retryAfter(attempt.Time, failures.IPCount, failures.IPLastFailure, s.policy.IPThreshold),
+	)
+	if retryAfter > 0 {
+		s.logger.WithContext(ctx).Warnf("User %s (ip %s) login attempt rejected: %d failed attempts by username, %d by ip, retry after %v",
+			attempt.Username, attempt.IP, failures.UsernameCount, failures.IPCount, retryAfter)
+		// rejected attempt does not prolong delay
+		err = s.Release(ctx, attempt)
+		if err != nil {
+			return err
+		}
+		return &errs.RetryAfterError{
+			Err:        errs.TooManyAttempts,
+			RetryAfter: retryAfter,
+		}
+	}
+
+	return nil
+}
+
+// retryAfter returns non-positive duration if attempt is allowed
+func (s *LoginAttemptService) retryAfter(now time.Time, failures int, lastFailure time.Time, threshold int) time.Duration {
+	if threshold <= 0 || failures < threshold {
+		return 0
+	}
+
+	delay := s.policy.BaseDelay
+	for i := threshold; i < failures && delay < s.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, s.policy.MaxDelay)
+
+	return lastFailure.Add(delay).Sub(now)
+}
+
+func (s *LoginAttemptService) Release(ctx context.Context, attempt *entity.LoginAttempt) error {
+	if !s.enabled() {
+		return nil
+	}
+	if attempt == nil || attempt.ID == 0 {
+		s.logger.WithContext(ctx).Warnf("Releasing login attempt invalid data: attempt is not reserved")
+		return errs.InvalidData
+	}
+
+	err := s.attemptRepo.DeleteAttempt(ctx, attempt.ID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Releasing user %s (ip %s) login attempt: %v", attempt.Username, attempt.IP, err)
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *LoginAttemptService) Succeeded(ctx context.Context, attempt *entity.LoginAttempt) error {
+	if !s.enabled() {
+		return nil
+	}
+	err := isValidLoginAttempt(attempt)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Clearing failed login attempts invalid data: %v", err)
+		return errs.InvalidData
+	}
+
+	// reserved attempt is not a failure, it must not count for ip either
+	if attempt.ID != 0 {
+		err = s.attemptRepo.DeleteAttempt(ctx, attempt.ID)
+		if err != nil {
+			s.logger.WithContext(ctx).Warnf("Deleting user %s succeeded login attempt: %v", attempt.Username, err)
+			return errs.InternalError
+		}
+	}
+
+	err = s.attemptRepo.ClearUsernameFailures(ctx, attempt.Username)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Clearing user %s failed login attempts: %v", attempt.Username, err)
+		return errs.InternalError
+	}
+
+	return nil
+}
+
+func (s *LoginAttemptService) DeleteExpired(ctx context.Context, now time.Time) error {
+	if !s.enabled() {
+		return nil
+	}
+
+	err := s.attemptRepo.DeleteFailuresBefore(ctx, now.Add(-s.policy.Window))
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Deleting expired failed login attempts: %v", err)
+		return errs.InternalError
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/metrics"
+	"context"
+)
+
+// ItemServiceMetrics counts purchased units and failed purchases and refunds
+type ItemServiceMetrics struct {
+	entity.IItemService
+	metrics *metrics.Metrics
+}
+
+func NewItemServiceMetrics(next entity.IItemService, metrics *metrics.Metrics) entity.IItemService {
+	return &ItemServiceMetrics{
+		IItemService: next,
+		metrics:      metrics,
+	}
+}
+
+func (s *ItemServiceMetrics) BuyItem(ctx context.Context, purchase *entity.Purchase) error {
+	err := s.IItemService.BuyItem(ctx, purchase)
+	if err != nil {
+		s.metrics.ObserveFailure("buy", err)
+		return err
+	}
+	s.metrics.Purchases.WithLabelValues(purchase.ItemName).Inc()
+	return nil
+}
+
+func (s *ItemServiceMetrics) BuyItems(ctx context.Context, cart *entity.Cart) error {
+	err := s.IItemService.BuyItems(ctx, cart)
+	if err != nil {
+		s.metrics.ObserveFailure("buy", err)
+		return err
+	}
+	for _, line := range cart.Lines {
+		s.metrics.Purchases.WithLabelValues(line.ItemName).Add(float64(line.Quantity))
+	}
+	return nil
+}
+
+func (s *ItemServiceMetrics) RefundPurchase(ctx context.Context,
+	username string, purchaseID string,
+) (*entity.PurchaseRecord, error) {
+	purchase, err := s.IItemService.RefundPurchase(ctx, username, purchaseID)
+	s.metrics.ObserveFailure("refund", err)
+	return purchase, err
+}
+
+// UserServiceMetrics counts transferred coins and failed transfers
+type UserServiceMetrics struct {
+	entity.IUserService
+	metrics *metrics.Metrics
+}
+
+func NewUserServiceMetrics(next entity.IUserService, metrics *metrics.Metrics) entity.IUserService {
+	return &UserServiceMetrics{
+		IUserService: next,
+		metrics:      metrics,
+	}
+}
+
+func (s *UserServiceMetrics) SendCoins(ctx context.Context, transfer *entity.TransferCoins) error {
+	err := s.IUserService.SendCoins(ctx, transfer)
+	if err != nil {
+		s.metrics.ObserveFailure("send_coins", err)
+		return err
+	}
+	s.metrics.CoinsTransferred.Add(float64(transfer.Amount))
+	return nil
+}
+
+// AuthServiceMetrics counts failed logins and registrations
+type AuthServiceMetrics struct {
+	entity.IAuthService
+	metrics *metrics.Metrics
+}
+
+func NewAuthServiceMetrics(next entity.IAuthService, metrics *metrics.Metrics) entity.IAuthService {
+	return &AuthServiceMetrics{
+		IAuthService: next,
+		metrics:      metrics,
+	}
+}
+
+func (s *AuthServiceMetrics) Auth(ctx context.Context, authInfo *entity.Auth) (*entity.TokenPair, error) {
+	tokens, err := s.IAuthService.Auth(ctx, authInfo)
+	s.metrics.ObserveFailure("auth", err)
+	return tokens, err
+}
+
+func (s *AuthServiceMetrics) Register(ctx context.Context, authInfo *entity.Auth) (*entity.TokenPair, error) {
+	tokens, err := s.IAuthService.Register(ctx, authInfo)
+	s.metrics.ObserveFailure("register", err)
+	return tokens, err
+}
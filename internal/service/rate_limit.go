@@ -0,0 +1,113 @@
+package service
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const defaultRateLimitBudgetPath = "*"
+
+type RateLimitService struct {
+	logger        logger.ILogger
+	rateLimitRepo entity.IRateLimitRepository
+	policy        entity.RateLimitPolicy
+}
+
+func NewRateLimitService(repo entity.IRateLimitRepository, logger logger.ILogger,
+	policy entity.RateLimitPolicy,
+) entity.IRateLimitService {
+	return &RateLimitService{
+		logger:        logger,
+		rateLimitRepo: repo,
+		policy:        policy,
+	}
+}
+
+// budget returns the most specific route budget and its path
+func (s *RateLimitService) budget(path string) (entity.RateLimitBudget, string) {
+	budget, budgetPath := s.policy.Default, defaultRateLimitBudgetPath
+	matchedLength := -1
+	for _, route := range s.policy.Routes {
+		if len(route.Path) <= matchedLength {
+			continue
+		}
+		if path == route.Path || strings.HasPrefix(path, strings.TrimSuffix(route.Path, "/")+"/") {
+			budget, budgetPath = route.RateLimitBudget, route.Path
+			matchedLength = len(route.Path)
+		}
+	}
+	return budget, budgetPath
+}
+
+func isValidRateLimitRequest(request *entity.RateLimitRequest) error {
+	if request == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if request.Subject == "" {
+		return fmt.Errorf("empty subject")
+	}
+	if request.Time.IsZero() {
+		return fmt.Errorf("empty request time")
+	}
+	return nil
+}
+
+func (s *RateLimitService) Allow(ctx context.Context, request *entity.RateLimitRequest) (*entity.RateLimitStatus, error) {
+	err := isValidRateLimitRequest(request)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Rate limiting invalid data: %v", err)
+		return nil, errs.InvalidData
+	}
+
+	budget, budgetPath := s.budget(request.Path)
+	if budget.Limit <= 0 || budget.Window <= 0 {
+		return nil, nil
+	}
+
+	windowStart := request.Time.Truncate(budget.Window)
+	count, err := s.rateLimitRepo.Increment(ctx, request.Subject+" "+budgetPath, windowStart)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Rate limiting %s on %s: %v", request.Subject, request.Path, err)
+		return nil, errs.InternalError
+	}
+
+	status := &entity.RateLimitStatus{
+		Allowed:   count <= budget.Limit,
+		Limit:     budget.Limit,
+		Remaining: max(budget.Limit-count, 0),
+		Reset:     windowStart.Add(budget.Window),
+	}
+	if !status.Allowed {
+		s.logger.WithContext(ctx).Warnf("Rate limit %d per %v exceeded by %s on %s", budget.Limit, budget.Window, request.Subject, request.Path)
+	}
+	return status, nil
+}
+
+// maxWindow returns the longest window of budgets, counters older than it are expired for any budget
+func (s *RateLimitService) maxWindow() time.Duration {
+	window := s.policy.Default.Window
+	for _, route := range s.policy.Routes {
+		window = max(window, route.Window)
+	}
+	return window
+}
+
+func (s *RateLimitService) DeleteExpired(ctx context.Context, now time.Time) error {
+	window := s.maxWindow()
+	if window <= 0 {
+		return nil
+	}
+
+	err := s.rateLimitRepo.DeleteWindowsBefore(ctx, now.Add(-window))
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Deleting expired rate limit counters: %v", err)
+		return errs.InternalError
+	}
+
+	return nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/tracing"
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	usernameKey = attribute.Key("shop.username")
+	itemKey     = attribute.Key("shop.item")
+	amountKey   = attribute.Key("shop.amount")
+)
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// AuthServiceTracing wraps every method of auth service into span,
+// passwords and tokens are never recorded
+type AuthServiceTracing struct {
+	next entity.IAuthService
+}
+
+func NewAuthServiceTracing(next entity.IAuthService) entity.IAuthService {
+	return &AuthServiceTracing{
+		next: next,
+	}
+}
+
+func (s *AuthServiceTracing) Auth(ctx context.Context, authInfo *entity.Auth) (tokens *entity.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthService.Auth", usernameKey.String(authInfo.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Auth(ctx, authInfo)
+}
+
+func (s *AuthServiceTracing) Register(ctx context.Context,
+	authInfo *entity.Auth,
+) (tokens *entity.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthService.Register", usernameKey.String(authInfo.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Register(ctx, authInfo)
+}
+
+func (s *AuthServiceTracing) Refresh(ctx context.Context, refreshToken string) (tokens *entity.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthService.Refresh")
+	defer func() { tracing.End(span, err) }()
+	return s.next.Refresh(ctx, refreshToken)
+}
+
+func (s *AuthServiceTracing) Logout(ctx context.Context, logout *entity.Logout) (err error) {
+	ctx, span := startSpan(ctx, "AuthService.Logout")
+	defer func() { tracing.End(span, err) }()
+	return s.next.Logout(ctx, logout)
+}
+
+func (s *AuthServiceTracing) IsTokenRevoked(ctx context.Context, token *entity.AccessToken) (revoked bool, err error) {
+	ctx, span := startSpan(ctx, "AuthService.IsTokenRevoked")
+	defer func() { tracing.End(span, err) }()
+	return s.next.IsTokenRevoked(ctx, token)
+}
+
+func (s *AuthServiceTracing) ChangePassword(ctx context.Context,
+	change *entity.PasswordChange,
+) (tokens *entity.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthService.ChangePassword", usernameKey.String(change.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ChangePassword(ctx, change)
+}
+
+func (s *AuthServiceTracing) ResetPassword(ctx context.Context, authInfo *entity.Auth) (err error) {
+	ctx, span := startSpan(ctx, "AuthService.ResetPassword", usernameKey.String(authInfo.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.ResetPassword(ctx, authInfo)
+}
+
+type ItemServiceTracing struct {
+	next entity.IItemService
+}
+
+func NewItemServiceTracing(next entity.IItemService) entity.IItemService {
+	return &ItemServiceTracing{
+		next: next,
+	}
+}
+
+func (s *ItemServiceTracing) GetInventory(ctx context.Context, username string) (items []*entity.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.GetInventory", usernameKey.String(username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetInventory(ctx, username)
+}
+
+func (s *ItemServiceTracing) BuyItem(ctx context.Context, purchase *entity.Purchase) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.BuyItem",
+		usernameKey.String(purchase.Username), itemKey.String(purchase.ItemName))
+	defer func() { tracing.End(span, err) }()
+	return s.next.BuyItem(ctx, purchase)
+}
+
+func (s *ItemServiceTracing) BuyItems(ctx context.Context, cart *entity.Cart) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.BuyItems", usernameKey.String(cart.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.BuyItems(ctx, cart)
+}
+
+func (s *ItemServiceTracing) GetItems(ctx context.Context, filter *entity.ItemsFilter) (items []*entity.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.GetItems")
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetItems(ctx, filter)
+}
+
+func (s *ItemServiceTracing) GetItem(ctx context.Context, name string) (item *entity.Item, err error) {
+	ctx, span := startSpan(ctx, "ItemService.GetItem", itemKey.String(name))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetItem(ctx, name)
+}
+
+func (s *ItemServiceTracing) CreateItem(ctx context.Context, item *entity.Item) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.CreateItem", itemKey.String(item.Name))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateItem(ctx, item)
+}
+
+func (s *ItemServiceTracing) UpdateItemPrice(ctx context.Context, item *entity.Item) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.UpdateItemPrice", itemKey.String(item.Name))
+	defer func() { tracing.End(span, err) }()
+	return s.next.UpdateItemPrice(ctx, item)
+}
+
+func (s *ItemServiceTracing) UpdateItemStock(ctx context.Context, item *entity.Item) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.UpdateItemStock", itemKey.String(item.Name))
+	defer func() { tracing.End(span, err) }()
+	return s.next.UpdateItemStock(ctx, item)
+}
+
+func (s *ItemServiceTracing) RetireItem(ctx context.Context, name string) (err error) {
+	ctx, span := startSpan(ctx, "ItemService.RetireItem", itemKey.String(name))
+	defer func() { tracing.End(span, err) }()
+	return s.next.RetireItem(ctx, name)
+}
+
+func (s *ItemServiceTracing) GetPurchases(ctx context.Context,
+	filter *entity.PurchasesFilter,
+) (page *entity.PurchasesPage, err error) {
+	ctx, span := startSpan(ctx, "ItemService.GetPurchases", usernameKey.String(filter.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetPurchases(ctx, filter)
+}
+
+func (s *ItemServiceTracing) RefundPurchase(ctx context.Context,
+	username string, purchaseID string,
+) (purchase *entity.PurchaseRecord, err error) {
+	ctx, span := startSpan(ctx, "ItemService.RefundPurchase", usernameKey.String(username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.RefundPurchase(ctx, username, purchaseID)
+}
+
+type UserServiceTracing struct {
+	next entity.IUserService
+}
+
+func NewUserServiceTracing(next entity.IUserService) entity.IUserService {
+	return &UserServiceTracing{
+		next: next,
+	}
+}
+
+func (s *UserServiceTracing) SendCoins(ctx context.Context, transfer *entity.TransferCoins) (err error) {
+	ctx, span := startSpan(ctx, "UserService.SendCoins",
+		usernameKey.String(transfer.FromUser), amountKey.Int64(int64(transfer.Amount)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.SendCoins(ctx, transfer)
+}
+
+func (s *UserServiceTracing) GrantCoins(ctx context.Context, grant *entity.GrantCoins) (err error) {
+	ctx, span := startSpan(ctx, "UserService.GrantCoins",
+		usernameKey.String(grant.Username), amountKey.Int64(int64(grant.Amount)))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GrantCoins(ctx, grant)
+}
+
+func (s *UserServiceTracing) GetCoinsHistory(ctx context.Context,
+	username string,
+) (coins int32, history *entity.CoinsHistory, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetCoinsHistory", usernameKey.String(username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetCoinsHistory(ctx, username)
+}
+
+func (s *UserServiceTracing) GetTransactions(ctx context.Context,
+	filter *entity.TransactionsFilter,
+) (page *entity.TransactionsPage, err error) {
+	ctx, span := startSpan(ctx, "UserService.GetTransactions", usernameKey.String(filter.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetTransactions(ctx, filter)
+}
+
+type IdempotencyServiceTracing struct {
+	next entity.IIdempotencyService
+}
+
+func NewIdempotencyServiceTracing(next entity.IIdempotencyService) entity.IIdempotencyService {
+	return &IdempotencyServiceTracing{
+		next: next,
+	}
+}
+
+func (s *IdempotencyServiceTracing) Reserve(ctx context.Context,
+	record *entity.IdempotencyRecord,
+) (stored *entity.IdempotencyRecord, err error) {
+	ctx, span := startSpan(ctx, "IdempotencyService.Reserve")
+	defer func() { tracing.End(span, err) }()
+	return s.next.Reserve(ctx, record)
+}
+
+func (s *IdempotencyServiceTracing) Execute(ctx context.Context, record *entity.IdempotencyRecord,
+	handle func(ctx context.Context) error,
+) (err error) {
+	ctx, span := startSpan(ctx, "IdempotencyService.Execute")
+	defer func() { tracing.End(span, err) }()
+	return s.next.Execute(ctx, record, handle)
+}
+
+func (s *IdempotencyServiceTracing) Release(ctx context.Context, record *entity.IdempotencyRecord) (err error) {
+	ctx, span := startSpan(ctx, "IdempotencyService.Release")
+	defer func() { tracing.End(span, err) }()
+	return s.next.Release(ctx, record)
+}
+
+func (s *IdempotencyServiceTracing) DeleteExpired(ctx context.Context, now time.Time) (err error) {
+	ctx, span := startSpan(ctx, "IdempotencyService.DeleteExpired")
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeleteExpired(ctx, now)
+}
+
+type LoginAttemptServiceTracing struct {
+	next entity.ILoginAttemptService
+}
+
+func NewLoginAttemptServiceTracing(next entity.ILoginAttemptService) entity.ILoginAttemptService {
+	return &LoginAttemptServiceTracing{
+		next: next,
+	}
+}
+
+func (s *LoginAttemptServiceTracing) Reserve(ctx context.Context, attempt *entity.LoginAttempt) (err error) {
+	ctx, span := startSpan(ctx, "LoginAttemptService.Reserve", usernameKey.String(attempt.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Reserve(ctx, attempt)
+}
+
+func (s *LoginAttemptServiceTracing) Release(ctx context.Context, attempt *entity.LoginAttempt) (err error) {
+	ctx, span := startSpan(ctx, "LoginAttemptService.Release", usernameKey.String(attempt.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Release(ctx, attempt)
+}
+
+func (s *LoginAttemptServiceTracing) Succeeded(ctx context.Context, attempt *entity.LoginAttempt) (err error) {
+	ctx, span := startSpan(ctx, "LoginAttemptService.Succeeded", usernameKey.String(attempt.Username))
+	defer func() { tracing.End(span, err) }()
+	return s.next.Succeeded(ctx, attempt)
+}
+
+func (s *LoginAttemptServiceTracing) DeleteExpired(ctx context.Context, now time.Time) (err error) {
+	ctx, span := startSpan(ctx, "LoginAttemptService.DeleteExpired")
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeleteExpired(ctx, now)
+}
+
+type RateLimitServiceTracing struct {
+	next entity.IRateLimitService
+}
+
+func NewRateLimitServiceTracing(next entity.IRateLimitService) entity.IRateLimitService {
+	return &RateLimitServiceTracing{
+		next: next,
+	}
+}
+
+func (s *RateLimitServiceTracing) Allow(ctx context.Context,
+	request *entity.RateLimitRequest,
+) (status *entity.RateLimitStatus, err error) {
+	ctx, span := startSpan(ctx, "RateLimitService.Allow")
+	defer func() { tracing.End(span, err) }()
+	return s.next.Allow(ctx, request)
+}
+
+func (s *RateLimitServiceTracing) DeleteExpired(ctx context.Context, now time.Time) (err error) {
+	ctx, span := startSpan(ctx, "RateLimitService.DeleteExpired")
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeleteExpired(ctx, now)
+}
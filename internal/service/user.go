@@ -7,6 +7,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxTransactionsPageSize  = 100
+	maxTransferCommentLength = 128
 )
 
 type UserService struct {
@@ -38,21 +46,37 @@ func (s *UserService) isValid(transfer *entity.TransferCoins) error {
 		return fmt.Errorf("same user as reciever and sender")
 	}
 
-	return nil
+	var err error
+	transfer.Comment, err = sanitizeComment(transfer.Comment)
+	return err
+}
+
+// sanitizeComment drops control characters, so comment can not break history output
+func sanitizeComment(comment string) (string, error) {
+	comment = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, comment))
+	if utf8.RuneCountInString(comment) > maxTransferCommentLength {
+		return "", fmt.Errorf("comment longer than %d characters", maxTransferCommentLength)
+	}
+	return comment, nil
 }
 
 func (s *UserService) SendCoins(ctx context.Context, transfer *entity.TransferCoins) error {
 	err := s.isValid(transfer)
 	if err != nil {
-		s.logger.Warnf("Sending coins invalid data: %v", err)
+		s.logger.WithContext(ctx).Warnf("Sending coins invalid data: %v", err)
 		return errs.InvalidData
 	}
-	s.logger.Infof("User \"%s\" trying to transfer coins (%d) to \"%s\"",
+	s.logger.WithContext(ctx).Infof("User \"%s\" trying to transfer coins (%d) to \"%s\"",
 		transfer.FromUser, transfer.Amount, transfer.ToUser)
 
 	err = s.userRepo.SendCoins(ctx, transfer)
 	if err != nil {
-		s.logger.Warnf("User \"%s\" trying to transfer coins (%d) to \"%s\": %v",
+		s.logger.WithContext(ctx).Warnf("User \"%s\" trying to transfer coins (%d) to \"%s\": %v",
 			transfer.FromUser, transfer.Amount, transfer.ToUser, err)
 
 		if errors.Is(err, errs.UserNotFound) || errors.Is(err, errs.NotEnoughCoins) {
@@ -64,16 +88,52 @@ func (s *UserService) SendCoins(ctx context.Context, transfer *entity.TransferCo
 	return nil
 }
 
+func (s *UserService) isValidGrant(grant *entity.GrantCoins) error {
+	if grant == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if grant.Username == "" {
+		return fmt.Errorf("empty username")
+	}
+	if grant.Amount <= 0 {
+		return fmt.Errorf("negative or zero amount of coins")
+	}
+
+	var err error
+	grant.Comment, err = sanitizeComment(grant.Comment)
+	return err
+}
+
+func (s *UserService) GrantCoins(ctx context.Context, grant *entity.GrantCoins) error {
+	err := s.isValidGrant(grant)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Granting coins invalid data: %v", err)
+		return errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Granting coins (%d) to user \"%s\"", grant.Amount, grant.Username)
+
+	err = s.userRepo.GrantCoins(ctx, grant)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Granting coins (%d) to user \"%s\": %v", grant.Amount, grant.Username, err)
+		if errors.Is(err, errs.UserNotFound) {
+			return err
+		}
+		return errs.InternalError
+	}
+
+	return nil
+}
+
 func (s *UserService) GetCoinsHistory(ctx context.Context, username string) (int32, *entity.CoinsHistory, error) {
 	if username == "" {
-		s.logger.Warnf("Getting coins history for empty username")
+		s.logger.WithContext(ctx).Warnf("Getting coins history for empty username")
 		return 0, nil, errs.InvalidData
 	}
-	s.logger.Infof("Getting coins history for user \"%s\"", username)
+	s.logger.WithContext(ctx).Infof("Getting coins history for user \"%s\"", username)
 
 	coins, coinsHistory, err := s.userRepo.GetCoinsHistory(ctx, username)
 	if err != nil {
-		s.logger.Warnf("Getting coins history for user \"%s\": %v", username, err)
+		s.logger.WithContext(ctx).Warnf("Getting coins history for user \"%s\": %v", username, err)
 		if errors.Is(err, errs.UserNotFound) {
 			return 0, nil, err
 		}
@@ -82,3 +142,59 @@ func (s *UserService) GetCoinsHistory(ctx context.Context, username string) (int
 
 	return coins, coinsHistory, nil
 }
+
+func (s *UserService) isValidFilter(filter *entity.TransactionsFilter) error {
+	if filter == nil {
+		return fmt.Errorf("pointer to struct is nil")
+	}
+	if filter.Username == "" {
+		return fmt.Errorf("empty username")
+	}
+	if filter.Direction != "" && filter.Direction != entity.DirectionSent &&
+		filter.Direction != entity.DirectionReceived {
+		return fmt.Errorf("unknown direction \"%s\"", filter.Direction)
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return fmt.Errorf("from date after to date")
+	}
+	if filter.After != nil && filter.After.ID == "" {
+		return fmt.Errorf("empty cursor id")
+	}
+	if filter.Limit <= 0 || filter.Limit > maxTransactionsPageSize {
+		return fmt.Errorf("page size out of range (1..%d)", maxTransactionsPageSize)
+	}
+	return nil
+}
+
+func (s *UserService) GetTransactions(ctx context.Context,
+	filter *entity.TransactionsFilter,
+) (*entity.TransactionsPage, error) {
+	err := s.isValidFilter(filter)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Getting transactions invalid filter: %v", err)
+		return nil, errs.InvalidData
+	}
+	s.logger.WithContext(ctx).Infof("Getting transactions for user \"%s\"", filter.Username)
+
+	repoFilter := *filter
+	repoFilter.Limit++ // one more transaction to know if there is next page
+	transactions, err := s.userRepo.GetTransactions(ctx, &repoFilter)
+	if err != nil {
+		s.logger.WithContext(ctx).Warnf("Getting transactions for user \"%s\": %v", filter.Username, err)
+		return nil, errs.InternalError
+	}
+
+	page := &entity.TransactionsPage{
+		Transactions: transactions,
+	}
+	if len(transactions) > int(filter.Limit) {
+		page.Transactions = transactions[:filter.Limit]
+		last := page.Transactions[len(page.Transactions)-1]
+		page.Next = &entity.TransactionsCursor{
+			Time: last.Time,
+			ID:   last.ID,
+		}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"context"
+	"sync"
+	"time"
+)
+
+type rateLimitCounter struct {
+	windowStart time.Time
+	count       int
+}
+
+type rateLimitRepository struct {
+	mu       sync.Mutex
+	counters map[string]*rateLimitCounter
+}
+
+// NewRateLimitRepository counters are not shared between processes,
+// so with fiber prefork every process has its own budget
+func NewRateLimitRepository() entity.IRateLimitRepository {
+	return &rateLimitRepository{
+		counters: make(map[string]*rateLimitCounter),
+	}
+}
+
+func (r *rateLimitRepository) Increment(_ context.Context, key string, windowStart time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counter, ok := r.counters[key]
+	if !ok {
+		counter = &rateLimitCounter{}
+		r.counters[key] = counter
+	}
+	if !counter.windowStart.Equal(windowStart) {
+		counter.windowStart = windowStart
+		counter.count = 0
+	}
+	counter.count++
+
+	return counter.count, nil
+}
+
+func (r *rateLimitRepository) DeleteWindowsBefore(_ context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, counter := range r.counters {
+		if counter.windowStart.Before(before) {
+			delete(r.counters, key)
+		}
+	}
+
+	return nil
+}
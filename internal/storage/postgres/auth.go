@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -26,7 +27,7 @@ func NewAuthRepository(db *pgxpool.Pool) entity.IAuthRepository {
 }
 
 func (r authRepository) GetByUsername(ctx context.Context, username string) (*entity.Auth, error) {
-	query, args, err := r.builder.Select("password").
+	query, args, err := r.builder.Select("password", "role").
 		From("users").
 		Where(squirrel.Eq{"username": username}).
 		ToSql()
@@ -43,6 +44,7 @@ func (r authRepository) GetByUsername(ctx context.Context, username string) (*en
 		args...,
 	).Scan(
 		&authDb.Password,
+		&authDb.Role,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -76,3 +78,85 @@ func (r authRepository) Register(ctx context.Context, authInfo *entity.Auth) err
 	}
 	return nil
 }
+
+func (r authRepository) UpdatePassword(ctx context.Context, authInfo *entity.Auth) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("create transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("%v: %w", rollbackErr, err)
+			}
+		}
+	}()
+
+	query, args, err := r.builder.Update("users").
+		Set("password", authInfo.Password).
+		// same clock as iat of access tokens, database clock may differ
+		Set("tokens_valid_after", time.Now().Truncate(time.Microsecond)).
+		Where(squirrel.Eq{"username": authInfo.Username}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building updating password query: %w", err)
+	}
+
+	tag, err := tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("updating user \"%s\" password: %w", authInfo.Username, err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = errs.UserNotFound
+		return err
+	}
+
+	query, args, err = r.builder.Update("refresh_tokens").
+		Set("revoked_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"username": authInfo.Username, "revoked_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building revoking user refresh tokens query: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("revoking user \"%s\" refresh tokens: %w", authInfo.Username, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("user \"%s\" updating password (commiting transaction error): %w",
+			authInfo.Username, err)
+	}
+	return nil
+}
+
+func (r authRepository) UpdatePasswordHash(ctx context.Context, authInfo *entity.Auth, oldHash string) error {
+	query, args, err := r.builder.Update("users").
+		Set("password", authInfo.Password).
+		Where(squirrel.Eq{"username": authInfo.Username, "password": oldHash}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("updating user \"%s\" password hash: %w", authInfo.Username, err)
+	}
+	return nil
+}
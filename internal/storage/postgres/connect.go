@@ -2,23 +2,33 @@ package postgres
 
 import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/tracing"
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func NewConn(ctx context.Context, cfg *config.PostgresConfig) (*pgxpool.Pool, error) {
-	connStr := fmt.Sprintf("%s://%s:%s@%s:%d/%s",
-		cfg.Driver,
-		cfg.User,
-		cfg.Password,
-		cfg.Host,
-		cfg.Port,
-		cfg.DBName,
-	)
+	// secrets from environment may contain any characters, so url is escaped
+	connURL := url.URL{
+		Scheme: cfg.Driver,
+		User:   url.UserPassword(cfg.User, cfg.Password),
+		Host:   net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Path:   cfg.DBName,
+	}
+	connStr := connURL.String()
+
+	poolConfig, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing connection string: %w", err)
+	}
+	poolConfig.ConnConfig.Tracer = tracing.NewQueryTracer()
 
-	pool, err := pgxpool.New(ctx, connStr)
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
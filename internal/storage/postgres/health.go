@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type healthRepository struct {
+	db      *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewHealthRepository(db *pgxpool.Pool) entity.IHealthRepository {
+	return &healthRepository{
+		db:      db,
+		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *healthRepository) Ping(ctx context.Context) error {
+	err := r.db.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("pinging database: %w", err)
+	}
+	return nil
+}
+
+// GetSchemaVersion reads version table of golang-migrate
+func (r *healthRepository) GetSchemaVersion(ctx context.Context) (*entity.SchemaVersion, error) {
+	query, args, err := r.builder.Select("version", "dirty").
+		From("schema_migrations").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+
+	version := new(entity.SchemaVersion)
+	err = r.db.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(&version.Version, &version.Dirty)
+	if err != nil {
+		return nil, fmt.Errorf("getting schema version: %w", err)
+	}
+	return version, nil
+}
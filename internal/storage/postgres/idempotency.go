@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// idempotencyKeyTTL is the time after which key can be reused, expired keys are deleted
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyKeyLease is the time after which key still in progress is taken over by retry,
+	// writes of the previous holder can not be committed then, its response is not saved
+	idempotencyKeyLease = time.Minute
+)
+
+type idempotencyRepository struct {
+	db      *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewIdempotencyRepository(db *pgxpool.Pool) entity.IIdempotencyRepository {
+	return &idempotencyRepository{
+		db:      db,
+		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context,
+	record *entity.IdempotencyRecord,
+) (*entity.IdempotencyRecord, error) {
+	// concurrent inserts with the same key are serialized by primary key, so only one of them reserves it,
+	// created_at identifies reservation, so holder whose key was taken over can not save response
+	now := time.Now()
+	query, args, err := r.builder.Insert("idempotency_keys").
+		Columns("username", "key", "request_hash").
+		Values(record.Username, record.Key, record.RequestHash).
+		Suffix(`on conflict (username, key) do update
+			set request_hash = excluded.request_hash, status_code = null, response = null,
+				created_at = current_timestamp
+			where idempotency_keys.created_at < ?
+				or (idempotency_keys.status_code is null and idempotency_keys.created_at < ?)
+			returning created_at`, now.Add(-idempotencyKeyTTL), now.Add(-idempotencyKeyLease)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building reserving idempotency key query: %w", err)
+	}
+
+	err = r.db.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(
+		&record.ReservedAt,
+	)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("reserving idempotency key: %w", err)
+	}
+	if err == nil { // reserved by this call
+		return nil, nil
+	}
+
+	query, args, err = r.builder.Select("request_hash", "status_code", "response").
+		From("idempotency_keys").
+		Where(squirrel.Eq{"username": record.Username, "key": record.Key}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building getting idempotency key query: %w", err)
+	}
+
+	stored := &entity.IdempotencyRecord{
+		Username: record.Username,
+		Key:      record.Key,
+	}
+	var statusCode *int
+	err = r.db.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(
+		&stored.RequestHash,
+		&statusCode,
+		&stored.Response,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) { // released between insert and select
+			return nil, errs.RequestInProgress
+		}
+		return nil, fmt.Errorf("getting idempotency key: %w", err)
+	}
+
+	if stored.RequestHash != record.RequestHash {
+		return nil, errs.KeyReused
+	}
+	if statusCode == nil {
+		return nil, errs.RequestInProgress
+	}
+	stored.StatusCode = *statusCode
+
+	return stored, nil
+}
+
+func (r *idempotencyRepository) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTransaction(ctx, r.db, fn)
+}
+
+// SaveResponse completes key in transaction of ctx, if reservation was taken over meanwhile
+// errs.RequestInProgress is returned, so transaction is rolled back
+func (r *idempotencyRepository) SaveResponse(ctx context.Context, record *entity.IdempotencyRecord) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("create transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("%v: %w", rollbackErr, err)
+			}
+		}
+	}()
+
+	query, args, err := r.builder.Update("idempotency_keys").
+		Set("status_code", record.StatusCode).
+		Set("response", record.Response).
+		Where(squirrel.Eq{
+			"username":    record.Username,
+			"key":         record.Key,
+			"status_code": nil,
+			"created_at":  record.ReservedAt,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building saving idempotency response query: %w", err)
+	}
+
+	tag, err := tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("saving idempotency response: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = fmt.Errorf("idempotency key was taken over: %w", errs.RequestInProgress)
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("saving idempotency response (commiting transaction error): %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, record *entity.IdempotencyRecord) error {
+	query, args, err := r.builder.Delete("idempotency_keys").
+		Where(squirrel.Eq{
+			"username":    record.Username,
+			"key":         record.Key,
+			"status_code": nil,
+			"created_at":  record.ReservedAt,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building releasing idempotency key query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	query, args, err := r.builder.Delete("idempotency_keys").
+		Where(squirrel.Lt{"created_at": now.Add(-idempotencyKeyTTL)}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting idempotency keys expired at %v: %w", now, err)
+	}
+	return nil
+}
@@ -4,11 +4,15 @@ import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
 	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -25,7 +29,19 @@ func NewItemRepository(db *pgxpool.Pool) entity.IItemRepository {
 }
 
 func (r *itemRepository) BuyItem(ctx context.Context, purchase *entity.Purchase) error {
-	tx, err := r.db.Begin(ctx)
+	return r.BuyItems(ctx, &entity.Cart{
+		Username: purchase.Username,
+		Lines: []*entity.CartLine{
+			{
+				ItemName: purchase.ItemName,
+				Quantity: 1,
+			},
+		},
+	})
+}
+
+func (r *itemRepository) BuyItems(ctx context.Context, cart *entity.Cart) error {
+	tx, err := begin(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("create transaction: %w", err)
 	}
@@ -38,25 +54,27 @@ func (r *itemRepository) BuyItem(ctx context.Context, purchase *entity.Purchase)
 		}
 	}()
 
-	itemPrice, err := r.checkUserCoinsForUpdate(ctx, tx, purchase)
+	items, totalPrice, err := r.checkUserCoinsForUpdate(ctx, tx, cart)
 	if err != nil {
 		return err
 	}
 
-	err = r.decreaseUserCoinsOnItemPrice(ctx, tx, purchase.Username, itemPrice)
+	err = r.decreaseUserCoinsOnItemPrice(ctx, tx, cart.Username, totalPrice)
 	if err != nil {
 		return err
 	}
 
-	err = r.savePurchaseHistory(ctx, tx, purchase)
-	if err != nil {
-		return err
+	for _, item := range items {
+		err = r.savePurchaseHistory(ctx, tx, cart.Username, item)
+		if err != nil {
+			return err
+		}
 	}
 
 	err = tx.Commit(ctx)
 	if err != nil {
-		return fmt.Errorf("user \"%s\" buing item \"%s\" (commiting transaction error): %w",
-			purchase.Username, purchase.ItemName, err)
+		return fmt.Errorf("user \"%s\" buing items (commiting transaction error): %w",
+			cart.Username, err)
 	}
 	return nil
 }
@@ -64,7 +82,7 @@ func (r *itemRepository) BuyItem(ctx context.Context, purchase *entity.Purchase)
 func (r *itemRepository) GetInventory(ctx context.Context, username string) ([]*entity.Item, error) {
 	query, args, err := r.builder.Select("item", "count(*)").
 		From("purchases").
-		Where(squirrel.Eq{"username": username}).
+		Where(squirrel.Eq{"username": username, "refunded_at": nil}).
 		GroupBy("item").
 		ToSql()
 	if err != nil {
@@ -95,56 +113,453 @@ func (r *itemRepository) GetInventory(ctx context.Context, username string) ([]*
 	return items, nil
 }
 
-func (r *itemRepository) checkUserCoinsForUpdate(ctx context.Context,
-	tx pgx.Tx, purchase *entity.Purchase,
-) (int32, error) {
-	query, args, err := r.builder.Select("coins").
-		From("users").
-		Where(squirrel.Eq{"username": purchase.Username}).
+func (r *itemRepository) GetPurchases(ctx context.Context,
+	filter *entity.PurchasesFilter,
+) ([]*entity.PurchaseRecord, error) {
+	builder := r.builder.Select("id::text", "time", "item", "price", "refunded_at is not null").
+		From("purchases").
+		Where(squirrel.Eq{"username": filter.Username})
+	if filter.From != nil {
+		builder = builder.Where(squirrel.GtOrEq{"time": *filter.From})
+	}
+	if filter.To != nil {
+		builder = builder.Where(squirrel.Lt{"time": *filter.To})
+	}
+	if filter.After != nil {
+		builder = builder.Where(squirrel.Expr("(time, id) < (?, ?::uuid)", filter.After.Time, filter.After.ID))
+	}
+
+	query, args, err := builder.
+		OrderBy("time desc", "id desc").
+		Limit(uint64(filter.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building getting purchases query: %w", err)
+	}
+
+	rows, err := r.db.Query(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting purchases: %w", err)
+	}
+	defer rows.Close()
+
+	purchases := make([]*entity.PurchaseRecord, 0)
+	for rows.Next() {
+		tmp := new(entity.PurchaseRecord)
+		err = rows.Scan(
+			&tmp.ID,
+			&tmp.Time,
+			&tmp.ItemName,
+			&tmp.Price,
+			&tmp.Refunded,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning purchase: %w", err)
+		}
+		purchases = append(purchases, tmp)
+	}
+
+	return purchases, nil
+}
+
+// RefundPurchase returns purchase price to user, marks purchase as refunded,
+// returns unit to item stock and saves refund audit record in one transaction
+func (r *itemRepository) RefundPurchase(ctx context.Context, refund *entity.Refund) (*entity.PurchaseRecord, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("create transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("%v: %w", rollbackErr, err)
+			}
+		}
+	}()
+
+	purchase, err := r.getPurchaseForRefund(ctx, tx, refund)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.increaseUserCoins(ctx, tx, refund.Username, purchase.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.builder.Update("purchases").
+		Set("refunded_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"id": purchase.ID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building marking purchase refunded query: %w", err)
+	}
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("marking purchase \"%s\" refunded: %w", purchase.ID, err)
+	}
+
+	query, args, err = r.builder.Update("items").
+		Set("stock", squirrel.Expr("stock + 1")).
+		Where(squirrel.Eq{"name": purchase.ItemName}).
+		Where(squirrel.NotEq{"stock": nil}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building increasing item stock query: %w", err)
+	}
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("increasing item \"%s\" stock: %w", purchase.ItemName, err)
+	}
+
+	query, args, err = r.builder.Insert("purchase_refunds").
+		Columns("purchase_id", "username", "item", "coins").
+		Values(purchase.ID, refund.Username, purchase.ItemName, purchase.Price).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building saving refund query: %w", err)
+	}
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("saving purchase \"%s\" refund: %w", purchase.ID, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("user \"%s\" refunding purchase \"%s\" (commiting transaction error): %w",
+			refund.Username, purchase.ID, err)
+	}
+
+	purchase.Refunded = true
+	return purchase, nil
+}
+
+// getPurchaseForRefund locks user purchase row and checks that it can be refunded
+func (r *itemRepository) getPurchaseForRefund(ctx context.Context,
+	tx pgx.Tx, refund *entity.Refund,
+) (*entity.PurchaseRecord, error) {
+	query, args, err := r.builder.Select("id::text", "time", "item", "price", "refunded_at is not null").
+		Column(squirrel.Expr("time >= current_timestamp - make_interval(secs => ?)", refund.Window.Seconds())).
+		From("purchases").
+		Where(squirrel.Expr("id = ?::uuid", refund.PurchaseID)).
+		Where(squirrel.Eq{"username": refund.Username}).
 		Suffix("for update").
 		ToSql()
 	if err != nil {
-		return 0, fmt.Errorf("building getting user coins query: %w", err)
+		return nil, fmt.Errorf("building getting purchase query: %w", err)
 	}
 
-	var userCoins int32
+	purchase := new(entity.PurchaseRecord)
+	var inWindow bool
 	err = tx.QueryRow(
 		ctx,
 		query,
 		args...,
 	).Scan(
-		&userCoins,
+		&purchase.ID,
+		&purchase.Time,
+		&purchase.ItemName,
+		&purchase.Price,
+		&purchase.Refunded,
+		&inWindow,
 	)
 	if err != nil {
-		return 0, errs.UserNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errs.PurchaseNotFound
+			return nil, err
+		}
+		return nil, fmt.Errorf("getting purchase \"%s\": %w", refund.PurchaseID, err)
 	}
 
-	query, args, err = r.builder.Select("price").
+	if purchase.Refunded {
+		err = errs.AlreadyRefunded
+		return nil, err
+	}
+	if !inWindow {
+		err = errs.RefundExpired
+		return nil, err
+	}
+	return purchase, nil
+}
+
+func (r *itemRepository) increaseUserCoins(ctx context.Context, tx pgx.Tx, username string, coins int32) error {
+	query, args, err := r.builder.Update("users").
+		Set("coins", squirrel.Expr("coins + ?", coins)).
+		Where(squirrel.Eq{"username": username}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building updating user coins query: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("updating user \"%s\" coins: %w", username, err)
+	}
+
+	return nil
+}
+
+func (r *itemRepository) GetItems(ctx context.Context, filter *entity.ItemsFilter) ([]*entity.Item, error) {
+	builder := r.builder.Select("name", "price", "stock").
 		From("items").
-		Where(squirrel.Eq{"name": purchase.ItemName}).
+		Where(squirrel.Eq{"retired": false})
+	if filter.MinPrice != nil {
+		builder = builder.Where(squirrel.GtOrEq{"price": *filter.MinPrice})
+	}
+	if filter.MaxPrice != nil {
+		builder = builder.Where(squirrel.LtOrEq{"price": *filter.MaxPrice})
+	}
+	switch filter.Sort {
+	case entity.SortByPriceAsc:
+		builder = builder.OrderBy("price asc", "name")
+	case entity.SortByPriceDesc:
+		builder = builder.OrderBy("price desc", "name")
+	default:
+		builder = builder.OrderBy("name")
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building getting items query: %w", err)
+	}
+
+	rows, err := r.db.Query(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Item, 0)
+	for rows.Next() {
+		tmp := new(entity.Item)
+		err = rows.Scan(
+			&tmp.Name,
+			&tmp.Price,
+			&tmp.Stock,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning item: %w", err)
+		}
+		items = append(items, tmp)
+	}
+
+	return items, nil
+}
+
+func (r *itemRepository) GetItem(ctx context.Context, name string) (*entity.Item, error) {
+	query, args, err := r.builder.Select("name", "price", "stock").
+		From("items").
+		Where(squirrel.Eq{"name": name, "retired": false}).
 		ToSql()
 	if err != nil {
-		return 0, fmt.Errorf("building getting item price query: %w", err)
+		return nil, fmt.Errorf("building getting item query: %w", err)
 	}
 
-	var itemPrice int32
+	item := new(entity.Item)
+	err = r.db.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(
+		&item.Name,
+		&item.Price,
+		&item.Stock,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errs.ItemNotFound
+		}
+		return nil, fmt.Errorf("getting item \"%s\": %w", name, err)
+	}
+
+	return item, nil
+}
+
+func (r *itemRepository) CreateItem(ctx context.Context, item *entity.Item) error {
+	query, args, err := r.builder.Insert("items").
+		Columns("name", "price", "stock").
+		Values(item.Name, item.Price, item.Stock).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building creating item query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == errs.UniqueConstraintSQLState {
+			return errs.ItemAlreadyExists
+		}
+		return fmt.Errorf("creating item \"%s\": %w", item.Name, err)
+	}
+	return nil
+}
+
+func (r *itemRepository) UpdateItemPrice(ctx context.Context, item *entity.Item) error {
+	query, args, err := r.builder.Update("items").
+		Set("price", item.Price).
+		Where(squirrel.Eq{"name": item.Name, "retired": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building updating item price query: %w", err)
+	}
+
+	tag, err := r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("updating item \"%s\" price: %w", item.Name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ItemNotFound
+	}
+	return nil
+}
+
+// UpdateItemStock sets remaining stock, nil stock makes item unlimited
+func (r *itemRepository) UpdateItemStock(ctx context.Context, item *entity.Item) error {
+	query, args, err := r.builder.Update("items").
+		Set("stock", item.Stock).
+		Where(squirrel.Eq{"name": item.Name, "retired": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building updating item stock query: %w", err)
+	}
+
+	tag, err := r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("updating item \"%s\" stock: %w", item.Name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ItemNotFound
+	}
+	return nil
+}
+
+// RetireItem hides item from catalog and blocks new purchases,
+// existing purchases keep referencing it
+func (r *itemRepository) RetireItem(ctx context.Context, name string) error {
+	query, args, err := r.builder.Update("items").
+		Set("retired", true).
+		Where(squirrel.Eq{"name": name, "retired": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building retiring item query: %w", err)
+	}
+
+	tag, err := r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("retiring item \"%s\": %w", name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.ItemNotFound
+	}
+	return nil
+}
+
+// checkUserCoinsForUpdate locks user row, takes requested quantity from stock of limited items
+// (in name order to avoid deadlocks) and returns items with requested quantity and total price of the cart.
+// Rows of unlimited items are not locked, so buyers of popular item do not wait for each other
+func (r *itemRepository) checkUserCoinsForUpdate(ctx context.Context,
+	tx pgx.Tx, cart *entity.Cart,
+) ([]*entity.Item, int32, error) {
+	query, args, err := r.builder.Select("coins").
+		From("users").
+		Where(squirrel.Eq{"username": cart.Username}).
+		Suffix("for update").
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("building getting user coins query: %w", err)
+	}
+
+	var userCoins int32
 	err = tx.QueryRow(
 		ctx,
 		query,
 		args...,
 	).Scan(
-		&itemPrice,
+		&userCoins,
 	)
 	if err != nil {
-		return 0, errs.ItemNotFound
+		return nil, 0, errs.UserNotFound
+	}
+
+	lines := slices.Clone(cart.Lines)
+	slices.SortFunc(lines, func(a, b *entity.CartLine) int {
+		return strings.Compare(a.ItemName, b.ItemName)
+	})
+
+	items := make([]*entity.Item, 0, len(lines))
+	var totalPrice int64
+	for _, line := range lines {
+		query, args, err = r.builder.Select("price", "stock").
+			From("items").
+			Where(squirrel.Eq{"name": line.ItemName, "retired": false}).
+			ToSql()
+		if err != nil {
+			return nil, 0, fmt.Errorf("building getting item price query: %w", err)
+		}
+
+		item := &entity.Item{
+			Name:     line.ItemName,
+			Quantity: line.Quantity,
+		}
+		err = tx.QueryRow(
+			ctx,
+			query,
+			args...,
+		).Scan(
+			&item.Price,
+			&item.Stock,
+		)
+		if err != nil {
+			return nil, 0, errs.ItemNotFound
+		}
+
+		if item.Stock != nil {
+			err = r.decreaseItemStock(ctx, tx, item)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+
+		totalPrice += int64(item.Price) * int64(item.Quantity)
+		items = append(items, item)
 	}
 
-	if userCoins < itemPrice {
+	if int64(userCoins) < totalPrice {
 		err = errs.NotEnoughCoins
-		return 0, err
+		return nil, 0, err
 	}
 
-	return itemPrice, nil
+	return items, int32(totalPrice), nil
 }
 
 func (r *itemRepository) decreaseUserCoinsOnItemPrice(ctx context.Context,
@@ -170,10 +585,44 @@ func (r *itemRepository) decreaseUserCoinsOnItemPrice(ctx context.Context,
 	return nil
 }
 
-func (r *itemRepository) savePurchaseHistory(ctx context.Context, tx pgx.Tx, purchase *entity.Purchase) error {
+// decreaseItemStock takes quantity from stock only if there is enough units left,
+// concurrent buyers are serialized by row lock of the update
+func (r *itemRepository) decreaseItemStock(ctx context.Context, tx pgx.Tx, item *entity.Item) error {
+	query, args, err := r.builder.Update("items").
+		Set("stock", squirrel.Expr("stock - ?", item.Quantity)).
+		Where(squirrel.Eq{"name": item.Name}).
+		Where(squirrel.GtOrEq{"stock": item.Quantity}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building decreasing item stock query: %w", err)
+	}
+
+	tag, err := tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("decreasing item \"%s\" stock: %w", item.Name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.OutOfStock
+	}
+
+	return nil
+}
+
+// savePurchaseHistory saves one purchase row per bought unit of item,
+// price is saved so that later repricing does not change history.
+// Rows are generated by database, so query size does not depend on quantity
+func (r *itemRepository) savePurchaseHistory(ctx context.Context, tx pgx.Tx, username string, item *entity.Item) error {
 	query, args, err := r.builder.Insert("purchases").
-		Columns("username", "item").
-		Values(purchase.Username, purchase.ItemName).
+		Columns("username", "item", "price").
+		Select(r.builder.Select().
+			Column(squirrel.Expr("?::varchar", username)).
+			Column(squirrel.Expr("?::varchar", item.Name)).
+			Column(squirrel.Expr("?::int", item.Price)).
+			FromSelect(r.builder.Select().Column(squirrel.Expr("generate_series(1, ?::int)", item.Quantity)), "units")).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("building creating purchase query: %w", err)
@@ -186,7 +635,7 @@ func (r *itemRepository) savePurchaseHistory(ctx context.Context, tx pgx.Tx, pur
 	)
 	if err != nil {
 		return fmt.Errorf("creating user \"%s\" item \"%s\" purchase: %w",
-			purchase.Username, purchase.ItemName, err)
+			username, item.Name, err)
 	}
 
 	return nil
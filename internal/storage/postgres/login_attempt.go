@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type loginAttemptRepository struct {
+	db      *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewLoginAttemptRepository(db *pgxpool.Pool) entity.ILoginAttemptRepository {
+	return &loginAttemptRepository{
+		db:      db,
+		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// ReserveAttempt holds advisory locks of attempt username and ip until attempt is saved,
+// locks are always taken in the same order, so concurrent attempts do not deadlock
+func (r *loginAttemptRepository) ReserveAttempt(ctx context.Context,
+	attempt *entity.LoginAttempt, since time.Time,
+) (*entity.LoginFailures, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("create transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("%v: %w", rollbackErr, err)
+			}
+		}
+	}()
+
+	query, args, err := r.builder.Select().
+		Column(squirrel.Expr("pg_advisory_xact_lock(hashtext(?))", "login_username:"+attempt.Username)).
+		Column(squirrel.Expr("pg_advisory_xact_lock(hashtext(?))", "login_ip:"+attempt.IP)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building locking query: %w", err)
+	}
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("locking user \"%s\" (ip %s) login attempts: %w", attempt.Username, attempt.IP, err)
+	}
+
+	query, args, err = r.builder.Select().
+		Column(squirrel.Expr("count(*) filter (where username = ? and not username_cleared)", attempt.Username)).
+		Column(squirrel.Expr("coalesce(max(time) filter (where username = ? and not username_cleared), to_timestamp(0))",
+			attempt.Username)).
+		Column(squirrel.Expr("count(*) filter (where ip = ?)", attempt.IP)).
+		Column(squirrel.Expr("coalesce(max(time) filter (where ip = ?), to_timestamp(0))", attempt.IP)).
+		From("failed_logins").
+		Where(squirrel.Or{
+			squirrel.Eq{"username": attempt.Username},
+			squirrel.Eq{"ip": attempt.IP},
+		}).
+		Where(squirrel.Gt{"time": since}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+
+	failures := new(entity.LoginFailures)
+	err = tx.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(
+		&failures.UsernameCount,
+		&failures.UsernameLastFailure,
+		&failures.IPCount,
+		&failures.IPLastFailure,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting user \"%s\" (ip %s) login failures: %w", attempt.Username, attempt.IP, err)
+	}
+	if failures.UsernameCount == 0 {
+		failures.UsernameLastFailure = time.Time{}
+	}
+	if failures.IPCount == 0 {
+		failures.IPLastFailure = time.Time{}
+	}
+
+	query, args, err = r.builder.Insert("failed_logins").
+		Columns("username", "ip", "time").
+		Values(attempt.Username, attempt.IP, attempt.Time).
+		Suffix("returning id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+	err = tx.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(
+		&attempt.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("saving user \"%s\" (ip %s) login attempt: %w", attempt.Username, attempt.IP, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("user \"%s\" (ip %s) reserving login attempt (commiting transaction error): %w",
+			attempt.Username, attempt.IP, err)
+	}
+	return failures, nil
+}
+
+func (r *loginAttemptRepository) DeleteAttempt(ctx context.Context, id int64) error {
+	query, args, err := r.builder.Delete("failed_logins").
+		Where(squirrel.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting login attempt %d: %w", id, err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) ClearUsernameFailures(ctx context.Context, username string) error {
+	query, args, err := r.builder.Update("failed_logins").
+		Set("username_cleared", true).
+		Where(squirrel.Eq{"username": username, "username_cleared": false}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("clearing user \"%s\" login failures: %w", username, err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) DeleteFailuresBefore(ctx context.Context, before time.Time) error {
+	query, args, err := r.builder.Delete("failed_logins").
+		Where(squirrel.Lt{"time": before}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting login failures before %v: %w", before, err)
+	}
+	return nil
+}
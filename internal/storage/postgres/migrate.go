@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/migrations"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// SchemaVersion is the latest embedded migration, readiness fails until database reaches it
+var SchemaVersion = latestSchemaVersion()
+
+// Migration is embedded migration and whether it is applied to database
+type Migration struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// Migrator applies migrations embedded into binary, state is kept in schema_migrations table of golang-migrate.
+// Concurrent migrators (e.g. several instances started at once) are serialized by advisory lock
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+func NewMigrator(pool *pgxpool.Pool) (*Migrator, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded migrations: %w", err)
+	}
+	// connections are taken from pool and returned to it when migrator is closed
+	driver, err := pgxmigrate.WithInstance(stdlib.OpenDBFromPool(pool), &pgxmigrate.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("creating migration driver: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "pgx5", driver)
+	if err != nil {
+		return nil, fmt.Errorf("creating migrator: %w", err)
+	}
+	return &Migrator{m: m}, nil
+}
+
+// Up applies all pending migrations, nothing to apply is not an error
+func (m *Migrator) Up() error {
+	err := m.m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("applying migrations: %w", err)
+	}
+	return nil
+}
+
+// Down rolls back last steps migrations
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive")
+	}
+	err := m.m.Steps(-steps)
+	if err != nil {
+		return fmt.Errorf("rolling back migrations: %w", err)
+	}
+	return nil
+}
+
+// Version returns version of the last applied migration, zero if none is applied.
+// Dirty version means migration failed and database must be fixed manually
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("getting schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// Status lists embedded migrations in order of versions
+func (m *Migrator) Status() ([]*Migration, bool, error) {
+	version, dirty, err := m.Version()
+	if err != nil {
+		return nil, false, err
+	}
+	list, err := embeddedMigrations()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, migration := range list {
+		migration.Applied = migration.Version <= version
+	}
+	return list, dirty, nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+func embeddedMigrations() ([]*Migration, error) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded migrations: %w", err)
+	}
+	list := make([]*Migration, 0, len(entries)/2)
+	for _, entry := range entries {
+		parsed, err := source.DefaultParse(entry.Name())
+		if err != nil || parsed.Direction != source.Up {
+			continue
+		}
+		list = append(list, &Migration{
+			Version: parsed.Version,
+			Name:    parsed.Identifier,
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
+
+func latestSchemaVersion() int64 {
+	list, err := embeddedMigrations()
+	if err != nil || len(list) == 0 {
+		panic(fmt.Sprintf("no embedded migrations: %v", err))
+	}
+	return int64(list[len(list)-1].Version)
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type rateLimitRepository struct {
+	db      *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+// NewRateLimitRepository keeps one row per key, so counters are shared between processes (fiber prefork)
+func NewRateLimitRepository(db *pgxpool.Pool) entity.IRateLimitRepository {
+	return &rateLimitRepository{
+		db:      db,
+		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *rateLimitRepository) Increment(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	query, args, err := r.builder.Insert("rate_limits").
+		Columns("key", "window_start", "count").
+		Values(key, windowStart, 1).
+		Suffix(`on conflict (key) do update set
+			count = case when rate_limits.window_start = excluded.window_start then rate_limits.count + 1 else 1 end,
+			window_start = excluded.window_start
+			returning count`).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("building query: %w", err)
+	}
+
+	var count int
+	err = r.db.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("incrementing rate limit counter \"%s\": %w", key, err)
+	}
+	return count, nil
+}
+
+func (r *rateLimitRepository) DeleteWindowsBefore(ctx context.Context, before time.Time) error {
+	query, args, err := r.builder.Delete("rate_limits").
+		Where(squirrel.Lt{"window_start": before}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting rate limit counters before %v: %w", before, err)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package postgres
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type tokenRepository struct {
+	db      *pgxpool.Pool
+	builder squirrel.StatementBuilderType
+}
+
+func NewTokenRepository(db *pgxpool.Pool) entity.ITokenRepository {
+	return &tokenRepository{
+		db:      db,
+		builder: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+func (r *tokenRepository) SaveRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	query, args, err := r.builder.Insert("refresh_tokens").
+		Columns("username", "token_hash", "expires_at").
+		Values(token.Username, token.TokenHash, token.ExpiresAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building saving refresh token query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("saving user \"%s\" refresh token: %w", token.Username, err)
+	}
+	return nil
+}
+
+// RotateRefreshToken also revokes all user refresh tokens if already revoked token is presented,
+// as it means that token was stolen (or client retried refresh)
+func (r *tokenRepository) RotateRefreshToken(ctx context.Context,
+	oldHash string, newToken *entity.RefreshToken,
+) (*entity.Auth, error) {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("create transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("%v: %w", rollbackErr, err)
+			}
+		}
+	}()
+
+	query, args, err := r.builder.Select("refresh_tokens.username", "users.role",
+		"refresh_tokens.revoked_at is not null", "refresh_tokens.expires_at < current_timestamp").
+		From("refresh_tokens").
+		Join("users on users.username = refresh_tokens.username").
+		Where(squirrel.Eq{"refresh_tokens.token_hash": oldHash}).
+		Suffix("for update of refresh_tokens").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building getting refresh token query: %w", err)
+	}
+
+	user := new(entity.Auth)
+	var revoked, expired bool
+	err = tx.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(
+		&user.Username,
+		&user.Role,
+		&revoked,
+		&expired,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errs.InvalidToken
+			return nil, err
+		}
+		return nil, fmt.Errorf("getting refresh token: %w", err)
+	}
+
+	if revoked {
+		err = r.revokeUserRefreshTokens(ctx, tx, user.Username)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.Commit(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("user \"%s\" revoking refresh tokens (commiting transaction error): %w",
+				user.Username, err)
+		}
+		return nil, errs.InvalidToken
+	}
+	if expired {
+		err = errs.InvalidToken
+		return nil, err
+	}
+
+	query, args, err = r.builder.Update("refresh_tokens").
+		Set("revoked_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"token_hash": oldHash}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building revoking refresh token query: %w", err)
+	}
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("revoking user \"%s\" refresh token: %w", user.Username, err)
+	}
+
+	query, args, err = r.builder.Insert("refresh_tokens").
+		Columns("username", "token_hash", "expires_at").
+		Values(user.Username, newToken.TokenHash, newToken.ExpiresAt).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building saving refresh token query: %w", err)
+	}
+	_, err = tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("saving user \"%s\" refresh token: %w", user.Username, err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("user \"%s\" rotating refresh token (commiting transaction error): %w",
+			user.Username, err)
+	}
+	return user, nil
+}
+
+func (r *tokenRepository) revokeUserRefreshTokens(ctx context.Context, tx pgx.Tx, username string) error {
+	query, args, err := r.builder.Update("refresh_tokens").
+		Set("revoked_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"username": username, "revoked_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building revoking user refresh tokens query: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("revoking user \"%s\" refresh tokens: %w", username, err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeRefreshToken(ctx context.Context, username string, tokenHash string) error {
+	query, args, err := r.builder.Update("refresh_tokens").
+		Set("revoked_at", squirrel.Expr("current_timestamp")).
+		Where(squirrel.Eq{"username": username, "token_hash": tokenHash, "revoked_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building revoking refresh token query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("revoking user \"%s\" refresh token: %w", username, err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeAccessToken(ctx context.Context, token *entity.RevokedToken) error {
+	query, args, err := r.builder.Insert("revoked_tokens").
+		Columns("jti", "username", "expires_at").
+		Values(token.JTI, token.Username, token.ExpiresAt).
+		Suffix("on conflict (jti) do nothing").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building revoking access token query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("revoking user \"%s\" access token: %w", token.Username, err)
+	}
+
+	// expired tokens are rejected by signature check anyway, no need to keep them
+	query, args, err = r.builder.Delete("revoked_tokens").
+		Where(squirrel.Expr("expires_at < current_timestamp")).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building deleting expired revoked tokens query: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("deleting expired revoked tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(ctx context.Context, token *entity.AccessToken) (bool, error) {
+	// iat and tokens_valid_after are both set by application with microseconds precision
+	query, args, err := r.builder.Select("1").
+		From("revoked_tokens").
+		Where(squirrel.Eq{"jti": token.JTI}).
+		Prefix("select exists (").
+		Suffix(`) or exists (
+			select 1 from users
+			where username = ? and tokens_valid_after >= ?
+		)`, token.Username, token.IssuedAt).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("building checking revoked token query: %w", err)
+	}
+
+	var revoked bool
+	err = r.db.QueryRow(
+		ctx,
+		query,
+		args...,
+	).Scan(
+		&revoked,
+	)
+	if err != nil {
+		return false, fmt.Errorf("checking revoked token: %w", err)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type txKey struct{}
+
+// begin starts transaction of repository method, if ctx carries transaction of inTransaction
+// nested transaction (savepoint) is started in it, so writes are committed only with the outer one
+func begin(ctx context.Context, db *pgxpool.Pool) (pgx.Tx, error) {
+	if outer, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return outer.Begin(ctx)
+	}
+	return db.Begin(ctx)
+}
+
+// inTransaction runs fn in transaction passed to repositories through ctx, it is committed if fn succeeds
+func inTransaction(ctx context.Context, db *pgxpool.Pool, fn func(ctx context.Context) error) (err error) {
+	tx, err := begin(ctx, db)
+	if err != nil {
+		return fmt.Errorf("create transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("%v: %w", rollbackErr, err)
+			}
+		}
+	}()
+
+	err = fn(context.WithValue(ctx, txKey{}, tx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commiting transaction: %w", err)
+	}
+	return nil
+}
@@ -25,7 +25,7 @@ func NewUserRepository(db *pgxpool.Pool) entity.IUserRepository {
 }
 
 func (r *userRepository) SendCoins(ctx context.Context, transfer *entity.TransferCoins) error {
-	tx, err := r.db.Begin(ctx)
+	tx, err := begin(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("create transaction: %w", err)
 	}
@@ -60,6 +60,66 @@ func (r *userRepository) SendCoins(ctx context.Context, transfer *entity.Transfe
 	return nil
 }
 
+func (r *userRepository) GrantCoins(ctx context.Context, grant *entity.GrantCoins) error {
+	tx, err := begin(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("create transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback(ctx)
+			if rollbackErr != nil {
+				err = fmt.Errorf("%v: %w", rollbackErr, err)
+			}
+		}
+	}()
+
+	query, args, err := r.builder.Update("users").
+		Set("coins", squirrel.Expr("coins + ?", grant.Amount)).
+		Where(squirrel.Eq{"username": grant.Username}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building granting user \"%s\" coins query: %w", grant.Username, err)
+	}
+
+	tag, err := tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("granting user \"%s\" coins: %w", grant.Username, err)
+	}
+	if tag.RowsAffected() == 0 {
+		err = errs.UserNotFound
+		return err
+	}
+
+	// transaction without sender marks coins granted by admin
+	query, args, err = r.builder.Insert("transactions").
+		Columns("toUser", "coins", "comment").
+		Values(grant.Username, grant.Amount, grant.Comment).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building saving grant history query: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("saving grant history: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("commiting transaction error: %w", err)
+	}
+	return nil
+}
+
 func (r *userRepository) GetCoinsHistory(ctx context.Context, username string) (int32, *entity.CoinsHistory, error) {
 	coins, err := r.getUserCoins(ctx, username)
 	if err != nil {
@@ -74,6 +134,70 @@ func (r *userRepository) GetCoinsHistory(ctx context.Context, username string) (
 	return coins, coinsHistory, nil
 }
 
+func (r *userRepository) GetTransactions(ctx context.Context,
+	filter *entity.TransactionsFilter,
+) ([]*entity.Transaction, error) {
+	builder := r.builder.Select("id::text", "time", "coalesce(fromUser, '')", "toUser", "coins", "comment").
+		From("transactions")
+	switch filter.Direction {
+	case entity.DirectionSent:
+		builder = builder.Where(squirrel.Eq{"fromUser": filter.Username})
+	case entity.DirectionReceived:
+		builder = builder.Where(squirrel.Eq{"toUser": filter.Username})
+	default:
+		builder = builder.Where(squirrel.Or{
+			squirrel.Eq{"fromUser": filter.Username},
+			squirrel.Eq{"toUser": filter.Username},
+		})
+	}
+	if filter.From != nil {
+		builder = builder.Where(squirrel.GtOrEq{"time": *filter.From})
+	}
+	if filter.To != nil {
+		builder = builder.Where(squirrel.Lt{"time": *filter.To})
+	}
+	if filter.After != nil {
+		builder = builder.Where(squirrel.Expr("(time, id) < (?, ?::uuid)", filter.After.Time, filter.After.ID))
+	}
+
+	query, args, err := builder.
+		OrderBy("time desc", "id desc").
+		Limit(uint64(filter.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building getting transactions query: %w", err)
+	}
+
+	rows, err := r.db.Query(
+		ctx,
+		query,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]*entity.Transaction, 0)
+	for rows.Next() {
+		tmp := new(entity.Transaction)
+		err = rows.Scan(
+			&tmp.ID,
+			&tmp.Time,
+			&tmp.FromUser,
+			&tmp.ToUser,
+			&tmp.Amount,
+			&tmp.Comment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning transaction: %w", err)
+		}
+		transactions = append(transactions, tmp)
+	}
+
+	return transactions, nil
+}
+
 func (r *userRepository) checkUsersCoinsForUpdate(ctx context.Context,
 	tx pgx.Tx, transfer *entity.TransferCoins,
 ) error {
@@ -174,8 +298,8 @@ func (r *userRepository) updateUsersCoins(ctx context.Context, tx pgx.Tx, transf
 
 func (r *userRepository) saveTransactionHistory(ctx context.Context, tx pgx.Tx, transfer *entity.TransferCoins) error {
 	query, args, err := r.builder.Insert("transactions").
-		Columns("fromUser", "toUser", "coins").
-		Values(transfer.FromUser, transfer.ToUser, transfer.Amount).
+		Columns("fromUser", "toUser", "coins", "comment").
+		Values(transfer.FromUser, transfer.ToUser, transfer.Amount, transfer.Comment).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("building saving transaction history query: %w", err)
@@ -221,7 +345,8 @@ func (r *userRepository) getUserCoins(ctx context.Context, username string) (int
 }
 
 func (r *userRepository) getUserTransactions(ctx context.Context, username string) (*entity.CoinsHistory, error) {
-	query, args, err := r.builder.Select("fromUser", "coins").
+	// sender of coins granted by admin is empty
+	query, args, err := r.builder.Select("coalesce(fromUser, '')", "coins", "comment").
 		From("transactions").
 		Where(squirrel.Eq{"toUser": username}).
 		OrderBy("time desc").
@@ -241,12 +366,13 @@ func (r *userRepository) getUserTransactions(ctx context.Context, username strin
 	defer rows.Close()
 
 	coinsHistory := new(entity.CoinsHistory)
-	coinsHistory.Received = make([]*entity.User, 0)
+	coinsHistory.Received = make([]*entity.CoinsHistoryEntry, 0)
 	for rows.Next() {
-		tmp := new(entity.User)
+		tmp := new(entity.CoinsHistoryEntry)
 		err = rows.Scan(
 			&tmp.Username,
 			&tmp.Coins,
+			&tmp.Comment,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning transaction from user: %w", err)
@@ -254,7 +380,7 @@ func (r *userRepository) getUserTransactions(ctx context.Context, username strin
 		coinsHistory.Received = append(coinsHistory.Received, tmp)
 	}
 
-	query, args, err = r.builder.Select("toUser", "coins").
+	query, args, err = r.builder.Select("toUser", "coins", "comment").
 		From("transactions").
 		Where(squirrel.Eq{"fromUser": username}).
 		OrderBy("time desc").
@@ -273,12 +399,13 @@ func (r *userRepository) getUserTransactions(ctx context.Context, username strin
 	}
 	defer rows.Close()
 
-	coinsHistory.Sent = make([]*entity.User, 0)
+	coinsHistory.Sent = make([]*entity.CoinsHistoryEntry, 0)
 	for rows.Next() {
-		tmp := new(entity.User)
+		tmp := new(entity.CoinsHistoryEntry)
 		err = rows.Scan(
 			&tmp.Username,
 			&tmp.Coins,
+			&tmp.Comment,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scanning transaction to user: %w", err)
@@ -7,40 +7,174 @@ import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/web/jwt"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
 	"errors"
-	"fmt"
-	"log"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func errorMap(errText string) *fiber.Map {
-	return &fiber.Map{
-		"errors": errText,
-	}
-}
-
 func AuthHandler(app *app.App) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		const prompt = "Authorization"
 		var req models.Auth
 		err := ctx.BodyParser(&req)
 		if err != nil {
-			return ctx.Status(fiber.StatusBadRequest).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, errs.InvalidData.Error())))
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		attempt := &entity.LoginAttempt{
+			Username: req.Username,
+			IP:       ctx.IP(),
+			Time:     time.Now(),
+		}
+		// attempt is counted as failed until password is verified
+		err = app.LoginAttemptService.Reserve(ctx.UserContext(), attempt)
+		if err != nil {
+			return errs.New(prompt, err)
 		}
 
 		ua := models.ToAuthEntity(&req)
-		token, err := app.AuthService.Auth(ctx.Context(), ua)
-		if err != nil {
-			if errors.Is(err, errs.InvalidData) {
-				return ctx.Status(fiber.StatusBadRequest).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
-			} else if errors.Is(err, errs.InvalidCredentials) {
-				return ctx.Status(fiber.StatusUnauthorized).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
-			} else {
-				return ctx.Status(fiber.StatusInternalServerError).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
+		tokens, err := app.AuthService.Auth(ctx.UserContext(), ua)
+		if err != nil {
+			if !errors.Is(err, errs.InvalidCredentials) {
+				// errors are logged by service, they must not change login response
+				_ = app.LoginAttemptService.Release(ctx.UserContext(), attempt)
+			}
+			return errs.New(prompt, err)
+		}
+		_ = app.LoginAttemptService.Succeeded(ctx.UserContext(), attempt)
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToAuthResponseTransport(tokens))
+	}
+}
+
+func RegisterHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Registration"
+		var req models.Auth
+		err := ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		tokens, err := app.AuthService.Register(ctx.UserContext(), models.ToAuthEntity(&req))
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(models.ToAuthResponseTransport(tokens))
+	}
+}
+
+func ChangePasswordHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Changing password"
+
+		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		var req models.PasswordChange
+		err = ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		tokens, err := app.AuthService.ChangePassword(ctx.UserContext(), &entity.PasswordChange{
+			Username:    username,
+			OldPassword: req.OldPassword,
+			NewPassword: req.NewPassword,
+		})
+		if err != nil {
+			// token is valid, so wrong old password forbids the change rather than fails authentication
+			return errs.New(prompt, err).WithStatus(fiber.StatusForbidden, errs.InvalidCredentials)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToAuthResponseTransport(tokens))
+	}
+}
+
+func ResetPasswordHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Resetting password"
+
+		var req models.PasswordReset
+		err := ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		err = app.AuthService.ResetPassword(ctx.UserContext(), &entity.Auth{
+			Username: ctx.Params("username"),
+			Password: req.Password,
+		})
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.SendStatus(fiber.StatusOK)
+	}
+}
+
+func RefreshHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Refreshing token"
+		var req models.RefreshToken
+		err := ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		tokens, err := app.AuthService.Refresh(ctx.UserContext(), req.RefreshToken)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToAuthResponseTransport(tokens))
+	}
+}
+
+func LogoutHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Logout"
+
+		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+		jti, err := jwt.FGetStringClaimFromJWT(ctx, "jti")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+		expiresAt, err := jwt.FGetExpirationFromJWT(ctx)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		// refresh token is optional, so empty body is allowed
+		var req models.RefreshToken
+		if len(ctx.Body()) > 0 {
+			err = ctx.BodyParser(&req)
+			if err != nil {
+				return errs.New(prompt, errs.InvalidData)
 			}
 		}
 
-		return ctx.Status(fiber.StatusOK).JSON(models.AuthResponse{Token: token})
+		err = app.AuthService.Logout(ctx.UserContext(), &entity.Logout{
+			Username:     username,
+			JTI:          jti,
+			ExpiresAt:    expiresAt,
+			RefreshToken: req.RefreshToken,
+		})
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.SendStatus(fiber.StatusOK)
 	}
 }
 
@@ -51,20 +185,41 @@ func BuyItemHandler(app *app.App) fiber.Handler {
 		itemName := ctx.Params("item")
 		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
 		if err != nil {
-			return ctx.Status(fiber.StatusUnauthorized).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, "invalid token")))
+			return errs.New(prompt, errs.InvalidToken)
 		}
 
 		purchase := &entity.Purchase{
 			Username: username,
 			ItemName: itemName,
 		}
-		err = app.ItemService.BuyItem(ctx.Context(), purchase)
+		err = app.ItemService.BuyItem(ctx.UserContext(), purchase)
 		if err != nil {
-			if errors.Is(err, errs.ItemNotFound) || errors.Is(err, errs.UserNotFound) ||
-				errors.Is(err, errs.NotEnoughCoins) {
-				return ctx.Status(fiber.StatusBadRequest).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
-			}
-			return ctx.Status(fiber.StatusInternalServerError).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
+			// API of the assignment answers 400 for any failed purchase
+			return errs.New(prompt, err).WithStatus(fiber.StatusBadRequest, errs.ItemNotFound, errs.UserNotFound)
+		}
+
+		return ctx.SendStatus(fiber.StatusOK)
+	}
+}
+
+func BuyItemsHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Buying items"
+
+		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		var req models.Cart
+		err = ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		err = app.ItemService.BuyItems(ctx.UserContext(), models.ToCartEntity(username, &req))
+		if err != nil {
+			return errs.New(prompt, err).WithStatus(fiber.StatusBadRequest, errs.ItemNotFound, errs.UserNotFound)
 		}
 
 		return ctx.SendStatus(fiber.StatusOK)
@@ -77,29 +232,24 @@ func SendCoinsHandler(app *app.App) fiber.Handler {
 
 		fromUser, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
 		if err != nil {
-			return ctx.Status(fiber.StatusUnauthorized).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, "invalid token")))
+			return errs.New(prompt, errs.InvalidToken)
 		}
 
 		var req models.CoinsTransfer
 		err = ctx.BodyParser(&req)
 		if err != nil {
-			return ctx.Status(fiber.StatusBadRequest).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, errs.InvalidData.Error())))
+			return errs.New(prompt, errs.InvalidData)
 		}
 
 		transfer := &entity.TransferCoins{
 			FromUser: fromUser,
 			ToUser:   req.ToUser,
 			Amount:   req.Amount,
+			Comment:  req.Comment,
 		}
-		err = app.UserService.SendCoins(ctx.Context(), transfer)
+		err = app.UserService.SendCoins(ctx.UserContext(), transfer)
 		if err != nil {
-			log.Println(transfer, err)
-
-			if errors.Is(err, errs.InvalidData) || errors.Is(err, errs.NotEnoughCoins) ||
-				errors.Is(err, errs.UserNotFound) {
-				return ctx.Status(fiber.StatusBadRequest).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
-			}
-			return ctx.Status(fiber.StatusInternalServerError).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
+			return errs.New(prompt, err).WithStatus(fiber.StatusBadRequest, errs.UserNotFound)
 		}
 
 		return ctx.SendStatus(fiber.StatusOK)
@@ -112,23 +262,17 @@ func GetUserInfoHandler(app *app.App) fiber.Handler {
 
 		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
 		if err != nil {
-			return ctx.Status(fiber.StatusUnauthorized).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, "invalid token")))
+			return errs.New(prompt, errs.InvalidToken)
 		}
 
-		items, err := app.ItemService.GetInventory(ctx.Context(), username)
+		items, err := app.ItemService.GetInventory(ctx.UserContext(), username)
 		if err != nil {
-			if errors.Is(err, errs.InvalidData) {
-				return ctx.Status(fiber.StatusBadRequest).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
-			}
-			return ctx.Status(fiber.StatusInternalServerError).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
+			return errs.New(prompt, err)
 		}
 
-		coins, coinHistory, err := app.UserService.GetCoinsHistory(ctx.Context(), username)
+		coins, coinHistory, err := app.UserService.GetCoinsHistory(ctx.UserContext(), username)
 		if err != nil {
-			if errors.Is(err, errs.InvalidData) {
-				return ctx.Status(fiber.StatusBadRequest).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
-			}
-			return ctx.Status(fiber.StatusInternalServerError).JSON(errorMap(fmt.Sprintf("%s: %s", prompt, err.Error())))
+			return errs.New(prompt, err)
 		}
 
 		return ctx.Status(fiber.StatusOK).JSON(models.InfoResponse{
@@ -138,3 +282,290 @@ func GetUserInfoHandler(app *app.App) fiber.Handler {
 		})
 	}
 }
+
+func optionalInt32Query(ctx *fiber.Ctx, key string) (*int32, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	val, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	res := int32(val)
+	return &res, nil
+}
+
+func optionalTimeQuery(ctx *fiber.Ctx, key string) (*time.Time, error) {
+	raw := ctx.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	val, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &val, nil
+}
+
+const defaultPageSize = 20
+
+func GetTransactionsHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Getting transactions"
+
+		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		filter := &entity.TransactionsFilter{
+			Username:  username,
+			Direction: ctx.Query("direction"),
+			Limit:     defaultPageSize,
+		}
+		limit, err := optionalInt32Query(ctx, "limit")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+		if limit != nil {
+			filter.Limit = *limit
+		}
+		filter.From, err = optionalTimeQuery(ctx, "from")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+		filter.To, err = optionalTimeQuery(ctx, "to")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+		filter.After, err = models.DecodeTransactionsCursor(ctx.Query("cursor"))
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		page, err := app.UserService.GetTransactions(ctx.UserContext(), filter)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToTransactionsPageTransport(page))
+	}
+}
+
+func GetPurchasesHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Getting purchases"
+
+		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		filter := &entity.PurchasesFilter{
+			Username: username,
+			Limit:    defaultPageSize,
+		}
+		limit, err := optionalInt32Query(ctx, "limit")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+		if limit != nil {
+			filter.Limit = *limit
+		}
+		filter.From, err = optionalTimeQuery(ctx, "from")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+		filter.To, err = optionalTimeQuery(ctx, "to")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+		filter.After, err = models.DecodePurchasesCursor(ctx.Query("cursor"))
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		page, err := app.ItemService.GetPurchases(ctx.UserContext(), filter)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToPurchasesPageTransport(page))
+	}
+}
+
+func RefundPurchaseHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Refunding purchase"
+
+		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		purchase, err := app.ItemService.RefundPurchase(ctx.UserContext(), username, ctx.Params("id"))
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToPurchaseTransport(purchase))
+	}
+}
+
+func GetItemsHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Getting items"
+
+		var err error
+		filter := &entity.ItemsFilter{
+			Sort: ctx.Query("sort"),
+		}
+		filter.MinPrice, err = optionalInt32Query(ctx, "minPrice")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+		filter.MaxPrice, err = optionalInt32Query(ctx, "maxPrice")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		items, err := app.ItemService.GetItems(ctx.UserContext(), filter)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToCatalogTransport(items))
+	}
+}
+
+func GetItemHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Getting item"
+
+		item, err := app.ItemService.GetItem(ctx.UserContext(), ctx.Params("name"))
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToCatalogItemTransport(item))
+	}
+}
+
+func CreateItemHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Creating item"
+
+		var req models.CatalogItem
+		err := ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		item := models.ToItemEntity(&req)
+		err = app.ItemService.CreateItem(ctx.UserContext(), item)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(models.ToCatalogItemTransport(item))
+	}
+}
+
+func UpdateItemPriceHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Updating item price"
+
+		var req models.ItemPrice
+		err := ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		item := &entity.Item{
+			Name:  ctx.Params("name"),
+			Price: req.Price,
+		}
+		err = app.ItemService.UpdateItemPrice(ctx.UserContext(), item)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.Status(fiber.StatusOK).JSON(models.ToCatalogItemTransport(item))
+	}
+}
+
+func UpdateItemStockHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Updating item stock"
+
+		var req models.ItemStock
+		err := ctx.BodyParser(&req)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidData)
+		}
+
+		item := &entity.Item{
+			Name:  ctx.Params("name"),
+			Stock: req.Stock,
+		}
+		err = app.ItemService.UpdateItemStock(ctx.UserContext(), item)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.SendStatus(fiber.StatusOK)
+	}
+}
+
+func RetireItemHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Retiring item"
+
+		err := app.ItemService.RetireItem(ctx.UserContext(), ctx.Params("name"))
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+
+		return ctx.SendStatus(fiber.StatusOK)
+	}
+}
+
+// JWKSHandler publishes public keys of asymmetric signing keys, so other services can verify tokens
+func JWKSHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return ctx.Status(fiber.StatusOK).JSON(models.ToJWKSTransport(app.TokenManager.PublicKeys()))
+	}
+}
+
+// MetricsHandler exposes metrics in Prometheus format, under prefork metrics of all processes are merged
+func MetricsHandler(app *app.App) fiber.Handler {
+	gatherer := prometheus.GathererFunc(app.Metrics.Gather)
+	if app.MetricsSnapshots != nil {
+		gatherer = app.MetricsSnapshots.Gather
+	}
+	return adaptor.HTTPHandler(promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+}
+
+// LivenessHandler answers while process is able to serve requests, restart is needed otherwise
+func LivenessHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+		report := app.HealthService.Live(ctx.UserContext())
+		return ctx.Status(fiber.StatusOK).JSON(models.ToHealthReportTransport(report))
+	}
+}
+
+// ReadinessHandler answers 503 if database is unavailable or not migrated, or server is shutting down
+func ReadinessHandler(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderCacheControl, "no-store")
+		report := app.HealthService.Ready(ctx.UserContext())
+		status := fiber.StatusOK
+		if report.Status != entity.HealthStatusOK {
+			status = fiber.StatusServiceUnavailable
+		}
+		return ctx.Status(status).JSON(models.ToHealthReportTransport(report))
+	}
+}
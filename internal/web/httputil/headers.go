@@ -0,0 +1,18 @@
+package httputil
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetRetryAfter sets Retry-After header in whole seconds, rounding up
+func SetRetryAfter(ctx *fiber.Ctx, retryAfter time.Duration) {
+	ctx.Set(fiber.HeaderRetryAfter, strconv.FormatInt(CeilSeconds(retryAfter), 10))
+}
+
+// CeilSeconds returns duration in whole seconds, rounding up, negative duration is zero
+func CeilSeconds(d time.Duration) int64 {
+	return int64((max(d, 0) + time.Second - 1) / time.Second)
+}
@@ -1,19 +1,71 @@
 package jwt
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 func FGetStringClaimFromJWT(ctx *fiber.Ctx, claim string) (string, error) {
-	user := ctx.Locals("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	strVal := claims[claim].(string)
+	user, ok := ctx.Locals("user").(*jwt.Token)
+	if !ok {
+		return "", fmt.Errorf("no token")
+	}
+	claims, ok := user.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", fmt.Errorf("invalid claims")
+	}
+	strVal, _ := claims[claim].(string)
 	var err error
 	if strVal == "" {
 		err = fmt.Errorf("empty claim")
 	}
 	return strVal, err
 }
+
+func FGetExpirationFromJWT(ctx *fiber.Ctx) (time.Time, error) {
+	user, ok := ctx.Locals("user").(*jwt.Token)
+	if !ok {
+		return time.Time{}, fmt.Errorf("no token")
+	}
+	exp, err := user.Claims.GetExpirationTime()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("getting expiration time: %w", err)
+	}
+	if exp == nil {
+		return time.Time{}, fmt.Errorf("empty claim")
+	}
+	return exp.Time, nil
+}
+
+// FGetIssuedAtFromJWT keeps fraction of iat, library claims getter truncates it to seconds
+func FGetIssuedAtFromJWT(ctx *fiber.Ctx) (time.Time, error) {
+	user, ok := ctx.Locals("user").(*jwt.Token)
+	if !ok {
+		return time.Time{}, fmt.Errorf("no token")
+	}
+	claims, ok := user.Claims.(jwt.MapClaims)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid claims")
+	}
+	var seconds float64
+	switch iat := claims["iat"].(type) {
+	case float64:
+		seconds = iat
+	case json.Number:
+		var err error
+		seconds, err = iat.Float64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("getting issued at time: %w", err)
+		}
+	case nil:
+		return time.Time{}, fmt.Errorf("empty claim")
+	default:
+		return time.Time{}, fmt.Errorf("getting issued at time: invalid type %T", iat)
+	}
+	return time.UnixMicro(int64(math.Round(seconds * 1e6))), nil
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccessLogMiddleware writes JSON record for every request to service logger, so access and service
+// records of one request share request id, must be registered right after RequestIDMiddleware
+func AccessLogMiddleware(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		err := ctx.Next()
+
+		route, status := routeStatus(ctx, err)
+		log := app.Logger.WithContext(ctx.UserContext()).WithFields(map[string]interface{}{
+			"method":     ctx.Method(),
+			"path":       ctx.Path(),
+			"route":      route,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"ip":         ctx.IP(),
+			"user_agent": ctx.Get(fiber.HeaderUserAgent),
+			"bytes_out":  len(ctx.Response().Body()),
+		})
+		if status >= fiber.StatusInternalServerError {
+			log.Errorf("request failed")
+		} else {
+			log.Infof("request")
+		}
+		return err
+	}
+}
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/httputil"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// apiError converts error returned by handlers and middlewares to error reported to client
+func apiError(err error) *errs.Error {
+	var apiErr *errs.Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return errs.NewStatus(fiberErr.Code, fiberErr.Message)
+	}
+	return errs.New("", err)
+}
+
+// ErrorHandler writes every error as JSON with stable code and request id,
+// details of internal errors are logged and never sent to client
+func ErrorHandler(app *app.App) fiber.ErrorHandler {
+	return func(ctx *fiber.Ctx, err error) error {
+		apiErr := apiError(err)
+		if apiErr.Status >= fiber.StatusInternalServerError {
+			cause := apiErr.Unwrap()
+			if cause == nil {
+				cause = err
+			}
+			app.Logger.WithContext(ctx.UserContext()).Errorf("%s: %v", apiErr.Message, cause)
+		}
+
+		var retryErr *errs.RetryAfterError
+		if errors.As(err, &retryErr) {
+			httputil.SetRetryAfter(ctx, retryErr.RetryAfter)
+		}
+
+		requestID := logger.RequestIDFromContext(ctx.UserContext())
+		return ctx.Status(apiErr.Status).JSON(models.ToErrorResponseTransport(apiErr, requestID))
+	}
+}
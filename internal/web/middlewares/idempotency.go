@@ -0,0 +1,100 @@
+package middlewares
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/jwt"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// errNotCompleted rolls back writes of request whose response is not stored (server errors)
+var errNotCompleted = errors.New("request is not completed")
+
+func requestHash(ctx *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(ctx.Method()))
+	h.Write([]byte{0})
+	h.Write([]byte(ctx.Path()))
+	h.Write([]byte{0})
+	h.Write(ctx.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IdempotencyMiddleware replays stored response for requests with already used Idempotency-Key header,
+// must be used after JwtMiddleware
+func IdempotencyMiddleware(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Idempotent request"
+
+		key := ctx.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return ctx.Next()
+		}
+
+		username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		record := &entity.IdempotencyRecord{
+			Username:    username,
+			Key:         key,
+			RequestHash: requestHash(ctx),
+		}
+		stored, err := app.IdempotencyService.Reserve(ctx.UserContext(), record)
+		if err != nil {
+			return errs.New(prompt, err)
+		}
+		if stored != nil {
+			ctx.Set("Idempotent-Replayed", "true")
+			if len(stored.Response) > 0 {
+				ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			}
+			return ctx.Status(stored.StatusCode).Send(stored.Response)
+		}
+
+		// writes of handler are committed together with its response, so key is never left in progress
+		// after money has moved, and nothing is committed if response can not be saved
+		userCtx := ctx.UserContext()
+		var handlerErr error
+		err = app.IdempotencyService.Execute(userCtx, record, func(txCtx context.Context) error {
+			ctx.SetUserContext(txCtx)
+			defer ctx.SetUserContext(userCtx)
+
+			handlerErr = ctx.Next()
+			if handlerErr != nil {
+				// error response is written here, so client errors are replayed like successful responses
+				handlerErr = ctx.App().ErrorHandler(ctx, handlerErr)
+			}
+			if handlerErr != nil || ctx.Response().StatusCode() >= fiber.StatusInternalServerError {
+				return errNotCompleted
+			}
+			record.StatusCode = ctx.Response().StatusCode()
+			record.Response = append([]byte(nil), ctx.Response().Body()...)
+			return nil
+		})
+		if err == nil {
+			return nil
+		}
+
+		// nothing is committed, so client may retry with the same key
+		releaseErr := app.IdempotencyService.Release(userCtx, record)
+		if releaseErr != nil {
+			app.Logger.WithContext(userCtx).Warnf("Releasing idempotency key \"%s\": %v", key, releaseErr)
+		}
+		if errors.Is(err, errNotCompleted) {
+			return handlerErr
+		}
+		// response of handler is written, but its writes are rolled back
+		ctx.Response().ResetBody()
+		return errs.New(prompt, err)
+	}
+}
@@ -1,19 +1,60 @@
 package middlewares
 
 import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/jwt"
+
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 )
 
-func JwtMiddleware(jwtKey string) fiber.Handler {
-	return func(ctx *fiber.Ctx) error {
-		return jwtware.New(jwtware.Config{
-			SigningKey: jwtware.SigningKey{Key: []byte(jwtKey)},
-			ErrorHandler: func(c *fiber.Ctx, err error) error {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"errors": err.Error(),
-				})
-			},
-		})(ctx)
+// JwtMiddleware verifies token signature and rejects tokens revoked on logout or password change
+func JwtMiddleware(app *app.App) fiber.Handler {
+	return jwtware.New(jwtware.Config{
+		KeyFunc: app.TokenManager.Keyfunc,
+		// messages of jwt library describe token internals, so client only gets invalid token
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return errs.New("", errs.InvalidToken)
+		},
+		SuccessHandler: func(c *fiber.Ctx) error {
+			token, err := accessTokenFromJWT(c)
+			if err != nil {
+				return errs.New("", errs.InvalidToken)
+			}
+
+			revoked, err := app.AuthService.IsTokenRevoked(c.UserContext(), token)
+			if err != nil {
+				return errs.New("", err)
+			}
+			if revoked {
+				return errs.New("", errs.TokenRevoked)
+			}
+			c.SetUserContext(logger.WithUsername(c.UserContext(), token.Username))
+			return c.Next()
+		},
+	})
+}
+
+func accessTokenFromJWT(ctx *fiber.Ctx) (*entity.AccessToken, error) {
+	jti, err := jwt.FGetStringClaimFromJWT(ctx, "jti")
+	if err != nil {
+		return nil, err
 	}
+	username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+	if err != nil {
+		return nil, err
+	}
+	issuedAt, err := jwt.FGetIssuedAtFromJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AccessToken{
+		JTI:      jti,
+		Username: username,
+		IssuedAt: issuedAt,
+	}, nil
 }
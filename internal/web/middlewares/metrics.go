@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware observes request latency labeled by route pattern (not by path, so ids
+// do not blow up cardinality), must be registered before routes
+func MetricsMiddleware(app *app.App) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+
+		err := ctx.Next()
+
+		route, status := routeStatus(ctx, err)
+		app.Metrics.HTTPRequestDuration.
+			WithLabelValues(ctx.Method(), route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// routeStatus returns route pattern and response status after ctx.Next() returned err
+func routeStatus(ctx *fiber.Ctx, err error) (string, int) {
+	route := ctx.Route().Path
+	status := ctx.Response().StatusCode()
+	if err != nil { // error handler has not written response yet
+		status = apiError(err).Status
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusNotFound {
+			// no route matched, route is the last passed middleware
+			route = unmatchedRoute
+		}
+	}
+	return route, status
+}
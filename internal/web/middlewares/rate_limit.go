@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/httputil"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/jwt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset" // seconds until window reset
+)
+
+// RateLimitSubject returns key requests are counted by
+type RateLimitSubject func(ctx *fiber.Ctx) (string, error)
+
+func RateLimitByIP(ctx *fiber.Ctx) (string, error) {
+	return "ip:" + ctx.IP(), nil
+}
+
+// RateLimitByUser must be used after JwtMiddleware
+func RateLimitByUser(ctx *fiber.Ctx) (string, error) {
+	username, err := jwt.FGetStringClaimFromJWT(ctx, "sub")
+	if err != nil {
+		return "", err
+	}
+	return "user:" + username, nil
+}
+
+// RateLimitMiddleware rejects requests over budget of the route with 429,
+// requests are passed if limiter itself fails (error is logged by service)
+func RateLimitMiddleware(app *app.App, subject RateLimitSubject) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		const prompt = "Rate limiting"
+
+		key, err := subject(ctx)
+		if err != nil {
+			return errs.New(prompt, errs.InvalidToken)
+		}
+
+		status, err := app.RateLimitService.Allow(ctx.UserContext(), &entity.RateLimitRequest{
+			Subject: key,
+			Path:    ctx.Path(),
+			Time:    time.Now(),
+		})
+		if err != nil || status == nil {
+			return ctx.Next()
+		}
+
+		reset := time.Until(status.Reset)
+		ctx.Set(RateLimitLimitHeader, strconv.Itoa(status.Limit))
+		ctx.Set(RateLimitRemainingHeader, strconv.Itoa(status.Remaining))
+		ctx.Set(RateLimitResetHeader, strconv.FormatInt(httputil.CeilSeconds(reset), 10))
+		if !status.Allowed {
+			return errs.New(prompt, &errs.RetryAfterError{
+				Err:        errs.RateLimitExceeded,
+				RetryAfter: reset,
+			})
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// requestIDRegexp limits ids taken from clients, so they can not inject anything into logs
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestIDMiddleware takes request id from X-Request-ID header or generates new one,
+// returns it in response header and puts it into ctx.UserContext() for logging.
+// Must be registered first
+func RequestIDMiddleware() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		requestID := ctx.Get(fiber.HeaderXRequestID)
+		if !requestIDRegexp.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		ctx.Set(fiber.HeaderXRequestID, requestID)
+		ctx.SetUserContext(logger.WithRequestID(ctx.UserContext(), requestID))
+		return ctx.Next()
+	}
+}
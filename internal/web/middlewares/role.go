@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/jwt"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleMiddleware must be used after JwtMiddleware
+func RoleMiddleware(roles ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		role, err := jwt.FGetStringClaimFromJWT(ctx, "role")
+		if err != nil || !slices.Contains(roles, role) {
+			return errs.New("", errs.PermissionDenied)
+		}
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fiberHeaderCarrier adapts request and response headers to propagation.TextMapCarrier
+type fiberHeaderCarrier struct {
+	ctx *fiber.Ctx
+}
+
+var _ propagation.TextMapCarrier = fiberHeaderCarrier{}
+
+func (c fiberHeaderCarrier) Get(key string) string {
+	return c.ctx.Get(key)
+}
+
+func (c fiberHeaderCarrier) Set(key, value string) {
+	c.ctx.Set(key, value)
+}
+
+func (c fiberHeaderCarrier) Keys() []string {
+	keys := make([]string, 0)
+	c.ctx.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// TracingMiddleware starts server span continuing W3C trace context of the caller (traceparent header)
+// and puts it into ctx.UserContext(), so handlers must pass ctx.UserContext() to services.
+// Must be registered before routes
+func TracingMiddleware() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		parent := otel.GetTextMapPropagator().Extract(ctx.UserContext(), fiberHeaderCarrier{ctx: ctx})
+		spanCtx, span := tracing.Tracer().Start(parent, ctx.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Method()),
+				semconv.URLPath(ctx.Path()),
+				semconv.ClientAddress(ctx.IP()),
+			),
+		)
+		defer span.End()
+		ctx.SetUserContext(spanCtx)
+
+		err := ctx.Next()
+
+		route, status := routeStatus(ctx, err)
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.SetName(ctx.Method() + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "")
+		}
+		return err
+	}
+}
@@ -8,7 +8,28 @@ type Auth struct {
 }
 
 type AuthResponse struct {
-	Token string `json:"token,omitempty"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+type PasswordChange struct {
+	OldPassword string `json:"oldPassword,omitempty"`
+	NewPassword string `json:"newPassword,omitempty"`
+}
+
+type PasswordReset struct {
+	Password string `json:"password,omitempty"`
+}
+
+type RefreshToken struct {
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+func ToAuthResponseTransport(tokens *entity.TokenPair) *AuthResponse {
+	return &AuthResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}
 }
 
 func ToAuthEntity(auth *Auth) *entity.Auth {
@@ -10,24 +10,28 @@ type CoinHistory struct {
 type CoinReceivedTransfer struct {
 	FromUser string `json:"fromUser,omitempty"`
 	Amount   int32  `json:"amount,omitempty"`
+	Comment  string `json:"comment,omitempty"`
 }
 
 type CoinSentTransfer struct {
-	ToUser string `json:"toUser,omitempty"`
-	Amount int32  `json:"amount,omitempty"`
+	ToUser  string `json:"toUser,omitempty"`
+	Amount  int32  `json:"amount,omitempty"`
+	Comment string `json:"comment,omitempty"`
 }
 
-func ToCoinSentTransferTransport(sentTransfer *entity.User) *CoinSentTransfer {
+func ToCoinSentTransferTransport(sentTransfer *entity.CoinsHistoryEntry) *CoinSentTransfer {
 	return &CoinSentTransfer{
-		ToUser: sentTransfer.Username,
-		Amount: sentTransfer.Coins,
+		ToUser:  sentTransfer.Username,
+		Amount:  sentTransfer.Coins,
+		Comment: sentTransfer.Comment,
 	}
 }
 
-func ToCoinReceivedTransferTransport(sentTransfer *entity.User) *CoinReceivedTransfer {
+func ToCoinReceivedTransferTransport(sentTransfer *entity.CoinsHistoryEntry) *CoinReceivedTransfer {
 	return &CoinReceivedTransfer{
 		FromUser: sentTransfer.Username,
 		Amount:   sentTransfer.Coins,
+		Comment:  sentTransfer.Comment,
 	}
 }
 
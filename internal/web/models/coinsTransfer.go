@@ -1,8 +1,9 @@
 package models
 
 type CoinsTransfer struct {
-	ToUser string `json:"toUser,omitempty"`
-	Amount int32  `json:"amount,omitempty"`
+	ToUser  string `json:"toUser,omitempty"`
+	Amount  int32  `json:"amount,omitempty"`
+	Comment string `json:"comment,omitempty"`
 }
 
 //func ToCoinsTransferEntity(transfer *CoinsTransfer) *entity.Auth {
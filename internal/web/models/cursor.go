@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// encodeCursor packs (time, id) keyset position into opaque url-safe string
+func encodeCursor(t time.Time, id string) string {
+	raw := fmt.Sprintf("%s|%s", t.UTC().Format(time.RFC3339Nano), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("decoding cursor: %w", err)
+	}
+	timePart, id, found := strings.Cut(string(raw), "|")
+	if !found || !uuidRegexp.MatchString(id) {
+		return time.Time{}, "", fmt.Errorf("invalid cursor format")
+	}
+	t, err := time.Parse(time.RFC3339Nano, timePart)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("parsing cursor time: %w", err)
+	}
+	return t, id, nil
+}
@@ -0,0 +1,18 @@
+package models
+
+import errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+
+// ErrorResponse keeps errors field of original API, code and requestId are for clients and support
+type ErrorResponse struct {
+	Errors    string `json:"errors"`
+	Code      string `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+func ToErrorResponseTransport(err *errs.Error, requestID string) *ErrorResponse {
+	return &ErrorResponse{
+		Errors:    err.Message,
+		Code:      err.Code,
+		RequestID: requestID,
+	}
+}
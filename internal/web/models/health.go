@@ -0,0 +1,29 @@
+package models
+
+import "Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+
+type HealthCheck struct {
+	Status     string  `json:"status"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status string                  `json:"status"`
+	Checks map[string]*HealthCheck `json:"checks"`
+}
+
+func ToHealthReportTransport(report *entity.HealthReport) *HealthReport {
+	transport := &HealthReport{
+		Status: report.Status,
+		Checks: make(map[string]*HealthCheck, len(report.Checks)),
+	}
+	for _, check := range report.Checks {
+		transport.Checks[check.Name] = &HealthCheck{
+			Status:     check.Status,
+			DurationMs: float64(check.Duration.Microseconds()) / 1000,
+			Error:      check.Error,
+		}
+	}
+	return transport
+}
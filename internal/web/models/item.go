@@ -22,3 +22,69 @@ func ToInventoryTransport(items []*entity.Item) []*Item {
 
 	return inventory
 }
+
+type CatalogItem struct {
+	Name  string `json:"name"`
+	Price int32  `json:"price"`
+	Stock *int32 `json:"stock,omitempty"` // omitted for unlimited items
+}
+
+func ToCatalogItemTransport(item *entity.Item) *CatalogItem {
+	return &CatalogItem{
+		Name:  item.Name,
+		Price: item.Price,
+		Stock: item.Stock,
+	}
+}
+
+func ToCatalogTransport(items []*entity.Item) []*CatalogItem {
+	catalog := make([]*CatalogItem, len(items))
+	for i := 0; i < len(items); i++ {
+		catalog[i] = ToCatalogItemTransport(items[i])
+	}
+
+	return catalog
+}
+
+type ItemPrice struct {
+	Price int32 `json:"price"`
+}
+
+type ItemStock struct {
+	Stock *int32 `json:"stock"`
+}
+
+func ToItemEntity(item *CatalogItem) *entity.Item {
+	return &entity.Item{
+		Name:  item.Name,
+		Price: item.Price,
+		Stock: item.Stock,
+	}
+}
+
+type CartLine struct {
+	Item     string `json:"item"`
+	Quantity int32  `json:"quantity"`
+}
+
+type Cart struct {
+	Items []*CartLine `json:"items"`
+}
+
+func ToCartEntity(username string, cart *Cart) *entity.Cart {
+	lines := make([]*entity.CartLine, len(cart.Items))
+	for i := 0; i < len(cart.Items); i++ {
+		if cart.Items[i] == nil {
+			continue
+		}
+		lines[i] = &entity.CartLine{
+			ItemName: cart.Items[i].Item,
+			Quantity: cart.Items[i].Quantity,
+		}
+	}
+
+	return &entity.Cart{
+		Username: username,
+		Lines:    lines,
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/jwt"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK fields are described in RFC 7517 (RSA) and RFC 8037 (OKP)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+func ToJWKSTransport(keys []jwt.PublicKey) *JWKS {
+	jwks := &JWKS{
+		Keys: make([]*JWK, 0, len(keys)),
+	}
+	for _, key := range keys {
+		jwk := &JWK{
+			Use: "sig",
+			Alg: key.Alg,
+			Kid: key.KID,
+		}
+		switch k := key.Key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(k)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+	return jwks
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"time"
+)
+
+type Purchase struct {
+	ID       string    `json:"id"`
+	Item     string    `json:"item"`
+	Price    int32     `json:"price"`
+	Time     time.Time `json:"time"`
+	Refunded bool      `json:"refunded"`
+}
+
+type PurchasesPage struct {
+	Purchases  []*Purchase `json:"purchases"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+func ToPurchaseTransport(purchase *entity.PurchaseRecord) *Purchase {
+	return &Purchase{
+		ID:       purchase.ID,
+		Item:     purchase.ItemName,
+		Price:    purchase.Price,
+		Time:     purchase.Time,
+		Refunded: purchase.Refunded,
+	}
+}
+
+func ToPurchasesPageTransport(page *entity.PurchasesPage) *PurchasesPage {
+	purchases := make([]*Purchase, len(page.Purchases))
+	for i := 0; i < len(page.Purchases); i++ {
+		purchases[i] = ToPurchaseTransport(page.Purchases[i])
+	}
+
+	return &PurchasesPage{
+		Purchases:  purchases,
+		NextCursor: EncodePurchasesCursor(page.Next),
+	}
+}
+
+// EncodePurchasesCursor returns opaque cursor for client, empty if there is no next page
+func EncodePurchasesCursor(cursor *entity.PurchasesCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return encodeCursor(cursor.Time, cursor.ID)
+}
+
+func DecodePurchasesCursor(cursor string) (*entity.PurchasesCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	t, id, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.PurchasesCursor{
+		Time: t,
+		ID:   id,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"time"
+)
+
+type Transaction struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	FromUser string    `json:"fromUser"`
+	ToUser   string    `json:"toUser"`
+	Amount   int32     `json:"amount"`
+	Comment  string    `json:"comment,omitempty"`
+}
+
+type TransactionsPage struct {
+	Transactions []*Transaction `json:"transactions"`
+	NextCursor   string         `json:"nextCursor,omitempty"`
+}
+
+func ToTransactionTransport(transaction *entity.Transaction) *Transaction {
+	return &Transaction{
+		ID:       transaction.ID,
+		Time:     transaction.Time,
+		FromUser: transaction.FromUser,
+		ToUser:   transaction.ToUser,
+		Amount:   transaction.Amount,
+		Comment:  transaction.Comment,
+	}
+}
+
+func ToTransactionsPageTransport(page *entity.TransactionsPage) *TransactionsPage {
+	transactions := make([]*Transaction, len(page.Transactions))
+	for i := 0; i < len(page.Transactions); i++ {
+		transactions[i] = ToTransactionTransport(page.Transactions[i])
+	}
+
+	return &TransactionsPage{
+		Transactions: transactions,
+		NextCursor:   EncodeTransactionsCursor(page.Next),
+	}
+}
+
+// EncodeTransactionsCursor returns opaque cursor for client, empty if there is no next page
+func EncodeTransactionsCursor(cursor *entity.TransactionsCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return encodeCursor(cursor.Time, cursor.ID)
+}
+
+func DecodeTransactionsCursor(cursor string) (*entity.TransactionsCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	t, id, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TransactionsCursor{
+		Time: t,
+		ID:   id,
+	}, nil
+}
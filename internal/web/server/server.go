@@ -0,0 +1,144 @@
+package server
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// childDoneSignal is sent by prefork child to master after graceful shutdown
+const childDoneSignal = syscall.SIGUSR1
+
+// Server runs fiber app and shuts it down gracefully.
+// With prefork only master receives shutdown signal (docker stop signals pid 1), but requests
+// are served by children, so master forwards signal to them and waits until they are done:
+// children report not ready for shutdownDelay, finish requests and notify master
+type Server struct {
+	app             *fiber.App
+	health          entity.IHealthService
+	shutdownDelay   time.Duration
+	shutdownTimeout time.Duration
+
+	mu        sync.Mutex
+	children  []int
+	childDone chan os.Signal
+}
+
+// New must be called before app listens, so prefork master registers children it starts
+func New(app *fiber.App, health entity.IHealthService, shutdownDelay, shutdownTimeout time.Duration) *Server {
+	s := &Server{
+		app:             app,
+		health:          health,
+		shutdownDelay:   shutdownDelay,
+		shutdownTimeout: shutdownTimeout,
+	}
+	if s.isPreforkMaster() {
+		// registered before children start, default action of signal terminates process
+		s.childDone = make(chan os.Signal, 1)
+		signal.Notify(s.childDone, childDoneSignal)
+		app.Hooks().OnFork(func(pid int) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.children = append(s.children, pid)
+			return nil
+		})
+	}
+	return s
+}
+
+// Run listens on addr until one of signals is received, then shuts down gracefully,
+// stop is called after requests are finished to stop background work of process
+func (s *Server) Run(addr string, stop func(), signals ...os.Signal) error {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, signals...)
+	defer signal.Stop(sig)
+
+	listenErr := make(chan error, 1)
+	go func() {
+		// prefork master returns when any child exits, nil if child exited successfully
+		err := s.app.Listen(addr)
+		if err != nil {
+			listenErr <- err
+		}
+	}()
+
+	select {
+	case err := <-listenErr:
+		return err
+	case received := <-sig:
+		if s.isPreforkMaster() {
+			return s.shutdownMaster(received, stop)
+		}
+		return s.shutdown(stop)
+	}
+}
+
+func (s *Server) isPreforkMaster() bool {
+	return s.app.Config().Prefork && !fiber.IsChild()
+}
+
+func (s *Server) shutdownMaster(sig os.Signal, stop func()) error {
+	s.mu.Lock()
+	children := append([]int(nil), s.children...)
+	s.mu.Unlock()
+
+	var errs []error
+	for _, pid := range children {
+		err := signalProcess(pid, sig)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("forwarding signal to child %d: %w", pid, err))
+		}
+	}
+
+	// children are killed when master exits, so it waits for them. Signals sent at the same time
+	// may be merged into one, then master waits until children are surely done
+	timeout := time.After(s.shutdownDelay + s.shutdownTimeout)
+wait:
+	for done := 0; done < len(children); done++ {
+		select {
+		case <-s.childDone:
+		case <-timeout:
+			break wait
+		}
+	}
+	stop()
+	return errors.Join(errs...)
+}
+
+func (s *Server) shutdown(stop func()) error {
+	s.health.SetShuttingDown()
+	time.Sleep(s.shutdownDelay)
+	err := s.app.ShutdownWithTimeout(s.shutdownTimeout)
+	stop()
+	return err
+}
+
+// Done is called by process after Run returns, prefork child notifies master that it is done
+// and waits until master exits, since exit of child makes fiber kill other children,
+// which may still finish requests. Fiber exits child after master, wait is bounded if it does not
+func (s *Server) Done() error {
+	if !fiber.IsChild() {
+		return nil
+	}
+	err := signalProcess(os.Getppid(), childDoneSignal)
+	if err != nil {
+		return fmt.Errorf("notifying master: %w", err)
+	}
+	time.Sleep(s.shutdownDelay + s.shutdownTimeout)
+	return nil
+}
+
+func signalProcess(pid int, sig os.Signal) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Signal(sig)
+}
@@ -0,0 +1,4 @@
+drop table if exists purchases;
+drop table if exists transactions;
+drop table if exists items;
+drop table if exists users;
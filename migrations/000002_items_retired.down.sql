@@ -0,0 +1 @@
+alter table items drop column if exists retired;
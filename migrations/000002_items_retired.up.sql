@@ -0,0 +1 @@
+alter table items add column if not exists retired boolean default false not null;
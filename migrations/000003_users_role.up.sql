@@ -0,0 +1 @@
+alter table users add column if not exists role varchar(16) default 'user' not null;
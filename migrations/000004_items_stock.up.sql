@@ -0,0 +1 @@
+alter table items add column if not exists stock int constraint stock_not_negative_check check ( stock >= 0 );
@@ -0,0 +1,9 @@
+create table if not exists idempotency_keys (
+    username varchar(32) references users(username),
+    key varchar(64),
+    request_hash varchar(64) not null,
+    status_code int,
+    response bytea,
+    created_at timestamp with time zone default current_timestamp not null,
+    primary key (username, key)
+);
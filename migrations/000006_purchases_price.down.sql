@@ -0,0 +1 @@
+alter table purchases drop column if exists price;
@@ -0,0 +1,10 @@
+alter table purchases add column if not exists price int;
+
+-- purchases made before this migration are saved with current item price
+update purchases
+set price = items.price
+from items
+where purchases.item = items.name and purchases.price is null;
+
+alter table purchases alter column price set not null;
+alter table purchases add constraint price_not_negative_check check ( price >= 0 );
@@ -0,0 +1,3 @@
+drop table if exists purchase_refunds;
+
+alter table purchases drop column if exists refunded_at;
@@ -0,0 +1,10 @@
+alter table purchases add column if not exists refunded_at timestamp with time zone; -- null if not refunded
+
+create table if not exists purchase_refunds (
+    id uuid default gen_random_uuid() primary key,
+    time timestamp with time zone default current_timestamp not null,
+    purchase_id uuid unique references purchases(id),
+    username varchar(32) references users(username),
+    item varchar(32) references items(name),
+    coins int not null constraint not_negative_check check ( coins >= 0 )
+);
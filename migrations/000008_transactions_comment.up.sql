@@ -0,0 +1 @@
+alter table transactions add column if not exists comment varchar(128) default '' not null;
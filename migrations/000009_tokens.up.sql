@@ -0,0 +1,16 @@
+create table if not exists refresh_tokens (
+    id uuid default gen_random_uuid() primary key,
+    username varchar(32) references users(username),
+    token_hash varchar(64) not null unique,
+    expires_at timestamp with time zone not null,
+    revoked_at timestamp with time zone, -- null while token is active
+    created_at timestamp with time zone default current_timestamp not null
+);
+
+create index if not exists refresh_tokens_username_idx on refresh_tokens(username);
+
+create table if not exists revoked_tokens (
+    jti varchar(64) primary key,
+    username varchar(32) references users(username),
+    expires_at timestamp with time zone not null
+);
@@ -0,0 +1 @@
+alter table users add column if not exists tokens_valid_after timestamp with time zone; -- set on password change
@@ -0,0 +1 @@
+alter table users alter column password type varchar(255); -- argon2id hashes are longer than bcrypt ones
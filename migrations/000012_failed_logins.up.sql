@@ -0,0 +1,10 @@
+create table if not exists failed_logins (
+    id bigserial primary key,
+    username varchar(32) not null, -- not a reference, unknown usernames are counted too
+    ip varchar(45) not null,
+    time timestamp with time zone not null
+);
+
+create index if not exists failed_logins_username_time_idx on failed_logins(username, time);
+create index if not exists failed_logins_ip_time_idx on failed_logins(ip, time);
+create index if not exists failed_logins_time_idx on failed_logins(time);
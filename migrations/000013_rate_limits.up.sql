@@ -0,0 +1,5 @@
+create table if not exists rate_limits (
+    key varchar(128) primary key, -- subject and budget path
+    window_start timestamp with time zone not null,
+    count integer not null
+);
@@ -0,0 +1,2 @@
+drop index if exists transactions_touser_time_id_idx;
+drop index if exists transactions_fromuser_time_id_idx;
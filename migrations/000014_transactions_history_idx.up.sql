@@ -0,0 +1,3 @@
+-- keyset pagination of user history: (time, id) < cursor filtered by sender or receiver
+create index if not exists transactions_fromuser_time_id_idx on transactions(fromUser, time desc, id desc);
+create index if not exists transactions_touser_time_id_idx on transactions(toUser, time desc, id desc);
@@ -0,0 +1 @@
+drop index if exists rate_limits_window_start_idx;
@@ -0,0 +1,2 @@
+-- expired counters are deleted by window_start in background
+create index if not exists rate_limits_window_start_idx on rate_limits (window_start);
@@ -0,0 +1 @@
+alter table failed_logins drop column if exists username_cleared;
@@ -0,0 +1,2 @@
+-- successful login stops counting failures for username, rows are kept to count for their ip
+alter table failed_logins add column if not exists username_cleared boolean not null default false;
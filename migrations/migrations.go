@@ -0,0 +1,7 @@
+// Package migrations embeds golang-migrate migrations into binary, so schema changes ship with the code
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
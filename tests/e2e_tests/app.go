@@ -2,53 +2,124 @@ package e2e_tests
 
 import (
 	appPackage "Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/web/handlers"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/middlewares"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const (
 	GracefulShutdownSeconds = 30
 	TestingPort             = 8081
+	TestingSigningKeyID     = "e2e"
 )
 
+func testingSigningKeyPEM() string {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Panic(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		log.Panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
 func RunTheApp(db *pgxpool.Pool, started chan bool) {
 	cfg := &config.Config{
 		HTTP: config.HTTPConfig{Port: TestingPort},
-		Jwt:  config.Jwt{Key: "abcdef12345"},
+		Jwt: config.Jwt{
+			Key: "abcdef12345",
+			SigningKeys: []config.SigningKeyConfig{
+				{KID: TestingSigningKeyID, PEM: testingSigningKeyPEM()},
+			},
+		},
+		Auth: config.AuthConfig{
+			AutoRegister: true,
+			BruteForce: config.BruteForceConfig{
+				Window:            15 * time.Minute,
+				UsernameThreshold: 3,
+				IPThreshold:       100,
+				BaseDelay:         time.Minute,
+				MaxDelay:          15 * time.Minute,
+			},
+		},
+		Shop: config.ShopConfig{RefundWindow: time.Minute},
+		RateLimit: config.RateLimitConfig{
+			Routes: []config.RouteRateLimitConfig{
+				{Path: "/api/info", Limit: 20, Window: time.Hour},
+			},
+		},
 	}
 	svcLogger := mocks.NewMockLogger()
 
-	app := appPackage.NewApp(db, cfg, svcLogger)
+	app, err := appPackage.NewApp(db, cfg, svcLogger)
+	if err != nil {
+		log.Panic(err)
+	}
 
 	r := fiber.New(fiber.Config{
 		Prefork:       false,
 		ServerHeader:  "Avito-shop",
 		CaseSensitive: true,
+		ErrorHandler:  middlewares.ErrorHandler(app),
 	})
-	r.Use(logger.New())
+	r.Use(middlewares.RequestIDMiddleware())
+	r.Use(middlewares.AccessLogMiddleware(app))
 	r.Use(cors.New())
+	r.Use(middlewares.TracingMiddleware())
+	r.Use(middlewares.MetricsMiddleware(app))
+
+	r.Get("/.well-known/jwks.json", handlers.JWKSHandler(app))
+	r.Get("/metrics", handlers.MetricsHandler(app))
+	r.Get("/healthz", handlers.LivenessHandler(app))
+	r.Get("/readyz", handlers.ReadinessHandler(app))
 
 	r.Route("/api", func(r fiber.Router) {
-		r.Post("/auth", handlers.AuthHandler(app))
+		ipRateLimit := middlewares.RateLimitMiddleware(app, middlewares.RateLimitByIP)
+		r.Post("/register", ipRateLimit, handlers.RegisterHandler(app))
+		r.Post("/auth", ipRateLimit, handlers.AuthHandler(app))
+		r.Post("/auth/refresh", ipRateLimit, handlers.RefreshHandler(app))
+		r.Get("/items", ipRateLimit, handlers.GetItemsHandler(app))
+		r.Get("/items/:name", ipRateLimit, handlers.GetItemHandler(app))
 
-		r.Use(jwtMiddleware(cfg.Jwt.Key))
-		r.Get("/buy/:item", handlers.BuyItemHandler(app))
+		r.Use(middlewares.JwtMiddleware(app))
+		r.Use(middlewares.RateLimitMiddleware(app, middlewares.RateLimitByUser))
+		r.Post("/auth/logout", handlers.LogoutHandler(app))
+		r.Post("/auth/password", handlers.ChangePasswordHandler(app))
+		r.Get("/buy/:item", middlewares.IdempotencyMiddleware(app), handlers.BuyItemHandler(app))
+		r.Post("/buy", middlewares.IdempotencyMiddleware(app), handlers.BuyItemsHandler(app))
 
-		r.Post("/sendCoin", handlers.SendCoinsHandler(app))
+		r.Post("/sendCoin", middlewares.IdempotencyMiddleware(app), handlers.SendCoinsHandler(app))
 		r.Get("/info", handlers.GetUserInfoHandler(app))
+		r.Get("/transactions", handlers.GetTransactionsHandler(app))
+		r.Get("/purchases", handlers.GetPurchasesHandler(app))
+		r.Post("/purchases/:id/refund", middlewares.IdempotencyMiddleware(app), handlers.RefundPurchaseHandler(app))
+
+		r.Route("/admin", func(r fiber.Router) {
+			r.Use(middlewares.RoleMiddleware(entity.RoleAdmin))
+			r.Post("/items", handlers.CreateItemHandler(app))
+			r.Put("/items/:name", handlers.UpdateItemPriceHandler(app))
+			r.Put("/items/:name/stock", handlers.UpdateItemStockHandler(app))
+			r.Delete("/items/:name", handlers.RetireItemHandler(app))
+			r.Put("/users/:username/password", handlers.ResetPasswordHandler(app))
+		})
 	})
 
 	go func() {
@@ -63,7 +134,8 @@ func RunTheApp(db *pgxpool.Pool, started chan bool) {
 
 	<-sig
 	log.Info(syscall.Getpid(), " gracefully shutting down...")
-	err := r.ShutdownWithTimeout(GracefulShutdownSeconds * time.Second)
+	app.HealthService.SetShuttingDown()
+	err = r.ShutdownWithTimeout(GracefulShutdownSeconds * time.Second)
 	if err != nil {
 		log.Fatal(err)
 	} else {
@@ -71,19 +143,6 @@ func RunTheApp(db *pgxpool.Pool, started chan bool) {
 	}
 }
 
-func jwtMiddleware(jwtKey string) fiber.Handler {
-	return func(ctx *fiber.Ctx) error {
-		return jwtware.New(jwtware.Config{
-			SigningKey: jwtware.SigningKey{Key: []byte(jwtKey)},
-			ErrorHandler: func(c *fiber.Ctx, err error) error {
-				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-					"errors": err.Error(),
-				})
-			},
-		})(ctx)
-	}
-}
-
 //func RunTheApp(db *pgxpool.Pool, started chan bool) {
 //	cfg := &config.Config{
 //		HTTP: config.HTTPConfig{Port: TestingPort},
@@ -3,12 +3,18 @@ package e2e_tests
 import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/gavv/httpexpect/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -25,6 +31,7 @@ const (
 	item1ToBuyCost      = 300
 	item2ToBuy          = "cup"
 	item2ToBuyCost      = 10
+	adminUsername       = "admin"
 )
 
 func (s *E2ESuite) SetupSuite() {
@@ -44,7 +51,7 @@ func (s *E2ESuite) SetupSuite() {
 }
 
 func (s *E2ESuite) SetupTest() {
-	clearQuery := `truncate table users cascade`
+	clearQuery := `truncate table users, failed_logins, rate_limits cascade`
 	_, err := testDbInstance.Exec(
 		context.Background(),
 		clearQuery,
@@ -96,6 +103,595 @@ func (s *E2ESuite) TestE2E_SendCoins() {
 		Status(http.StatusOK)
 }
 
+func (s *E2ESuite) TestE2E_Register() {
+	registerReq := models.Auth{
+		Username: "new_user",
+		Password: "password1",
+	}
+
+	s.e.POST("/api/register").
+		WithJSON(registerReq).
+		Expect().
+		Status(http.StatusCreated).
+		JSON().
+		Object().
+		ContainsKey("token").
+		ContainsKey("refreshToken")
+
+	s.e.POST("/api/register").
+		WithJSON(registerReq).
+		Expect().
+		Status(http.StatusConflict)
+
+	s.e.POST("/api/auth").
+		WithJSON(registerReq).
+		Expect().
+		Status(http.StatusOK)
+
+	s.e.POST("/api/register").
+		WithJSON(models.Auth{Username: "weak", Password: "pass"}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	s.e.POST("/api/register").
+		WithJSON(models.Auth{Username: "bad name", Password: "password1"}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func (s *E2ESuite) TestE2E_Auth_BruteForce() {
+	wrongAuthReq := models.Auth{
+		Username: "first",
+		Password: "wrongPass",
+	}
+
+	// e2e config allows 3 failed attempts per username
+	for i := 0; i < 3; i++ {
+		s.e.POST("/api/auth").
+			WithJSON(wrongAuthReq).
+			Expect().
+			Status(http.StatusUnauthorized)
+	}
+
+	s.e.POST("/api/auth").
+		WithJSON(wrongAuthReq).
+		Expect().
+		Status(http.StatusTooManyRequests).
+		Header("Retry-After").
+		AsNumber().
+		Gt(0)
+
+	// other usernames from same ip are not locked
+	s.e.POST("/api/auth").
+		WithJSON(models.Auth{Username: "user", Password: "pass"}).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func (s *E2ESuite) TestE2E_RateLimit() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	token := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	// e2e config allows 20 requests to /api/info per user
+	for i := 1; i <= 20; i++ {
+		resp := reqWithAuth.GET("/api/info").
+			Expect().
+			Status(http.StatusOK)
+		resp.Header("X-RateLimit-Limit").IsEqual("20")
+		resp.Header("X-RateLimit-Remaining").IsEqual(strconv.Itoa(20 - i))
+	}
+
+	resp := reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusTooManyRequests)
+	resp.Header("X-RateLimit-Remaining").IsEqual("0")
+	resp.Header("Retry-After").AsNumber().Gt(0)
+
+	// budget is per user
+	otherToken := s.e.POST("/api/auth").
+		WithJSON(models.Auth{Username: "other", Password: "pass"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+	s.e.GET("/api/info").
+		WithHeader("Authorization", "Bearer "+otherToken).
+		Expect().
+		Status(http.StatusOK)
+
+	// routes without budget are not limited
+	reqWithAuth.GET("/api/purchases").
+		Expect().
+		Status(http.StatusOK).
+		Headers().NotContainsKey("X-Ratelimit-Limit")
+}
+
+func (s *E2ESuite) TestE2E_JWKS() {
+	jwks := s.e.GET("/.well-known/jwks.json").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	keys := jwks.Value("keys").Array()
+	keys.Length().IsEqual(1)
+	key := keys.Value(0).Object()
+	key.Value("kid").IsEqual(TestingSigningKeyID)
+	key.Value("kty").IsEqual("OKP")
+	key.Value("crv").IsEqual("Ed25519")
+	key.Value("alg").IsEqual("EdDSA")
+	publicKey, err := base64.RawURLEncoding.DecodeString(key.Value("x").String().Raw())
+	require.NoError(s.T(), err)
+
+	tokenString := s.e.POST("/api/auth").
+		WithJSON(models.Auth{Username: "user", Password: "pass"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+
+	// other services verify tokens with published key only
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		require.Equal(s.T(), TestingSigningKeyID, token.Header["kid"])
+		return ed25519.PublicKey(publicKey), nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	require.NoError(s.T(), err)
+	require.True(s.T(), token.Valid)
+}
+
+func (s *E2ESuite) TestE2E_LegacyHS256Token() {
+	// token issued before switching to asymmetric keys
+	legacyToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "first",
+		"role": "user",
+		"jti":  "legacy",
+		"iss":  "AvitoShop",
+		"exp":  time.Now().Add(time.Minute).Unix(),
+		"iat":  time.Now().Unix(),
+	}).SignedString([]byte("abcdef12345"))
+	require.NoError(s.T(), err)
+
+	s.e.GET("/api/info").
+		WithHeader("Authorization", "Bearer "+legacyToken).
+		Expect().
+		Status(http.StatusOK)
+
+	forgedToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "first",
+		"role": "admin",
+		"jti":  "forged",
+		"exp":  time.Now().Add(time.Minute).Unix(),
+		"iat":  time.Now().Unix(),
+	}).SignedString([]byte("wrong key"))
+	require.NoError(s.T(), err)
+
+	s.e.GET("/api/info").
+		WithHeader("Authorization", "Bearer "+forgedToken).
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func (s *E2ESuite) TestE2E_RequestID() {
+	s.e.GET("/api/items").
+		WithHeader("X-Request-ID", "e2e-request-1").
+		Expect().
+		Status(http.StatusOK).
+		Header("X-Request-ID").IsEqual("e2e-request-1")
+
+	s.e.GET("/api/items").
+		Expect().
+		Status(http.StatusOK).
+		Header("X-Request-ID").NotEmpty()
+}
+
+func (s *E2ESuite) TestE2E_ErrorEnvelope() {
+	forged := s.e.GET("/api/info").
+		WithHeader("Authorization", "Bearer forged").
+		WithHeader("X-Request-ID", "e2e-error-1").
+		Expect().
+		Status(http.StatusUnauthorized).
+		JSON().
+		Object()
+	forged.Value("code").IsEqual("invalid_token")
+	forged.Value("errors").IsEqual("invalid token")
+	forged.Value("requestId").IsEqual("e2e-error-1")
+
+	token := s.e.POST("/api/auth").
+		WithJSON(models.Auth{Username: "user", Password: "pass"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+
+	unknown := s.e.GET("/api/buy/unknown").
+		WithHeader("Authorization", "Bearer "+token).
+		Expect().
+		Status(http.StatusBadRequest).
+		JSON().
+		Object()
+	unknown.Value("code").IsEqual("item_not_found")
+	unknown.Value("errors").IsEqual("Buying item: item not found")
+	unknown.Value("requestId").String().NotEmpty()
+
+	s.e.GET("/unknown").
+		Expect().
+		Status(http.StatusNotFound).
+		JSON().
+		Object().
+		Value("code").IsEqual("not_found")
+}
+
+func (s *E2ESuite) TestE2E_Health() {
+	s.e.GET("/healthz").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("status").IsEqual("ok")
+
+	ready := s.e.GET("/readyz").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	ready.Value("status").IsEqual("ok")
+	checks := ready.Value("checks").Object()
+	for _, name := range []string{"shutdown", "database", "migrations"} {
+		check := checks.Value(name).Object()
+		check.Value("status").IsEqual("ok")
+		check.NotContainsKey("error")
+	}
+}
+
+func (s *E2ESuite) TestE2E_Metrics() {
+	token := s.e.POST("/api/auth").
+		WithJSON(models.Auth{Username: "user", Password: "pass"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+	reqWithAuth.GET(fmt.Sprintf("/api/buy/%s", item2ToBuy)).
+		Expect().
+		Status(http.StatusOK)
+	reqWithAuth.GET("/api/buy/unknown").
+		Expect().
+		Status(http.StatusBadRequest)
+
+	body := s.e.GET("/metrics").
+		Expect().
+		Status(http.StatusOK).
+		Body()
+	body.Contains(`http_request_duration_seconds_count{method="GET",route="/api/buy/:item",status="200"}`)
+	body.Contains(`http_request_duration_seconds_count{method="GET",route="/api/buy/:item",status="400"}`)
+	body.Contains(fmt.Sprintf(`shop_purchases_total{item="%s"}`, item2ToBuy))
+	body.Contains(`shop_failures_total{operation="buy",reason="item not found"}`)
+	body.Contains("pgxpool_max_conns")
+}
+
+func (s *E2ESuite) TestE2E_RefreshAndLogout() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	refreshToken := r.Value("refreshToken").String().NotEmpty().Raw()
+
+	refreshed := s.e.POST("/api/auth/refresh").
+		WithJSON(models.RefreshToken{RefreshToken: refreshToken}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := refreshed.Value("token").String().NotEmpty().Raw()
+	newRefreshToken := refreshed.Value("refreshToken").String().NotEmpty().Raw()
+	require.NotEqual(s.T(), refreshToken, newRefreshToken)
+
+	// refresh token is rotated, so it can be used only once
+	s.e.POST("/api/auth/refresh").
+		WithJSON(models.RefreshToken{RefreshToken: refreshToken}).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusOK)
+
+	reqWithAuth.POST("/api/auth/logout").
+		WithJSON(models.RefreshToken{RefreshToken: newRefreshToken}).
+		Expect().
+		Status(http.StatusOK)
+
+	reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	s.e.POST("/api/auth/refresh").
+		WithJSON(models.RefreshToken{RefreshToken: newRefreshToken}).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	s.e.POST("/api/auth/refresh").
+		WithJSON(models.RefreshToken{}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func (s *E2ESuite) TestE2E_ChangePassword() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "password1",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := r.Value("token").String().NotEmpty().Raw()
+	refreshToken := r.Value("refreshToken").String().NotEmpty().Raw()
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	reqWithAuth.POST("/api/auth/password").
+		WithJSON(models.PasswordChange{OldPassword: "wrongPass1", NewPassword: "password2"}).
+		Expect().
+		Status(http.StatusForbidden)
+
+	reqWithAuth.POST("/api/auth/password").
+		WithJSON(models.PasswordChange{OldPassword: "password1", NewPassword: "weak"}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	// token issued in the same second as the password change is revoked too
+	changed := reqWithAuth.POST("/api/auth/password").
+		WithJSON(models.PasswordChange{OldPassword: "password1", NewPassword: "password2"}).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	newToken := changed.Value("token").String().NotEmpty().Raw()
+
+	reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	s.e.POST("/api/auth/refresh").
+		WithJSON(models.RefreshToken{RefreshToken: refreshToken}).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	s.e.GET("/api/info").
+		WithHeader("Authorization", "Bearer "+newToken).
+		Expect().
+		Status(http.StatusOK)
+
+	s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusUnauthorized)
+
+	s.e.POST("/api/auth").
+		WithJSON(models.Auth{Username: "user", Password: "password2"}).
+		Expect().
+		Status(http.StatusOK)
+}
+
+func (s *E2ESuite) TestE2E_SendCoins_Comment() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := r.Value("token").String().Raw()
+	require.NotEmpty(s.T(), token)
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	reqWithAuth.POST("/api/sendCoin").
+		WithJSON(models.CoinsTransfer{
+			ToUser:  "first",
+			Amount:  100,
+			Comment: "thanks\u0007 for review\n",
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("coinHistory").Object().
+		Value("sent").Array().Value(0).Object().
+		HasValue("toUser", "first").
+		HasValue("comment", "thanks for review")
+
+	reqWithAuth.GET("/api/transactions").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("transactions").Array().Value(0).Object().
+		HasValue("comment", "thanks for review")
+
+	reqWithAuth.POST("/api/sendCoin").
+		WithJSON(models.CoinsTransfer{
+			ToUser:  "first",
+			Amount:  100,
+			Comment: strings.Repeat("a", 129),
+		}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func (s *E2ESuite) TestE2E_GetTransactions() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := r.Value("token").String().Raw()
+	require.NotEmpty(s.T(), token)
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	for _, amount := range []int32{10, 20, 30} {
+		reqWithAuth.POST("/api/sendCoin").
+			WithJSON(models.CoinsTransfer{ToUser: "first", Amount: amount}).
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	firstPage := reqWithAuth.GET("/api/transactions").
+		WithQuery("limit", 2).
+		WithQuery("direction", "sent").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	firstPage.Value("transactions").Array().Length().IsEqual(2)
+	firstPage.Value("transactions").Array().Value(0).Object().
+		HasValue("amount", 30).
+		ContainsKey("id").
+		ContainsKey("time")
+	cursor := firstPage.Value("nextCursor").String().NotEmpty().Raw()
+
+	secondPage := reqWithAuth.GET("/api/transactions").
+		WithQuery("limit", 2).
+		WithQuery("direction", "sent").
+		WithQuery("cursor", cursor).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	secondPage.Value("transactions").Array().Length().IsEqual(1)
+	secondPage.NotContainsKey("nextCursor")
+
+	reqWithAuth.GET("/api/transactions").
+		WithQuery("direction", "received").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("transactions").Array().IsEmpty()
+
+	reqWithAuth.GET("/api/transactions").
+		WithQuery("cursor", "invalid").
+		Expect().
+		Status(http.StatusBadRequest)
+
+	reqWithAuth.GET("/api/transactions").
+		WithQuery("from", "yesterday").
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func (s *E2ESuite) TestE2E_SendCoins_Idempotent() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := r.Value("token").String().Raw()
+	require.NotEmpty(s.T(), token)
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+	sendCoinsReq := models.CoinsTransfer{
+		ToUser: "first",
+		Amount: 100,
+	}
+
+	for i := 0; i < 3; i++ {
+		reqWithAuth.POST("/api/sendCoin").
+			WithHeader("Idempotency-Key", "send-1").
+			WithJSON(sendCoinsReq).
+			Expect().
+			Status(http.StatusOK)
+	}
+
+	sendCoinsReq.Amount = 200
+	reqWithAuth.POST("/api/sendCoin").
+		WithHeader("Idempotency-Key", "send-1").
+		WithJSON(sendCoinsReq).
+		Expect().
+		Status(http.StatusUnprocessableEntity)
+
+	reqWithAuth.GET(fmt.Sprintf("/api/buy/%s", item1ToBuy)).
+		WithHeader("Idempotency-Key", "buy-1").
+		Expect().
+		Status(http.StatusOK)
+	reqWithAuth.GET(fmt.Sprintf("/api/buy/%s", item1ToBuy)).
+		WithHeader("Idempotency-Key", "buy-1").
+		Expect().
+		Status(http.StatusOK).
+		Header("Idempotent-Replayed").IsEqual("true")
+
+	reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		HasValue("coins", userCoinsOnRegister-100-item1ToBuyCost)
+}
+
 func (s *E2ESuite) TestE2E_BuyItem() {
 	authReq := models.Auth{
 		Username: "user",
@@ -120,6 +716,199 @@ func (s *E2ESuite) TestE2E_BuyItem() {
 		Status(http.StatusOK)
 }
 
+func (s *E2ESuite) TestE2E_BuyItems() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := r.Value("token").String().Raw()
+	require.NotEmpty(s.T(), token)
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	reqWithAuth.POST("/api/buy").
+		WithJSON(models.Cart{
+			Items: []*models.CartLine{
+				{Item: item2ToBuy, Quantity: 2},
+				{Item: item1ToBuy, Quantity: 1},
+			},
+		}).
+		Expect().
+		Status(http.StatusOK)
+
+	reqWithAuth.POST("/api/buy").
+		WithJSON(models.Cart{
+			Items: []*models.CartLine{
+				{Item: item1ToBuy, Quantity: userCoinsOnRegister / item1ToBuyCost},
+			},
+		}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	reqWithAuth.POST("/api/buy").
+		WithJSON(models.Cart{}).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	inventory := reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("inventory").Array()
+	inventory.Length().IsEqual(2)
+}
+
+func (s *E2ESuite) TestE2E_GetPurchases() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := r.Value("token").String().Raw()
+	require.NotEmpty(s.T(), token)
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	// item2ToBuyCost does not match catalog price, purchase keeps price from catalog
+	item2Price := s.e.GET(fmt.Sprintf("/api/items/%s", item2ToBuy)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("price").Number().Raw()
+
+	reqWithAuth.GET(fmt.Sprintf("/api/buy/%s", item1ToBuy)).
+		Expect().
+		Status(http.StatusOK)
+	reqWithAuth.GET(fmt.Sprintf("/api/buy/%s", item2ToBuy)).
+		Expect().
+		Status(http.StatusOK)
+
+	firstPage := reqWithAuth.GET("/api/purchases").
+		WithQuery("limit", 1).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	firstPage.Value("purchases").Array().Length().IsEqual(1)
+	firstPage.Value("purchases").Array().Value(0).Object().
+		HasValue("item", item2ToBuy).
+		HasValue("price", item2Price).
+		ContainsKey("id").
+		ContainsKey("time")
+	cursor := firstPage.Value("nextCursor").String().NotEmpty().Raw()
+
+	secondPage := reqWithAuth.GET("/api/purchases").
+		WithQuery("limit", 1).
+		WithQuery("cursor", cursor).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	secondPage.Value("purchases").Array().Value(0).Object().
+		HasValue("item", item1ToBuy).
+		HasValue("price", item1ToBuyCost)
+	secondPage.NotContainsKey("nextCursor")
+
+	reqWithAuth.GET("/api/purchases").
+		WithQuery("from", "2000-01-01T00:00:00Z").
+		WithQuery("to", "2000-01-02T00:00:00Z").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("purchases").Array().IsEmpty()
+
+	reqWithAuth.GET("/api/purchases").
+		WithQuery("limit", 0).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	s.e.GET("/api/purchases").
+		Expect().
+		Status(http.StatusUnauthorized)
+}
+
+func (s *E2ESuite) TestE2E_RefundPurchase() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+
+	r := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	token := r.Value("token").String().Raw()
+	require.NotEmpty(s.T(), token)
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	reqWithAuth.GET(fmt.Sprintf("/api/buy/%s", item1ToBuy)).
+		Expect().
+		Status(http.StatusOK)
+
+	purchaseID := reqWithAuth.GET("/api/purchases").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("purchases").Array().Value(0).Object().
+		Value("id").String().Raw()
+
+	reqWithAuth.POST(fmt.Sprintf("/api/purchases/%s/refund", purchaseID)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		HasValue("id", purchaseID).
+		HasValue("item", item1ToBuy).
+		HasValue("price", item1ToBuyCost).
+		HasValue("refunded", true)
+
+	info := reqWithAuth.GET("/api/info").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+	info.Value("coins").Number().IsEqual(userCoinsOnRegister)
+	info.Value("inventory").Array().IsEmpty()
+
+	reqWithAuth.POST(fmt.Sprintf("/api/purchases/%s/refund", purchaseID)).
+		Expect().
+		Status(http.StatusConflict)
+
+	reqWithAuth.POST("/api/purchases/00000000-0000-0000-0000-000000000000/refund").
+		Expect().
+		Status(http.StatusNotFound)
+
+	reqWithAuth.POST("/api/purchases/invalid/refund").
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
 func (s *E2ESuite) TestE2E_BuyItem_NotEnoughCoins() {
 	authReq := models.Auth{
 		Username: "user",
@@ -264,6 +1053,231 @@ func (s *E2ESuite) TestE2E_GetInventory() {
 		NotEmpty()
 }
 
+func (s *E2ESuite) TestE2E_GetItems() {
+	items := s.e.GET("/api/items").
+		WithQuery("sort", "price_desc").
+		WithQuery("minPrice", item2ToBuyCost).
+		WithQuery("maxPrice", item1ToBuyCost).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Array()
+	items.NotEmpty()
+	items.Value(0).Object().Value("name").String().IsEqual(item1ToBuy)
+	items.Value(0).Object().Value("price").Number().IsEqual(item1ToBuyCost)
+
+	s.e.GET(fmt.Sprintf("/api/items/%s", item1ToBuy)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		HasValue("name", item1ToBuy).
+		HasValue("price", item1ToBuyCost)
+
+	s.e.GET("/api/items/undefinedItem").
+		Expect().
+		Status(http.StatusNotFound)
+
+	s.e.GET("/api/items").
+		WithQuery("minPrice", "abc").
+		Expect().
+		Status(http.StatusBadRequest)
+
+	s.e.GET("/api/items").
+		WithQuery("minPrice", item1ToBuyCost).
+		WithQuery("maxPrice", item2ToBuyCost).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func (s *E2ESuite) TestE2E_AdminItems() {
+	const newItem = "sticker"
+	s.T().Cleanup(func() {
+		_, err := testDbInstance.Exec(context.Background(), `truncate table users cascade`)
+		require.NoError(s.T(), err)
+		_, err = testDbInstance.Exec(context.Background(), `delete from items where name = $1`, newItem)
+		require.NoError(s.T(), err)
+	})
+
+	authReq := models.Auth{
+		Username: adminUsername,
+		Password: "pass",
+	}
+	s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK)
+
+	query, args, err := s.builder.
+		Update("users").
+		Set("role", "admin").
+		Where(squirrel.Eq{"username": adminUsername}).
+		ToSql()
+	require.NoError(s.T(), err)
+	_, err = testDbInstance.Exec(
+		context.Background(),
+		query,
+		args...,
+	)
+	require.NoError(s.T(), err)
+
+	token := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+	adminReq := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	adminReq.POST("/api/admin/items").
+		WithJSON(models.CatalogItem{Name: newItem, Price: 5}).
+		Expect().
+		Status(http.StatusCreated)
+
+	adminReq.POST("/api/admin/items").
+		WithJSON(models.CatalogItem{Name: newItem, Price: 5}).
+		Expect().
+		Status(http.StatusConflict)
+
+	adminReq.PUT(fmt.Sprintf("/api/admin/items/%s", newItem)).
+		WithJSON(models.ItemPrice{Price: 7}).
+		Expect().
+		Status(http.StatusOK)
+
+	s.e.GET(fmt.Sprintf("/api/items/%s", newItem)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		HasValue("price", 7)
+
+	adminReq.GET(fmt.Sprintf("/api/buy/%s", newItem)).
+		Expect().
+		Status(http.StatusOK)
+
+	adminReq.PUT(fmt.Sprintf("/api/admin/items/%s/stock", newItem)).
+		WithJSON(map[string]interface{}{"stock": 1}).
+		Expect().
+		Status(http.StatusOK)
+
+	s.e.GET(fmt.Sprintf("/api/items/%s", newItem)).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		HasValue("stock", 1)
+
+	adminReq.GET(fmt.Sprintf("/api/buy/%s", newItem)).
+		Expect().
+		Status(http.StatusOK)
+
+	adminReq.GET(fmt.Sprintf("/api/buy/%s", newItem)).
+		Expect().
+		Status(http.StatusConflict)
+
+	adminReq.DELETE(fmt.Sprintf("/api/admin/items/%s", newItem)).
+		Expect().
+		Status(http.StatusOK)
+
+	adminReq.GET(fmt.Sprintf("/api/buy/%s", newItem)).
+		Expect().
+		Status(http.StatusBadRequest)
+
+	adminReq.GET("/api/info").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("inventory").Array().Value(0).Object().
+		HasValue("type", newItem).
+		HasValue("quantity", 2)
+}
+
+func (s *E2ESuite) TestE2E_AdminResetPassword() {
+	authReq := models.Auth{
+		Username: adminUsername,
+		Password: "pass",
+	}
+	s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK)
+
+	query, args, err := s.builder.
+		Update("users").
+		Set("role", "admin").
+		Where(squirrel.Eq{"username": adminUsername}).
+		ToSql()
+	require.NoError(s.T(), err)
+	_, err = testDbInstance.Exec(
+		context.Background(),
+		query,
+		args...,
+	)
+	require.NoError(s.T(), err)
+
+	token := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	reqWithAuth.PUT("/api/admin/users/first/password").
+		WithJSON(models.PasswordReset{Password: "password2"}).
+		Expect().
+		Status(http.StatusOK)
+
+	s.e.POST("/api/auth").
+		WithJSON(models.Auth{Username: "first", Password: "password2"}).
+		Expect().
+		Status(http.StatusOK)
+
+	reqWithAuth.PUT("/api/admin/users/unknown/password").
+		WithJSON(models.PasswordReset{Password: "password2"}).
+		Expect().
+		Status(http.StatusNotFound)
+
+	reqWithAuth.PUT("/api/admin/users/first/password").
+		WithJSON(models.PasswordReset{Password: "weak"}).
+		Expect().
+		Status(http.StatusBadRequest)
+}
+
+func (s *E2ESuite) TestE2E_AdminItems_Forbidden() {
+	authReq := models.Auth{
+		Username: "user",
+		Password: "pass",
+	}
+	token := s.e.POST("/api/auth").
+		WithJSON(authReq).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("token").String().Raw()
+	reqWithAuth := s.e.Builder(func(req *httpexpect.Request) {
+		req.WithHeader("Authorization", "Bearer "+token)
+	})
+
+	reqWithAuth.POST("/api/admin/items").
+		WithJSON(models.CatalogItem{Name: "sticker", Price: 5}).
+		Expect().
+		Status(http.StatusForbidden)
+
+	reqWithAuth.DELETE(fmt.Sprintf("/api/admin/items/%s", item1ToBuy)).
+		Expect().
+		Status(http.StatusForbidden)
+}
+
 func TestE2ETestSuite(t *testing.T) {
 	suite.Run(t, new(E2ESuite))
 }
@@ -86,6 +86,15 @@ func (s *IAuthSuite) TestAuthRepository_Register() {
 			},
 			wantErr: false,
 		}, // успешная регистрация
+		{
+			name: "регистрация с хешем argon2id",
+			authInfo: &entity.Auth{
+				Username: "test",
+				Password: "$argon2id$v=19$m=19456,t=2,p=1$c29tZXNhbHRzb21lc2FsdA$" +
+					"Zm9vYmFyZm9vYmFyZm9vYmFyZm9vYmFyZm9vYmFyZm9vYg",
+			},
+			wantErr: false,
+		}, // регистрация с хешем argon2id
 		{
 			name: "пользователь уже существует",
 			authInfo: &entity.Auth{
@@ -166,7 +175,7 @@ func (s *IAuthSuite) TestAuthRepository_GetByUsername() {
 			},
 			check: func(t *testing.T, username string, authFromRepo *entity.Auth) error {
 				checkQuery, args, err := s.builder.
-					Select("username", "password").
+					Select("username", "password", "role").
 					From("users").
 					Where(squirrel.Eq{"username": username}).
 					ToSql()
@@ -180,6 +189,7 @@ func (s *IAuthSuite) TestAuthRepository_GetByUsername() {
 				).Scan(
 					&dbUser.Username,
 					&dbUser.Password,
+					&dbUser.Role,
 				)
 				if errors.Is(dbErr, pgx.ErrNoRows) {
 					dbUser = nil
@@ -193,6 +203,9 @@ func (s *IAuthSuite) TestAuthRepository_GetByUsername() {
 				if dbUser.Password != authFromRepo.Password {
 					return fmt.Errorf("invalid password")
 				}
+				if authFromRepo.Role != entity.RoleUser {
+					return fmt.Errorf("invalid role")
+				}
 
 				return nil
 			},
@@ -231,6 +244,124 @@ func (s *IAuthSuite) TestAuthRepository_GetByUsername() {
 	}
 }
 
+func (s *IAuthSuite) TestAuthRepository_UpdatePassword() {
+	s.T().Run("успешная смена пароля", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		err := s.repo.Register(context.Background(), &entity.Auth{
+			Username: "test",
+			Password: "hashedPass",
+		})
+		require.NoError(t, err)
+
+		query, args, err := s.builder.
+			Insert("refresh_tokens").
+			Columns("username", "token_hash", "expires_at").
+			Values("test", "refresh", squirrel.Expr("current_timestamp + interval '1 hour'")).
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(context.Background(), query, args...)
+		require.NoError(t, err)
+
+		err = s.repo.UpdatePassword(context.Background(), &entity.Auth{
+			Username: "test",
+			Password: "newHashedPass",
+		})
+		require.NoError(t, err)
+
+		user, err := s.repo.GetByUsername(context.Background(), "test")
+		require.NoError(t, err)
+		require.Equal(t, "newHashedPass", user.Password)
+
+		var tokensValidAfterSet bool
+		err = testDbInstance.QueryRow(
+			context.Background(),
+			`select tokens_valid_after is not null from users where username = $1`,
+			"test",
+		).Scan(&tokensValidAfterSet)
+		require.NoError(t, err)
+		require.True(t, tokensValidAfterSet)
+
+		var activeRefreshTokens int
+		err = testDbInstance.QueryRow(
+			context.Background(),
+			`select count(*) from refresh_tokens where username = $1 and revoked_at is null`,
+			"test",
+		).Scan(&activeRefreshTokens)
+		require.NoError(t, err)
+		require.Equal(t, 0, activeRefreshTokens)
+	})
+
+	s.T().Run("пользователь не найден", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		err := s.repo.UpdatePassword(context.Background(), &entity.Auth{
+			Username: "test",
+			Password: "newHashedPass",
+		})
+		require.Equal(t, errs.UserNotFound, err)
+	})
+}
+
+func (s *IAuthSuite) TestAuthRepository_UpdatePasswordHash() {
+	s.T().Run("успешное обновление хеша", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		err := s.repo.Register(context.Background(), &entity.Auth{
+			Username: "test",
+			Password: "hashedPass",
+		})
+		require.NoError(t, err)
+
+		err = s.repo.UpdatePasswordHash(context.Background(), &entity.Auth{
+			Username: "test",
+			Password: "rehashedPass",
+		}, "hashedPass")
+		require.NoError(t, err)
+
+		user, err := s.repo.GetByUsername(context.Background(), "test")
+		require.NoError(t, err)
+		require.Equal(t, "rehashedPass", user.Password)
+
+		var tokensValidAfterSet bool
+		err = testDbInstance.QueryRow(
+			context.Background(),
+			`select tokens_valid_after is not null from users where username = $1`,
+			"test",
+		).Scan(&tokensValidAfterSet)
+		require.NoError(t, err)
+		require.False(t, tokensValidAfterSet)
+	})
+
+	s.T().Run("пароль изменен после чтения хеша", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		err := s.repo.Register(context.Background(), &entity.Auth{
+			Username: "test",
+			Password: "changedPass",
+		})
+		require.NoError(t, err)
+
+		err = s.repo.UpdatePasswordHash(context.Background(), &entity.Auth{
+			Username: "test",
+			Password: "rehashedPass",
+		}, "hashedPass")
+		require.NoError(t, err)
+
+		user, err := s.repo.GetByUsername(context.Background(), "test")
+		require.NoError(t, err)
+		require.Equal(t, "changedPass", user.Password)
+	})
+}
+
 func TestIAuthTestSuite(t *testing.T) {
 	suite.Run(t, new(IAuthSuite))
 }
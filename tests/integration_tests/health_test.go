@@ -0,0 +1,55 @@
+package integration_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type IHealthRepoSuite struct {
+	suite.Suite
+	repo entity.IHealthRepository
+}
+
+func (s *IHealthRepoSuite) SetupSuite() {
+	s.repo = postgres.NewHealthRepository(testDbInstance)
+}
+
+func (s *IHealthRepoSuite) Test_healthRepository_Ping() {
+	err := s.repo.Ping(context.Background())
+	require.NoError(s.T(), err)
+}
+
+// test database is migrated with all migrations, so its version must match the expected one
+func (s *IHealthRepoSuite) Test_healthRepository_GetSchemaVersion() {
+	version, err := s.repo.GetSchemaVersion(context.Background())
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), int64(postgres.SchemaVersion), version.Version)
+	require.False(s.T(), version.Dirty)
+}
+
+// test database is migrated by the same embedded migrations, so nothing is pending
+func (s *IHealthRepoSuite) Test_Migrator_Status() {
+	migrator, err := postgres.NewMigrator(testDbInstance)
+	require.NoError(s.T(), err)
+	defer func() {
+		require.NoError(s.T(), migrator.Close())
+	}()
+
+	require.NoError(s.T(), migrator.Up())
+	list, dirty, err := migrator.Status()
+	require.NoError(s.T(), err)
+	require.False(s.T(), dirty)
+	require.Len(s.T(), list, int(postgres.SchemaVersion))
+	for _, migration := range list {
+		require.True(s.T(), migration.Applied, migration.Name)
+	}
+}
+
+func TestIHealthRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(IHealthRepoSuite))
+}
@@ -0,0 +1,377 @@
+package integration_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const concurrentReplays = 20
+
+type IIdempotencyRepoSuite struct {
+	suite.Suite
+	repo     entity.IIdempotencyRepository
+	userRepo entity.IUserRepository
+	builder  squirrel.StatementBuilderType
+}
+
+func (s *IIdempotencyRepoSuite) SetupSuite() {
+	s.repo = postgres.NewIdempotencyRepository(testDbInstance)
+	s.userRepo = postgres.NewUserRepository(testDbInstance)
+	s.builder = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+}
+
+func (s *IIdempotencyRepoSuite) TearDownSubTest() {
+	query := `truncate table users cascade`
+	_, err := testDbInstance.Exec(context.Background(), query)
+	require.NoError(s.T(), err)
+}
+
+func (s *IIdempotencyRepoSuite) createUsers(t *testing.T, usernames ...string) {
+	builder := s.builder.
+		Insert("users").
+		Columns("username", "password")
+	for _, username := range usernames {
+		builder = builder.Values(username, "hashedPass")
+	}
+	query, args, err := builder.ToSql()
+	require.NoError(t, err)
+
+	_, err = testDbInstance.Exec(
+		context.Background(),
+		query,
+		args...,
+	)
+	require.NoError(t, err)
+}
+
+func (s *IIdempotencyRepoSuite) Test_idempotencyRepository_Reserve() {
+	record := &entity.IdempotencyRecord{
+		Username:    "user",
+		Key:         "key",
+		RequestHash: "hash",
+	}
+
+	s.T().Run("резервирование, повтор и сохранение ответа", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, record.Username)
+
+		stored, err := s.repo.Reserve(context.Background(), record)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		_, err = s.repo.Reserve(context.Background(), record)
+		require.Equal(t, errs.RequestInProgress, err)
+
+		_, err = s.repo.Reserve(context.Background(), &entity.IdempotencyRecord{
+			Username:    record.Username,
+			Key:         record.Key,
+			RequestHash: "otherHash",
+		})
+		require.Equal(t, errs.KeyReused, err)
+
+		err = s.repo.SaveResponse(context.Background(), &entity.IdempotencyRecord{
+			Username:   record.Username,
+			Key:        record.Key,
+			StatusCode: 200,
+			Response:   []byte(`{"ok":true}`),
+			ReservedAt: record.ReservedAt,
+		})
+		require.NoError(t, err)
+
+		stored, err = s.repo.Reserve(context.Background(), record)
+		require.NoError(t, err)
+		require.Equal(t, &entity.IdempotencyRecord{
+			Username:    record.Username,
+			Key:         record.Key,
+			RequestHash: record.RequestHash,
+			StatusCode:  200,
+			Response:    []byte(`{"ok":true}`),
+		}, stored)
+	})
+
+	s.T().Run("освобождение ключа", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, record.Username)
+
+		stored, err := s.repo.Reserve(context.Background(), record)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		err = s.repo.Release(context.Background(), record)
+		require.NoError(t, err)
+
+		stored, err = s.repo.Reserve(context.Background(), record)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	})
+
+	s.T().Run("одинаковый ключ у разных пользователей", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, "first", "second")
+
+		stored, err := s.repo.Reserve(context.Background(), &entity.IdempotencyRecord{
+			Username:    "first",
+			Key:         record.Key,
+			RequestHash: record.RequestHash,
+		})
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		stored, err = s.repo.Reserve(context.Background(), &entity.IdempotencyRecord{
+			Username:    "second",
+			Key:         record.Key,
+			RequestHash: record.RequestHash,
+		})
+		require.NoError(t, err)
+		require.Nil(t, stored)
+	})
+}
+
+func (s *IIdempotencyRepoSuite) Test_idempotencyRepository_ConcurrentReplays() {
+	s.T().Run("одновременные повторы резервируют ключ один раз", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, "user")
+
+		record := &entity.IdempotencyRecord{
+			Username:    "user",
+			Key:         "key",
+			RequestHash: "hash",
+		}
+
+		var reserved, inProgress atomic.Int32
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < concurrentReplays; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				record := *record
+				stored, err := s.repo.Reserve(context.Background(), &record)
+				if err == nil && stored == nil {
+					reserved.Add(1)
+				} else if err == errs.RequestInProgress {
+					inProgress.Add(1)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		require.Equal(t, int32(1), reserved.Load())
+		require.Equal(t, int32(concurrentReplays-1), inProgress.Load())
+	})
+
+	s.T().Run("одновременные повторы перевода списывают монеты один раз", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, "first", "second")
+
+		transfer := &entity.TransferCoins{
+			FromUser: "first",
+			ToUser:   "second",
+			Amount:   100,
+		}
+		record := &entity.IdempotencyRecord{
+			Username:    transfer.FromUser,
+			Key:         "transfer-key",
+			RequestHash: "hash",
+		}
+
+		var sent atomic.Int32
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < concurrentReplays; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				record := *record
+				stored, err := s.repo.Reserve(context.Background(), &record)
+				if err != nil || stored != nil {
+					return
+				}
+				err = s.repo.InTransaction(context.Background(), func(ctx context.Context) error {
+					err := s.userRepo.SendCoins(ctx, transfer)
+					if err != nil {
+						return err
+					}
+					record.StatusCode = 200
+					return s.repo.SaveResponse(ctx, &record)
+				})
+				if err != nil {
+					_ = s.repo.Release(context.Background(), &record)
+					return
+				}
+				sent.Add(1)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		require.Equal(t, int32(1), sent.Load())
+
+		stored, err := s.repo.Reserve(context.Background(), record)
+		require.NoError(t, err)
+		require.Equal(t, 200, stored.StatusCode)
+
+		var coins int32
+		err = testDbInstance.QueryRow(
+			context.Background(),
+			`select coins from users where username = $1`,
+			transfer.FromUser,
+		).Scan(&coins)
+		require.NoError(t, err)
+		require.Equal(t, userCoinsOnRegister-transfer.Amount, coins)
+	})
+}
+
+func (s *IIdempotencyRepoSuite) userCoins(t *testing.T, username string) int32 {
+	var coins int32
+	err := testDbInstance.QueryRow(
+		context.Background(),
+		`select coins from users where username = $1`,
+		username,
+	).Scan(&coins)
+	require.NoError(t, err)
+	return coins
+}
+
+func (s *IIdempotencyRepoSuite) ageKey(t *testing.T, record *entity.IdempotencyRecord, age time.Duration) {
+	_, err := testDbInstance.Exec(
+		context.Background(),
+		`update idempotency_keys set created_at = created_at - $3::interval where username = $1 and key = $2`,
+		record.Username, record.Key, age,
+	)
+	require.NoError(t, err)
+}
+
+func (s *IIdempotencyRepoSuite) Test_idempotencyRepository_InTransaction() {
+	transfer := &entity.TransferCoins{
+		FromUser: "first",
+		ToUser:   "second",
+		Amount:   100,
+	}
+
+	s.T().Run("перевод откатывается, если ответ не сохранен", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, "first", "second")
+		record := &entity.IdempotencyRecord{Username: "first", Key: "key", RequestHash: "hash"}
+		_, err := s.repo.Reserve(context.Background(), record)
+		require.NoError(t, err)
+
+		errSave := fmt.Errorf("saving response failed")
+		err = s.repo.InTransaction(context.Background(), func(ctx context.Context) error {
+			err := s.userRepo.SendCoins(ctx, transfer)
+			require.NoError(t, err)
+			return errSave
+		})
+		require.ErrorIs(t, err, errSave)
+		require.Equal(t, userCoinsOnRegister, s.userCoins(t, "first"))
+
+		_, err = s.repo.Reserve(context.Background(), &entity.IdempotencyRecord{
+			Username: "first", Key: "key", RequestHash: "hash",
+		})
+		require.Equal(t, errs.RequestInProgress, err)
+	})
+
+	s.T().Run("перехват ключа после истечения аренды", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, "first", "second")
+		stale := &entity.IdempotencyRecord{Username: "first", Key: "key", RequestHash: "hash"}
+		_, err := s.repo.Reserve(context.Background(), stale)
+		require.NoError(t, err)
+		s.ageKey(t, stale, 2*time.Minute)
+
+		retry := &entity.IdempotencyRecord{Username: "first", Key: "key", RequestHash: "hash"}
+		stored, err := s.repo.Reserve(context.Background(), retry)
+		require.NoError(t, err)
+		require.Nil(t, stored)
+
+		// previous holder can not commit its writes anymore
+		err = s.repo.InTransaction(context.Background(), func(ctx context.Context) error {
+			err := s.userRepo.SendCoins(ctx, transfer)
+			require.NoError(t, err)
+			stale.StatusCode = 200
+			return s.repo.SaveResponse(ctx, stale)
+		})
+		require.ErrorIs(t, err, errs.RequestInProgress)
+		require.Equal(t, userCoinsOnRegister, s.userCoins(t, "first"))
+
+		err = s.repo.InTransaction(context.Background(), func(ctx context.Context) error {
+			err := s.userRepo.SendCoins(ctx, transfer)
+			require.NoError(t, err)
+			retry.StatusCode = 200
+			return s.repo.SaveResponse(ctx, retry)
+		})
+		require.NoError(t, err)
+		require.Equal(t, userCoinsOnRegister-transfer.Amount, s.userCoins(t, "first"))
+
+		// completed key is not taken over
+		s.ageKey(t, retry, 2*time.Minute)
+		stored, err = s.repo.Reserve(context.Background(), &entity.IdempotencyRecord{
+			Username: "first", Key: "key", RequestHash: "hash",
+		})
+		require.NoError(t, err)
+		require.Equal(t, 200, stored.StatusCode)
+	})
+}
+
+func (s *IIdempotencyRepoSuite) Test_idempotencyRepository_DeleteExpired() {
+	s.T().Run("удаление устаревших ключей", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUsers(t, "user")
+		expired := &entity.IdempotencyRecord{Username: "user", Key: "expired", RequestHash: "hash"}
+		actual := &entity.IdempotencyRecord{Username: "user", Key: "actual", RequestHash: "hash"}
+		_, err := s.repo.Reserve(context.Background(), expired)
+		require.NoError(t, err)
+		_, err = s.repo.Reserve(context.Background(), actual)
+		require.NoError(t, err)
+		s.ageKey(t, expired, 25*time.Hour)
+
+		err = s.repo.DeleteExpired(context.Background(), time.Now())
+		require.NoError(t, err)
+
+		var keys []string
+		rows, err := testDbInstance.Query(context.Background(), `select key from idempotency_keys`)
+		require.NoError(t, err)
+		defer rows.Close()
+		for rows.Next() {
+			var key string
+			require.NoError(t, rows.Scan(&key))
+			keys = append(keys, key)
+		}
+		require.NoError(t, rows.Err())
+		require.Equal(t, []string{"actual"}, keys)
+	})
+}
+
+func TestIIdempotencyRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(IIdempotencyRepoSuite))
+}
@@ -6,7 +6,9 @@ import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/stretchr/testify/require"
@@ -167,6 +169,121 @@ func (s *IItemRepoSuite) Test_itemRepository_BuyItem() {
 	}
 }
 
+func (s *IItemRepoSuite) Test_itemRepository_BuyItems() {
+	testCases := []struct {
+		name        string
+		cart        *entity.Cart
+		coins       int32
+		inventory   []*entity.Item
+		coinsLeft   int32
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешная покупка корзины",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "socks", Quantity: 5},
+					{ItemName: "cup", Quantity: 1},
+				},
+			},
+			coins: 1000,
+			inventory: []*entity.Item{
+				{Name: "cup", Quantity: 1},
+				{Name: "socks", Quantity: 5},
+			},
+			coinsLeft: 1000 - 5*10 - 20,
+			wantErr:   false,
+		}, // успешная покупка корзины
+		{
+			name: "покупка большого количества единиц",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "pen", Quantity: 1000},
+				},
+			},
+			coins: 10000,
+			inventory: []*entity.Item{
+				{Name: "pen", Quantity: 1000},
+			},
+			coinsLeft: 0,
+			wantErr:   false,
+		}, // покупка большого количества единиц
+		{
+			name: "не хватает монет на всю корзину",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "socks", Quantity: 5},
+					{ItemName: "pink-hoody", Quantity: 2},
+				},
+			},
+			coins:       1000,
+			inventory:   []*entity.Item{},
+			coinsLeft:   1000,
+			wantErr:     true,
+			requiredErr: errs.NotEnoughCoins,
+		}, // не хватает монет на всю корзину
+		{
+			name: "один из предметов не найден",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "socks", Quantity: 5},
+					{ItemName: "undefined", Quantity: 1},
+				},
+			},
+			coins:       1000,
+			inventory:   []*entity.Item{},
+			coinsLeft:   1000,
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // один из предметов не найден
+	}
+	for _, tt := range testCases {
+		s.T().Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				s.TearDownSubTest()
+			})
+
+			query, args, err := s.builder.
+				Insert("users").
+				Columns("username", "password", "coins").
+				Values(tt.cart.Username, "hashedPass", tt.coins).
+				ToSql()
+			require.NoError(t, err)
+			_, err = testDbInstance.Exec(
+				context.Background(),
+				query,
+				args...,
+			)
+			require.NoError(t, err)
+
+			err = s.repo.BuyItems(context.Background(), tt.cart)
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			inventory, err := s.repo.GetInventory(context.Background(), tt.cart.Username)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.inventory, inventory)
+
+			var coins int32
+			err = testDbInstance.QueryRow(
+				context.Background(),
+				`select coins from users where username = $1`,
+				tt.cart.Username,
+			).Scan(&coins)
+			require.NoError(t, err)
+			require.Equal(t, tt.coinsLeft, coins)
+		})
+	}
+}
+
 func (s *IItemRepoSuite) Test_itemRepository_GetInventory() {
 	testCases := []struct {
 		name        string
@@ -206,10 +323,10 @@ func (s *IItemRepoSuite) Test_itemRepository_GetInventory() {
 
 				tmpBuilder := s.builder.
 					Insert("purchases").
-					Columns("username", "item")
+					Columns("username", "item", "price")
 				for _, item := range inventory {
 					for i := int32(0); i < item.Quantity; i++ {
-						tmpBuilder = tmpBuilder.Values(username, item.Name)
+						tmpBuilder = tmpBuilder.Values(username, item.Name, item.Price)
 					}
 				}
 				query, args, err = tmpBuilder.ToSql()
@@ -254,6 +371,675 @@ func (s *IItemRepoSuite) Test_itemRepository_GetInventory() {
 	}
 }
 
+func (s *IItemRepoSuite) Test_itemRepository_GetItems() {
+	minPrice := int32(50)
+	maxPrice := int32(200)
+	noItemsPrice := int32(1000)
+
+	testCases := []struct {
+		name        string
+		filter      *entity.ItemsFilter
+		items       []*entity.Item
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "фильтрация по цене с сортировкой по убыванию",
+			filter: &entity.ItemsFilter{
+				MinPrice: &minPrice,
+				MaxPrice: &maxPrice,
+				Sort:     entity.SortByPriceDesc,
+			},
+			items: []*entity.Item{
+				{Name: "powerbank", Price: 200},
+				{Name: "umbrella", Price: 200},
+				{Name: "t-shirt", Price: 80},
+				{Name: "book", Price: 50},
+				{Name: "wallet", Price: 50},
+			},
+			wantErr: false,
+		}, // фильтрация по цене с сортировкой по убыванию
+		{
+			name: "сортировка по возрастанию цены",
+			filter: &entity.ItemsFilter{
+				MaxPrice: &minPrice,
+				Sort:     entity.SortByPriceAsc,
+			},
+			items: []*entity.Item{
+				{Name: "pen", Price: 10},
+				{Name: "socks", Price: 10},
+				{Name: "cup", Price: 20},
+				{Name: "book", Price: 50},
+				{Name: "wallet", Price: 50},
+			},
+			wantErr: false,
+		}, // сортировка по возрастанию цены
+		{
+			name: "нет предметов в диапазоне цен",
+			filter: &entity.ItemsFilter{
+				MinPrice: &noItemsPrice,
+			},
+			items:   []*entity.Item{},
+			wantErr: false,
+		}, // нет предметов в диапазоне цен
+	}
+	for _, tt := range testCases {
+		s.T().Run(tt.name, func(t *testing.T) {
+			items, err := s.repo.GetItems(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, items)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.items, items)
+			}
+		})
+	}
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_GetItem() {
+	testCases := []struct {
+		name        string
+		itemName    string
+		item        *entity.Item
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:     "успешное получение предмета",
+			itemName: "cup",
+			item: &entity.Item{
+				Name:  "cup",
+				Price: 20,
+			},
+			wantErr: false,
+		}, // успешное получение предмета
+		{
+			name:        "предмет не найден",
+			itemName:    "undefined",
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // предмет не найден
+	}
+	for _, tt := range testCases {
+		s.T().Run(tt.name, func(t *testing.T) {
+			item, err := s.repo.GetItem(context.Background(), tt.itemName)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, item)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.item, item)
+			}
+		})
+	}
+}
+
+func (s *IItemRepoSuite) deleteTestItem(t *testing.T, name string) {
+	query, args, err := s.builder.
+		Delete("items").
+		Where(squirrel.Eq{"name": name}).
+		ToSql()
+	require.NoError(t, err)
+
+	_, err = testDbInstance.Exec(
+		context.Background(),
+		query,
+		args...,
+	)
+	require.NoError(t, err)
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_CreateItem() {
+	testCases := []struct {
+		name        string
+		item        *entity.Item
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешное создание предмета",
+			item: &entity.Item{
+				Name:  "sticker",
+				Price: 5,
+			},
+			wantErr: false,
+		}, // успешное создание предмета
+		{
+			name: "предмет уже существует",
+			item: &entity.Item{
+				Name:  "cup",
+				Price: 5,
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemAlreadyExists,
+		}, // предмет уже существует
+	}
+	for _, tt := range testCases {
+		s.T().Run(tt.name, func(t *testing.T) {
+			err := s.repo.CreateItem(context.Background(), tt.item)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				t.Cleanup(func() {
+					s.deleteTestItem(t, tt.item.Name)
+				})
+
+				require.NoError(t, err)
+				item, err := s.repo.GetItem(context.Background(), tt.item.Name)
+				require.NoError(t, err)
+				require.Equal(t, tt.item, item)
+			}
+		})
+	}
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_UpdateItemPrice() {
+	testCases := []struct {
+		name        string
+		item        *entity.Item
+		beforeTest  func(t *testing.T)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешное изменение цены",
+			item: &entity.Item{
+				Name:  "sticker",
+				Price: 15,
+			},
+			beforeTest: func(t *testing.T) {
+				err := s.repo.CreateItem(context.Background(), &entity.Item{
+					Name:  "sticker",
+					Price: 5,
+				})
+				require.NoError(t, err)
+			},
+			wantErr: false,
+		}, // успешное изменение цены
+		{
+			name: "предмет снят с продажи",
+			item: &entity.Item{
+				Name:  "sticker",
+				Price: 15,
+			},
+			beforeTest: func(t *testing.T) {
+				err := s.repo.CreateItem(context.Background(), &entity.Item{
+					Name:  "sticker",
+					Price: 5,
+				})
+				require.NoError(t, err)
+				err = s.repo.RetireItem(context.Background(), "sticker")
+				require.NoError(t, err)
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // предмет снят с продажи
+		{
+			name: "предмет не найден",
+			item: &entity.Item{
+				Name:  "undefined",
+				Price: 15,
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // предмет не найден
+	}
+	for _, tt := range testCases {
+		s.T().Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				s.deleteTestItem(t, tt.item.Name)
+			})
+
+			if tt.beforeTest != nil {
+				tt.beforeTest(t)
+			}
+
+			err := s.repo.UpdateItemPrice(context.Background(), tt.item)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.NoError(t, err)
+				item, err := s.repo.GetItem(context.Background(), tt.item.Name)
+				require.NoError(t, err)
+				require.Equal(t, tt.item, item)
+			}
+		})
+	}
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_BuyItem_LimitedStock() {
+	const itemName = "sticker"
+
+	s.T().Run("покупка уменьшает остаток до нуля", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+			s.deleteTestItem(t, itemName)
+		})
+
+		stock := int32(2)
+		err := s.repo.CreateItem(context.Background(), &entity.Item{
+			Name:  itemName,
+			Price: 5,
+			Stock: &stock,
+		})
+		require.NoError(t, err)
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("user", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(
+			context.Background(),
+			query,
+			args...,
+		)
+		require.NoError(t, err)
+
+		purchase := &entity.Purchase{
+			Username: "user",
+			ItemName: itemName,
+		}
+		for i := int32(0); i < stock; i++ {
+			err = s.repo.BuyItem(context.Background(), purchase)
+			require.NoError(t, err)
+		}
+
+		err = s.repo.BuyItem(context.Background(), purchase)
+		require.Equal(t, errs.OutOfStock, err)
+
+		item, err := s.repo.GetItem(context.Background(), itemName)
+		require.NoError(t, err)
+		require.NotNil(t, item.Stock)
+		require.Equal(t, int32(0), *item.Stock)
+
+		inventory, err := s.repo.GetInventory(context.Background(), "user")
+		require.NoError(t, err)
+		require.Equal(t, []*entity.Item{{Name: itemName, Quantity: stock}}, inventory)
+	})
+
+	s.T().Run("параллельные покупки не превышают остаток", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+			s.deleteTestItem(t, itemName)
+		})
+
+		stock := int32(5)
+		err := s.repo.CreateItem(context.Background(), &entity.Item{
+			Name:  itemName,
+			Price: 5,
+			Stock: &stock,
+		})
+		require.NoError(t, err)
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("user", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(
+			context.Background(),
+			query,
+			args...,
+		)
+		require.NoError(t, err)
+
+		const buyers = 20
+		results := make(chan error, buyers)
+		var wg sync.WaitGroup
+		for i := 0; i < buyers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results <- s.repo.BuyItem(context.Background(), &entity.Purchase{
+					Username: "user",
+					ItemName: itemName,
+				})
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		bought := int32(0)
+		for err := range results {
+			if err == nil {
+				bought++
+				continue
+			}
+			require.Equal(t, errs.OutOfStock, err)
+		}
+		require.Equal(t, stock, bought)
+
+		item, err := s.repo.GetItem(context.Background(), itemName)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), *item.Stock)
+	})
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_UpdateItemStock() {
+	const itemName = "sticker"
+
+	s.T().Run("пополнение и снятие ограничения остатка", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.deleteTestItem(t, itemName)
+		})
+
+		err := s.repo.CreateItem(context.Background(), &entity.Item{
+			Name:  itemName,
+			Price: 5,
+		})
+		require.NoError(t, err)
+
+		stock := int32(50)
+		err = s.repo.UpdateItemStock(context.Background(), &entity.Item{
+			Name:  itemName,
+			Stock: &stock,
+		})
+		require.NoError(t, err)
+
+		item, err := s.repo.GetItem(context.Background(), itemName)
+		require.NoError(t, err)
+		require.Equal(t, &stock, item.Stock)
+
+		err = s.repo.UpdateItemStock(context.Background(), &entity.Item{
+			Name: itemName,
+		})
+		require.NoError(t, err)
+
+		item, err = s.repo.GetItem(context.Background(), itemName)
+		require.NoError(t, err)
+		require.Nil(t, item.Stock)
+
+		err = s.repo.UpdateItemStock(context.Background(), &entity.Item{
+			Name:  "undefined",
+			Stock: &stock,
+		})
+		require.Equal(t, errs.ItemNotFound, err)
+	})
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_RetireItem() {
+	const itemName = "sticker"
+
+	s.T().Run("снятый с продажи предмет остается в инвентаре", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+			s.deleteTestItem(t, itemName)
+		})
+
+		err := s.repo.CreateItem(context.Background(), &entity.Item{
+			Name:  itemName,
+			Price: 5,
+		})
+		require.NoError(t, err)
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("user", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(
+			context.Background(),
+			query,
+			args...,
+		)
+		require.NoError(t, err)
+
+		purchase := &entity.Purchase{
+			Username: "user",
+			ItemName: itemName,
+		}
+		err = s.repo.BuyItem(context.Background(), purchase)
+		require.NoError(t, err)
+
+		err = s.repo.RetireItem(context.Background(), itemName)
+		require.NoError(t, err)
+
+		err = s.repo.BuyItem(context.Background(), purchase)
+		require.Equal(t, errs.ItemNotFound, err)
+
+		_, err = s.repo.GetItem(context.Background(), itemName)
+		require.Equal(t, errs.ItemNotFound, err)
+
+		inventory, err := s.repo.GetInventory(context.Background(), "user")
+		require.NoError(t, err)
+		require.Equal(t, []*entity.Item{{Name: itemName, Quantity: 1}}, inventory)
+
+		err = s.repo.RetireItem(context.Background(), itemName)
+		require.Equal(t, errs.ItemNotFound, err)
+	})
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_GetPurchases() {
+	const itemName = "sticker"
+
+	s.T().Run("история покупок сохраняет цену на момент покупки", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+			s.deleteTestItem(t, itemName)
+		})
+
+		err := s.repo.CreateItem(context.Background(), &entity.Item{
+			Name:  itemName,
+			Price: 5,
+		})
+		require.NoError(t, err)
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("user", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(
+			context.Background(),
+			query,
+			args...,
+		)
+		require.NoError(t, err)
+
+		purchase := &entity.Purchase{
+			Username: "user",
+			ItemName: itemName,
+		}
+		err = s.repo.BuyItem(context.Background(), purchase)
+		require.NoError(t, err)
+
+		err = s.repo.UpdateItemPrice(context.Background(), &entity.Item{
+			Name:  itemName,
+			Price: 7,
+		})
+		require.NoError(t, err)
+
+		err = s.repo.BuyItems(context.Background(), &entity.Cart{
+			Username: "user",
+			Lines: []*entity.CartLine{
+				{ItemName: itemName, Quantity: 2},
+			},
+		})
+		require.NoError(t, err)
+
+		all, err := s.repo.GetPurchases(context.Background(), &entity.PurchasesFilter{
+			Username: "user",
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+		require.Equal(t, int32(7), all[0].Price)
+		require.Equal(t, int32(7), all[1].Price)
+		require.Equal(t, int32(5), all[2].Price)
+		for _, p := range all {
+			require.Equal(t, itemName, p.ItemName)
+			require.NotEmpty(t, p.ID)
+		}
+
+		firstPage, err := s.repo.GetPurchases(context.Background(), &entity.PurchasesFilter{
+			Username: "user",
+			Limit:    2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, all[:2], firstPage)
+
+		secondPage, err := s.repo.GetPurchases(context.Background(), &entity.PurchasesFilter{
+			Username: "user",
+			After: &entity.PurchasesCursor{
+				Time: firstPage[1].Time,
+				ID:   firstPage[1].ID,
+			},
+			Limit: 2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, all[2:], secondPage)
+
+		to := all[2].Time.Add(time.Microsecond)
+		inRange, err := s.repo.GetPurchases(context.Background(), &entity.PurchasesFilter{
+			Username: "user",
+			To:       &to,
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Len(t, inRange, 1)
+		require.Equal(t, int32(5), inRange[0].Price)
+
+		from := all[0].Time.Add(time.Hour)
+		empty, err := s.repo.GetPurchases(context.Background(), &entity.PurchasesFilter{
+			Username: "user",
+			From:     &from,
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, empty)
+	})
+}
+
+func (s *IItemRepoSuite) Test_itemRepository_RefundPurchase() {
+	const itemName = "sticker"
+
+	createUserWithPurchase := func(t *testing.T) *entity.PurchaseRecord {
+		stock := int32(1)
+		err := s.repo.CreateItem(context.Background(), &entity.Item{
+			Name:  itemName,
+			Price: 5,
+			Stock: &stock,
+		})
+		require.NoError(t, err)
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("user", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(
+			context.Background(),
+			query,
+			args...,
+		)
+		require.NoError(t, err)
+
+		err = s.repo.BuyItem(context.Background(), &entity.Purchase{
+			Username: "user",
+			ItemName: itemName,
+		})
+		require.NoError(t, err)
+
+		purchases, err := s.repo.GetPurchases(context.Background(), &entity.PurchasesFilter{
+			Username: "user",
+			Limit:    1,
+		})
+		require.NoError(t, err)
+		require.Len(t, purchases, 1)
+		return purchases[0]
+	}
+
+	s.T().Run("успешный возврат", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+			s.deleteTestItem(t, itemName)
+		})
+		purchase := createUserWithPurchase(t)
+
+		refund := &entity.Refund{
+			Username:   "user",
+			PurchaseID: purchase.ID,
+			Window:     time.Hour,
+		}
+		refunded, err := s.repo.RefundPurchase(context.Background(), refund)
+		require.NoError(t, err)
+		require.True(t, refunded.Refunded)
+		require.Equal(t, int32(5), refunded.Price)
+
+		var coins int32
+		err = testDbInstance.QueryRow(context.Background(),
+			"select coins from users where username = $1", "user").Scan(&coins)
+		require.NoError(t, err)
+		require.Equal(t, int32(1000), coins)
+
+		item, err := s.repo.GetItem(context.Background(), itemName)
+		require.NoError(t, err)
+		require.Equal(t, int32(1), *item.Stock)
+
+		inventory, err := s.repo.GetInventory(context.Background(), "user")
+		require.NoError(t, err)
+		require.Empty(t, inventory)
+
+		var auditCoins int32
+		err = testDbInstance.QueryRow(context.Background(),
+			"select coins from purchase_refunds where purchase_id = $1", purchase.ID).Scan(&auditCoins)
+		require.NoError(t, err)
+		require.Equal(t, int32(5), auditCoins)
+
+		_, err = s.repo.RefundPurchase(context.Background(), refund)
+		require.Equal(t, errs.AlreadyRefunded, err)
+	})
+
+	s.T().Run("срок возврата истек", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+			s.deleteTestItem(t, itemName)
+		})
+		purchase := createUserWithPurchase(t)
+
+		_, err := testDbInstance.Exec(context.Background(),
+			"update purchases set time = time - interval '2 hours' where id = $1", purchase.ID)
+		require.NoError(t, err)
+
+		_, err = s.repo.RefundPurchase(context.Background(), &entity.Refund{
+			Username:   "user",
+			PurchaseID: purchase.ID,
+			Window:     time.Hour,
+		})
+		require.Equal(t, errs.RefundExpired, err)
+
+		inventory, err := s.repo.GetInventory(context.Background(), "user")
+		require.NoError(t, err)
+		require.Equal(t, []*entity.Item{{Name: itemName, Quantity: 1}}, inventory)
+	})
+
+	s.T().Run("чужая покупка", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+			s.deleteTestItem(t, itemName)
+		})
+		purchase := createUserWithPurchase(t)
+
+		_, err := s.repo.RefundPurchase(context.Background(), &entity.Refund{
+			Username:   "another",
+			PurchaseID: purchase.ID,
+			Window:     time.Hour,
+		})
+		require.Equal(t, errs.PurchaseNotFound, err)
+	})
+}
+
 func TestIItemRepoTestSuite(t *testing.T) {
 	suite.Run(t, new(IItemRepoSuite))
 }
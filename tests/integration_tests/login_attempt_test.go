@@ -0,0 +1,182 @@
+package integration_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ILoginAttemptRepoSuite struct {
+	suite.Suite
+	repo entity.ILoginAttemptRepository
+}
+
+func (s *ILoginAttemptRepoSuite) SetupSuite() {
+	s.repo = postgres.NewLoginAttemptRepository(testDbInstance)
+}
+
+func (s *ILoginAttemptRepoSuite) TearDownSubTest() {
+	query := `truncate table failed_logins`
+	_, err := testDbInstance.Exec(context.Background(), query)
+	require.NoError(s.T(), err)
+}
+
+func (s *ILoginAttemptRepoSuite) saveFailures(t *testing.T, attempts ...*entity.LoginAttempt) {
+	for _, attempt := range attempts {
+		_, err := s.repo.ReserveAttempt(context.Background(), attempt, attempt.Time)
+		require.NoError(t, err)
+	}
+}
+
+func (s *ILoginAttemptRepoSuite) Test_loginAttemptRepository_ReserveAttempt() {
+	now := time.Now().Truncate(time.Microsecond) // postgres timestamp precision
+
+	s.T().Run("подсчет по имени и ip", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.saveFailures(t,
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now.Add(-3 * time.Minute)},
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.2", Time: now.Add(-2 * time.Minute)},
+			&entity.LoginAttempt{Username: "other", IP: "10.0.0.1", Time: now.Add(-time.Minute)},
+			&entity.LoginAttempt{Username: "other", IP: "10.0.0.3", Time: now},
+			// outside of window
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now.Add(-time.Hour)},
+		)
+
+		attempt := &entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now}
+		failures, err := s.repo.ReserveAttempt(context.Background(), attempt, now.Add(-15*time.Minute))
+		require.NoError(t, err)
+		require.NotZero(t, attempt.ID)
+		require.Equal(t, 2, failures.UsernameCount)
+		require.True(t, now.Add(-2*time.Minute).Equal(failures.UsernameLastFailure))
+		require.Equal(t, 2, failures.IPCount)
+		require.True(t, now.Add(-time.Minute).Equal(failures.IPLastFailure))
+	})
+
+	s.T().Run("нет неудачных попыток", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		failures, err := s.repo.ReserveAttempt(context.Background(),
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now},
+			now.Add(-15*time.Minute),
+		)
+		require.NoError(t, err)
+		require.Equal(t, &entity.LoginFailures{}, failures)
+	})
+
+	s.T().Run("параллельные попытки видят друг друга", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		const attempts = 10
+
+		var wg sync.WaitGroup
+		counts := make(chan int, attempts)
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				failures, err := s.repo.ReserveAttempt(context.Background(),
+					&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now},
+					now.Add(-15*time.Minute),
+				)
+				require.NoError(t, err)
+				counts <- failures.UsernameCount
+			}()
+		}
+		wg.Wait()
+		close(counts)
+
+		seen := make([]int, 0, attempts)
+		for count := range counts {
+			seen = append(seen, count)
+		}
+		require.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, seen)
+	})
+}
+
+func (s *ILoginAttemptRepoSuite) Test_loginAttemptRepository_DeleteAttempt() {
+	now := time.Now().Truncate(time.Microsecond)
+
+	s.T().Run("удаление зарезервированной попытки", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		released := &entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now}
+		s.saveFailures(t,
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now.Add(-time.Minute)},
+			released,
+		)
+
+		err := s.repo.DeleteAttempt(context.Background(), released.ID)
+		require.NoError(t, err)
+
+		failures, err := s.repo.ReserveAttempt(context.Background(),
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now},
+			now.Add(-15*time.Minute),
+		)
+		require.NoError(t, err)
+		require.Equal(t, 1, failures.UsernameCount)
+		require.True(t, now.Add(-time.Minute).Equal(failures.UsernameLastFailure))
+	})
+}
+
+func (s *ILoginAttemptRepoSuite) Test_loginAttemptRepository_Clear() {
+	now := time.Now()
+
+	s.T().Run("очистка по имени", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.saveFailures(t,
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now},
+			&entity.LoginAttempt{Username: "other", IP: "10.0.0.1", Time: now},
+		)
+
+		err := s.repo.ClearUsernameFailures(context.Background(), "user")
+		require.NoError(t, err)
+
+		failures, err := s.repo.ReserveAttempt(context.Background(),
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now},
+			now.Add(-time.Minute),
+		)
+		require.NoError(t, err)
+		require.Equal(t, 0, failures.UsernameCount)
+		require.True(t, failures.UsernameLastFailure.IsZero())
+		// cleared failures still count for ip, so login of victim does not reset attacker ip
+		require.Equal(t, 2, failures.IPCount)
+	})
+
+	s.T().Run("удаление устаревших", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.saveFailures(t,
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now.Add(-time.Hour)},
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now},
+		)
+
+		err := s.repo.DeleteFailuresBefore(context.Background(), now.Add(-time.Minute))
+		require.NoError(t, err)
+
+		failures, err := s.repo.ReserveAttempt(context.Background(),
+			&entity.LoginAttempt{Username: "user", IP: "10.0.0.1", Time: now},
+			now.Add(-2*time.Hour),
+		)
+		require.NoError(t, err)
+		require.Equal(t, 1, failures.UsernameCount)
+	})
+}
+
+func TestILoginAttemptRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(ILoginAttemptRepoSuite))
+}
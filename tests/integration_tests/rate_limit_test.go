@@ -0,0 +1,113 @@
+package integration_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const concurrentRateLimitedRequests = 50
+
+type IRateLimitRepoSuite struct {
+	suite.Suite
+	repo entity.IRateLimitRepository
+}
+
+func (s *IRateLimitRepoSuite) SetupSuite() {
+	s.repo = postgres.NewRateLimitRepository(testDbInstance)
+}
+
+func (s *IRateLimitRepoSuite) TearDownSubTest() {
+	query := `truncate table rate_limits`
+	_, err := testDbInstance.Exec(context.Background(), query)
+	require.NoError(s.T(), err)
+}
+
+func (s *IRateLimitRepoSuite) Test_rateLimitRepository_Increment() {
+	window := time.Now().Truncate(time.Minute)
+
+	s.T().Run("подсчет в окне и сброс в новом окне", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		for i := 1; i <= 3; i++ {
+			count, err := s.repo.Increment(context.Background(), "user:user *", window)
+			require.NoError(t, err)
+			require.Equal(t, i, count)
+		}
+
+		count, err := s.repo.Increment(context.Background(), "user:other *", window)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		count, err = s.repo.Increment(context.Background(), "user:user *", window.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	// prefork processes share counters through database
+	s.T().Run("конкурентные запросы", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		var wg sync.WaitGroup
+		counts := make([]int, concurrentRateLimitedRequests)
+		for i := 0; i < concurrentRateLimitedRequests; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				count, err := s.repo.Increment(context.Background(), "ip:127.0.0.1 /api/auth", window)
+				require.NoError(t, err)
+				counts[i] = count
+			}(i)
+		}
+		wg.Wait()
+
+		sort.Ints(counts)
+		for i, count := range counts {
+			require.Equal(t, i+1, count)
+		}
+	})
+}
+
+func (s *IRateLimitRepoSuite) Test_rateLimitRepository_DeleteWindowsBefore() {
+	window := time.Now().Truncate(time.Minute)
+
+	s.T().Run("удаление устаревших окон", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		_, err := s.repo.Increment(context.Background(), "user:user *", window.Add(-time.Hour))
+		require.NoError(t, err)
+		_, err = s.repo.Increment(context.Background(), "user:other *", window)
+		require.NoError(t, err)
+
+		err = s.repo.DeleteWindowsBefore(context.Background(), window)
+		require.NoError(t, err)
+
+		var keys []string
+		rows, err := testDbInstance.Query(context.Background(), `select key from rate_limits`)
+		require.NoError(t, err)
+		defer rows.Close()
+		for rows.Next() {
+			var key string
+			require.NoError(t, rows.Scan(&key))
+			keys = append(keys, key)
+		}
+		require.NoError(t, rows.Err())
+		require.Equal(t, []string{"user:other *"}, keys)
+	})
+}
+
+func TestIRateLimitRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(IRateLimitRepoSuite))
+}
@@ -0,0 +1,262 @@
+package integration_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ITokenRepoSuite struct {
+	suite.Suite
+	repo    entity.ITokenRepository
+	builder squirrel.StatementBuilderType
+}
+
+func (s *ITokenRepoSuite) SetupSuite() {
+	s.repo = postgres.NewTokenRepository(testDbInstance)
+	s.builder = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+}
+
+func (s *ITokenRepoSuite) TearDownSubTest() {
+	query := `truncate table users cascade`
+	_, err := testDbInstance.Exec(context.Background(), query)
+	require.NoError(s.T(), err)
+}
+
+func (s *ITokenRepoSuite) createUser(t *testing.T, username string) {
+	query, args, err := s.builder.
+		Insert("users").
+		Columns("username", "password").
+		Values(username, "hashedPass").
+		ToSql()
+	require.NoError(t, err)
+
+	_, err = testDbInstance.Exec(
+		context.Background(),
+		query,
+		args...,
+	)
+	require.NoError(t, err)
+}
+
+func (s *ITokenRepoSuite) Test_tokenRepository_RotateRefreshToken() {
+	expiresAt := time.Now().Add(time.Hour)
+
+	s.T().Run("ротация токена", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUser(t, "user")
+
+		err := s.repo.SaveRefreshToken(context.Background(), &entity.RefreshToken{
+			Username:  "user",
+			TokenHash: "first",
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+
+		user, err := s.repo.RotateRefreshToken(context.Background(), "first", &entity.RefreshToken{
+			TokenHash: "second",
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &entity.Auth{Username: "user", Role: entity.RoleUser}, user)
+
+		user, err = s.repo.RotateRefreshToken(context.Background(), "second", &entity.RefreshToken{
+			TokenHash: "third",
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "user", user.Username)
+	})
+
+	s.T().Run("повторное использование отзывает все токены пользователя", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUser(t, "user")
+
+		err := s.repo.SaveRefreshToken(context.Background(), &entity.RefreshToken{
+			Username:  "user",
+			TokenHash: "first",
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+
+		_, err = s.repo.RotateRefreshToken(context.Background(), "first", &entity.RefreshToken{
+			TokenHash: "second",
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+
+		_, err = s.repo.RotateRefreshToken(context.Background(), "first", &entity.RefreshToken{
+			TokenHash: "stolen",
+			ExpiresAt: expiresAt,
+		})
+		require.Equal(t, errs.InvalidToken, err)
+
+		_, err = s.repo.RotateRefreshToken(context.Background(), "second", &entity.RefreshToken{
+			TokenHash: "third",
+			ExpiresAt: expiresAt,
+		})
+		require.Equal(t, errs.InvalidToken, err)
+	})
+
+	s.T().Run("истекший и неизвестный токены", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUser(t, "user")
+
+		err := s.repo.SaveRefreshToken(context.Background(), &entity.RefreshToken{
+			Username:  "user",
+			TokenHash: "expired",
+			ExpiresAt: time.Now().Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		_, err = s.repo.RotateRefreshToken(context.Background(), "expired", &entity.RefreshToken{
+			TokenHash: "new",
+			ExpiresAt: expiresAt,
+		})
+		require.Equal(t, errs.InvalidToken, err)
+
+		_, err = s.repo.RotateRefreshToken(context.Background(), "unknown", &entity.RefreshToken{
+			TokenHash: "new",
+			ExpiresAt: expiresAt,
+		})
+		require.Equal(t, errs.InvalidToken, err)
+	})
+
+	s.T().Run("отзыв refresh токена", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUser(t, "user")
+
+		err := s.repo.SaveRefreshToken(context.Background(), &entity.RefreshToken{
+			Username:  "user",
+			TokenHash: "first",
+			ExpiresAt: expiresAt,
+		})
+		require.NoError(t, err)
+
+		err = s.repo.RevokeRefreshToken(context.Background(), "user", "first")
+		require.NoError(t, err)
+
+		_, err = s.repo.RotateRefreshToken(context.Background(), "first", &entity.RefreshToken{
+			TokenHash: "second",
+			ExpiresAt: expiresAt,
+		})
+		require.Equal(t, errs.InvalidToken, err)
+	})
+}
+
+func (s *ITokenRepoSuite) Test_tokenRepository_RevokeAccessToken() {
+	s.T().Run("отзыв токена доступа", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUser(t, "user")
+
+		accessToken := &entity.AccessToken{
+			JTI:      "jti",
+			Username: "user",
+			IssuedAt: time.Now(),
+		}
+		revoked, err := s.repo.IsAccessTokenRevoked(context.Background(), accessToken)
+		require.NoError(t, err)
+		require.False(t, revoked)
+
+		token := &entity.RevokedToken{
+			JTI:       "jti",
+			Username:  "user",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+		err = s.repo.RevokeAccessToken(context.Background(), token)
+		require.NoError(t, err)
+		// repeated logout with the same token is not an error
+		err = s.repo.RevokeAccessToken(context.Background(), token)
+		require.NoError(t, err)
+
+		revoked, err = s.repo.IsAccessTokenRevoked(context.Background(), accessToken)
+		require.NoError(t, err)
+		require.True(t, revoked)
+	})
+
+	s.T().Run("токен выдан до смены пароля", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUser(t, "user")
+
+		query, args, err := s.builder.
+			Update("users").
+			Set("tokens_valid_after", squirrel.Expr("current_timestamp")).
+			Where(squirrel.Eq{"username": "user"}).
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(context.Background(), query, args...)
+		require.NoError(t, err)
+
+		revoked, err := s.repo.IsAccessTokenRevoked(context.Background(), &entity.AccessToken{
+			JTI:      "old",
+			Username: "user",
+			IssuedAt: time.Now().Add(-time.Hour),
+		})
+		require.NoError(t, err)
+		require.True(t, revoked)
+
+		revoked, err = s.repo.IsAccessTokenRevoked(context.Background(), &entity.AccessToken{
+			JTI:      "new",
+			Username: "user",
+			IssuedAt: time.Now().Add(time.Minute),
+		})
+		require.NoError(t, err)
+		require.False(t, revoked)
+	})
+
+	s.T().Run("токен выдан в ту же секунду до смены пароля", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+		s.createUser(t, "user")
+
+		validAfter := time.Now().Truncate(time.Second).Add(500 * time.Millisecond)
+		query, args, err := s.builder.
+			Update("users").
+			Set("tokens_valid_after", validAfter).
+			Where(squirrel.Eq{"username": "user"}).
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(context.Background(), query, args...)
+		require.NoError(t, err)
+
+		revoked, err := s.repo.IsAccessTokenRevoked(context.Background(), &entity.AccessToken{
+			JTI:      "old",
+			Username: "user",
+			IssuedAt: validAfter.Add(-time.Millisecond),
+		})
+		require.NoError(t, err)
+		require.True(t, revoked)
+
+		revoked, err = s.repo.IsAccessTokenRevoked(context.Background(), &entity.AccessToken{
+			JTI:      "new",
+			Username: "user",
+			IssuedAt: validAfter.Add(time.Millisecond),
+		})
+		require.NoError(t, err)
+		require.False(t, revoked)
+	})
+}
+
+func TestITokenRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(ITokenRepoSuite))
+}
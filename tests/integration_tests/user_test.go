@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/stretchr/testify/require"
@@ -43,7 +44,7 @@ func (s *IUserRepoSuite) Test_userRepository_GetCoinsHistory() {
 			name:     "успешное получение истории транзакций",
 			username: "user",
 			coinsHistory: &entity.CoinsHistory{
-				Received: []*entity.User{
+				Received: []*entity.CoinsHistoryEntry{
 					{
 						Username: "first",
 						Coins:    100,
@@ -53,7 +54,7 @@ func (s *IUserRepoSuite) Test_userRepository_GetCoinsHistory() {
 						Coins:    200,
 					},
 				},
-				Sent: []*entity.User{
+				Sent: []*entity.CoinsHistoryEntry{
 					{
 						Username: "first",
 						Coins:    100,
@@ -104,8 +105,8 @@ func (s *IUserRepoSuite) Test_userRepository_GetCoinsHistory() {
 			name:     "успешное получение пустой истории транзакций",
 			username: "user",
 			coinsHistory: &entity.CoinsHistory{
-				Received: []*entity.User{},
-				Sent:     []*entity.User{},
+				Received: []*entity.CoinsHistoryEntry{},
+				Sent:     []*entity.CoinsHistoryEntry{},
 			},
 			beforeTest: func(t *testing.T, username string, history *entity.CoinsHistory) {
 				query, args, err := s.builder.
@@ -428,6 +429,195 @@ func (s *IUserRepoSuite) Test_userRepository_SendCoins() {
 	}
 }
 
+func (s *IUserRepoSuite) Test_userRepository_SendCoins_Comment() {
+	s.T().Run("комментарий возвращается отправителю и получателю", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("first", "hashedPass").
+			Values("second", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(context.Background(), query, args...)
+		require.NoError(t, err)
+
+		err = s.repo.SendCoins(context.Background(), &entity.TransferCoins{
+			FromUser: "first",
+			ToUser:   "second",
+			Amount:   10,
+			Comment:  "за пиццу",
+		})
+		require.NoError(t, err)
+
+		_, senderHistory, err := s.repo.GetCoinsHistory(context.Background(), "first")
+		require.NoError(t, err)
+		require.Equal(t, []*entity.CoinsHistoryEntry{
+			{Username: "second", Coins: 10, Comment: "за пиццу"},
+		}, senderHistory.Sent)
+
+		_, receiverHistory, err := s.repo.GetCoinsHistory(context.Background(), "second")
+		require.NoError(t, err)
+		require.Equal(t, []*entity.CoinsHistoryEntry{
+			{Username: "first", Coins: 10, Comment: "за пиццу"},
+		}, receiverHistory.Received)
+
+		transactions, err := s.repo.GetTransactions(context.Background(), &entity.TransactionsFilter{
+			Username: "second",
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		require.Equal(t, "за пиццу", transactions[0].Comment)
+	})
+}
+
+func (s *IUserRepoSuite) Test_userRepository_GrantCoins() {
+	s.T().Run("начисление попадает в баланс и историю", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("user", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(context.Background(), query, args...)
+		require.NoError(t, err)
+
+		err = s.repo.GrantCoins(context.Background(), &entity.GrantCoins{
+			Username: "user",
+			Amount:   50,
+			Comment:  "бонус",
+		})
+		require.NoError(t, err)
+
+		coins, history, err := s.repo.GetCoinsHistory(context.Background(), "user")
+		require.NoError(t, err)
+		require.Equal(t, int32(1050), coins)
+		require.Equal(t, []*entity.CoinsHistoryEntry{
+			{Username: "", Coins: 50, Comment: "бонус"},
+		}, history.Received)
+
+		transactions, err := s.repo.GetTransactions(context.Background(), &entity.TransactionsFilter{
+			Username: "user",
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		require.Empty(t, transactions[0].FromUser)
+		require.Equal(t, "user", transactions[0].ToUser)
+	})
+
+	s.T().Run("пользователь не найден", func(t *testing.T) {
+		err := s.repo.GrantCoins(context.Background(), &entity.GrantCoins{
+			Username: "unknown",
+			Amount:   50,
+		})
+		require.ErrorIs(t, err, errs.UserNotFound)
+	})
+}
+
+func (s *IUserRepoSuite) Test_userRepository_GetTransactions() {
+	base := time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)
+	history := []struct {
+		fromUser string
+		toUser   string
+		amount   int32
+		time     time.Time
+	}{
+		{"user", "first", 10, base},
+		{"first", "user", 20, base.Add(time.Hour)},
+		{"user", "second", 30, base.Add(2 * time.Hour)},
+		{"second", "first", 40, base.Add(3 * time.Hour)},
+	}
+
+	s.T().Run("фильтры и постраничное получение", func(t *testing.T) {
+		t.Cleanup(func() {
+			s.TearDownSubTest()
+		})
+
+		query, args, err := s.builder.
+			Insert("users").
+			Columns("username", "password").
+			Values("user", "hashedPass").
+			Values("first", "hashedPass").
+			Values("second", "hashedPass").
+			ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(context.Background(), query, args...)
+		require.NoError(t, err)
+
+		insertingTransactions := s.builder.
+			Insert("transactions").
+			Columns("fromUser", "toUser", "coins", "time")
+		for _, tr := range history {
+			insertingTransactions = insertingTransactions.Values(tr.fromUser, tr.toUser, tr.amount, tr.time)
+		}
+		query, args, err = insertingTransactions.ToSql()
+		require.NoError(t, err)
+		_, err = testDbInstance.Exec(context.Background(), query, args...)
+		require.NoError(t, err)
+
+		firstPage, err := s.repo.GetTransactions(context.Background(), &entity.TransactionsFilter{
+			Username: "user",
+			Limit:    2,
+		})
+		require.NoError(t, err)
+		require.Len(t, firstPage, 2)
+		require.Equal(t, int32(30), firstPage[0].Amount)
+		require.Equal(t, int32(20), firstPage[1].Amount)
+		require.True(t, firstPage[0].Time.Equal(base.Add(2*time.Hour)))
+		require.NotEmpty(t, firstPage[0].ID)
+
+		secondPage, err := s.repo.GetTransactions(context.Background(), &entity.TransactionsFilter{
+			Username: "user",
+			After: &entity.TransactionsCursor{
+				Time: firstPage[1].Time,
+				ID:   firstPage[1].ID,
+			},
+			Limit: 2,
+		})
+		require.NoError(t, err)
+		require.Len(t, secondPage, 1)
+		require.Equal(t, int32(10), secondPage[0].Amount)
+
+		received, err := s.repo.GetTransactions(context.Background(), &entity.TransactionsFilter{
+			Username:  "user",
+			Direction: entity.DirectionReceived,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.Len(t, received, 1)
+		require.Equal(t, "first", received[0].FromUser)
+
+		sent, err := s.repo.GetTransactions(context.Background(), &entity.TransactionsFilter{
+			Username:  "user",
+			Direction: entity.DirectionSent,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.Len(t, sent, 2)
+
+		from := base.Add(30 * time.Minute)
+		to := base.Add(2 * time.Hour)
+		inRange, err := s.repo.GetTransactions(context.Background(), &entity.TransactionsFilter{
+			Username: "user",
+			From:     &from,
+			To:       &to,
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Len(t, inRange, 1)
+		require.Equal(t, int32(20), inRange[0].Amount)
+	})
+}
+
 func TestIUserRepoTestSuite(t *testing.T) {
 	suite.Run(t, new(IUserRepoSuite))
 }
@@ -4,19 +4,12 @@ import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
 	"context"
-	"errors"
 	"fmt"
 	"log"
-	"path/filepath"
-	"runtime"
 	"strconv"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jackc/pgx/v5/pgxpool"
-	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
@@ -47,7 +40,7 @@ func SetupTestDatabase() *TestDatabase {
 		log.Fatal("failed to setup test: ", err)
 	}
 
-	err = migrateDb(dbAddr)
+	err = migrateDb(dbInstance)
 	if err != nil {
 		log.Fatal("failed to perform db migration: ", err)
 	}
@@ -112,31 +105,13 @@ func createContainer(ctx context.Context) (testcontainers.Container, *pgxpool.Po
 		fmt.Sprintf("%s:%d", postgresConfig.Host, postgresConfig.Port), nil
 }
 
-func migrateDb(dbAddr string) error {
-	_, path, _, ok := runtime.Caller(0)
-	if !ok {
-		return fmt.Errorf("failed to get path")
-	}
-	_ = path
-	migrationFilesPath, err := filepath.Glob(filepath.Join(filepath.Dir(path), "..", "..", "migrations"))
+func migrateDb(db *pgxpool.Pool) error {
+	migrator, err := postgres.NewMigrator(db)
 	if err != nil {
 		return err
 	}
-	if len(migrationFilesPath) == 0 {
-		return fmt.Errorf("migration file not found")
-	}
-	databaseURL := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable",
-		postgresConfig.User, postgresConfig.Password, dbAddr, postgresConfig.DBName)
-
-	m, err := migrate.New(fmt.Sprintf("file:%s", migrationFilesPath[0]), databaseURL)
-	if err != nil {
-		return err
-	}
-	defer m.Close()
-
-	err = m.Up()
-	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return err
-	}
-	return nil
+	defer func() {
+		_ = migrator.Close()
+	}()
+	return migrator.Up()
 }
@@ -6,23 +6,47 @@ import (
 	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
 	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
+func refreshTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
 func TestAuthService_Auth(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	logger := mocks.NewMockLogger()
 	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
 	hasher := mocks.NewMockIHashCrypto(ctrl)
 	tokenManager := mocks.NewMockITokenManager(ctrl)
 
-	svc := service.NewAuthService(repo, logger, hasher, tokenManager)
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, true)
+
+	expiresAt := time.Now().Add(time.Hour)
+	expectRefreshToken := func(username string) {
+		tokenManager.EXPECT().
+			CreateRefreshToken().
+			Return("refresh", expiresAt, nil)
+
+		tokenRepo.EXPECT().
+			SaveRefreshToken(context.Background(), &entity.RefreshToken{
+				Username:  username,
+				TokenHash: refreshTokenHash("refresh"),
+				ExpiresAt: expiresAt,
+			}).
+			Return(nil)
+	}
 
 	tests := []struct {
 		name        string
@@ -46,18 +70,184 @@ func TestAuthService_Auth(t *testing.T) {
 					Return(&entity.Auth{
 						Username: "username",
 						Password: "hashedPass",
+						Role:     entity.RoleUser,
 					}, nil)
 
 				hasher.EXPECT().
 					VerifyPassword("pass", "hashedPass").
 					Return(true)
 
+				hasher.EXPECT().
+					NeedsRehash("hashedPass").
+					Return(false)
+
 				tokenManager.EXPECT().
-					CreateToken("username").
+					CreateToken("username", entity.RoleUser).
 					Return("token", nil)
+
+				expectRefreshToken("username")
 			},
 			wantErr: false,
 		}, // успешная аутентификация
+		{
+			name: "успешная аутентификация администратора",
+			authInfo: &entity.Auth{
+				Username: "admin",
+				Password: "pass",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(
+						context.Background(),
+						"admin",
+					).
+					Return(&entity.Auth{
+						Username: "admin",
+						Password: "hashedPass",
+						Role:     entity.RoleAdmin,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("pass", "hashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					NeedsRehash("hashedPass").
+					Return(false)
+
+				tokenManager.EXPECT().
+					CreateToken("admin", entity.RoleAdmin).
+					Return("token", nil)
+
+				expectRefreshToken("admin")
+			},
+			wantErr: false,
+		}, // успешная аутентификация администратора
+		{
+			name: "перехеширование пароля с устаревшими параметрами",
+			authInfo: &entity.Auth{
+				Username: "username",
+				Password: "pass",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(
+						context.Background(),
+						"username",
+					).
+					Return(&entity.Auth{
+						Username: "username",
+						Password: "oldHashedPass",
+						Role:     entity.RoleUser,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("pass", "oldHashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					NeedsRehash("oldHashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					HashPassword("pass").
+					Return("newHashedPass", nil)
+
+				authRepo.EXPECT().
+					UpdatePasswordHash(context.Background(), &entity.Auth{
+						Username: "username",
+						Password: "newHashedPass",
+					}, "oldHashedPass").
+					Return(nil)
+
+				tokenManager.EXPECT().
+					CreateToken("username", entity.RoleUser).
+					Return("token", nil)
+
+				expectRefreshToken("username")
+			},
+			wantErr: false,
+		}, // перехеширование пароля с устаревшими параметрами
+		{
+			name: "hasher rehash internal error (login not failed)",
+			authInfo: &entity.Auth{
+				Username: "username",
+				Password: "pass",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(
+						context.Background(),
+						"username",
+					).
+					Return(&entity.Auth{
+						Username: "username",
+						Password: "oldHashedPass",
+						Role:     entity.RoleUser,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("pass", "oldHashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					NeedsRehash("oldHashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					HashPassword("pass").
+					Return("", fmt.Errorf("hasher internal error"))
+
+				tokenManager.EXPECT().
+					CreateToken("username", entity.RoleUser).
+					Return("token", nil)
+
+				expectRefreshToken("username")
+			},
+			wantErr: false,
+		}, // hasher rehash internal error (login not failed)
+		{
+			name: "repo update hash internal error (login not failed)",
+			authInfo: &entity.Auth{
+				Username: "username",
+				Password: "pass",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(
+						context.Background(),
+						"username",
+					).
+					Return(&entity.Auth{
+						Username: "username",
+						Password: "oldHashedPass",
+						Role:     entity.RoleUser,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("pass", "oldHashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					NeedsRehash("oldHashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					HashPassword("pass").
+					Return("newHashedPass", nil)
+
+				authRepo.EXPECT().
+					UpdatePasswordHash(context.Background(), gomock.Any(), "oldHashedPass").
+					Return(fmt.Errorf("db internal error"))
+
+				tokenManager.EXPECT().
+					CreateToken("username", entity.RoleUser).
+					Return("token", nil)
+
+				expectRefreshToken("username")
+			},
+			wantErr: false,
+		}, // repo update hash internal error (login not failed)
 		{
 			name: "успешная регистрация",
 			authInfo: &entity.Auth{
@@ -89,8 +279,10 @@ func TestAuthService_Auth(t *testing.T) {
 					Return(nil)
 
 				tokenManager.EXPECT().
-					CreateToken("new").
+					CreateToken("new", entity.RoleUser).
 					Return("token", nil)
+
+				expectRefreshToken("new")
 			},
 			wantErr: false,
 		}, // успешная регистрация
@@ -150,6 +342,7 @@ func TestAuthService_Auth(t *testing.T) {
 					Return(&entity.Auth{
 						Username: "username",
 						Password: "hashedPass",
+						Role:     entity.RoleUser,
 					}, nil)
 
 				hasher.EXPECT().
@@ -228,19 +421,922 @@ func TestAuthService_Auth(t *testing.T) {
 					Return(&entity.Auth{
 						Username: "username",
 						Password: "hashedPass",
+						Role:     entity.RoleUser,
 					}, nil)
 
 				hasher.EXPECT().
 					VerifyPassword("pass", "hashedPass").
 					Return(true)
 
+				hasher.EXPECT().
+					NeedsRehash("hashedPass").
+					Return(false)
+
 				tokenManager.EXPECT().
-					CreateToken("username").
+					CreateToken("username", entity.RoleUser).
 					Return("", fmt.Errorf("creating token error"))
 			},
 			wantErr:     true,
 			requiredErr: errs.InternalError,
-		}, // успешная аутентификация
+		}, // ошибка получения токена
+		{
+			name: "ошибка сохранения refresh токена",
+			authInfo: &entity.Auth{
+				Username: "username",
+				Password: "pass",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(
+						context.Background(),
+						"username",
+					).
+					Return(&entity.Auth{
+						Username: "username",
+						Password: "hashedPass",
+						Role:     entity.RoleUser,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("pass", "hashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					NeedsRehash("hashedPass").
+					Return(false)
+
+				tokenManager.EXPECT().
+					CreateToken("username", entity.RoleUser).
+					Return("token", nil)
+
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("refresh", expiresAt, nil)
+
+				tokenRepo.EXPECT().
+					SaveRefreshToken(context.Background(), gomock.Any()).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // ошибка сохранения refresh токена
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo, *hasher)
+			}
+
+			tokens, err := svc.Auth(context.Background(), tt.authInfo)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, &entity.TokenPair{AccessToken: "token", RefreshToken: "refresh"}, tokens)
+			}
+		})
+	}
+}
+
+func TestAuthService_Auth_AutoRegisterDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
+	hasher := mocks.NewMockIHashCrypto(ctrl)
+	tokenManager := mocks.NewMockITokenManager(ctrl)
+
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, false)
+
+	// dummy hash is created once and verified for every unknown user
+	hasher.EXPECT().
+		HashPassword(gomock.Any()).
+		Return("dummyHash", nil)
+	for _, username := range []string{"typo", "other"} {
+		repo.EXPECT().
+			GetByUsername(context.Background(), username).
+			Return(nil, nil)
+		hasher.EXPECT().
+			VerifyPassword("pass", "dummyHash").
+			Return(false)
+
+		tokens, err := svc.Auth(context.Background(), &entity.Auth{
+			Username: username,
+			Password: "pass",
+		})
+		require.Equal(t, errs.InvalidCredentials, err)
+		require.Nil(t, tokens)
+	}
+}
+
+func TestAuthService_Register(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
+	hasher := mocks.NewMockIHashCrypto(ctrl)
+	tokenManager := mocks.NewMockITokenManager(ctrl)
+
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, false)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		authInfo    *entity.Auth
+		beforeTest  func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешная регистрация",
+			authInfo: &entity.Auth{
+				Username: "new_user.1",
+				Password: "password1",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				hasher.EXPECT().
+					HashPassword("password1").
+					Return("hashedPass", nil)
+
+				authRepo.EXPECT().
+					Register(context.Background(), &entity.Auth{
+						Username: "new_user.1",
+						Password: "hashedPass",
+					}).
+					Return(nil)
+
+				tokenManager.EXPECT().
+					CreateToken("new_user.1", entity.RoleUser).
+					Return("token", nil)
+
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("refresh", expiresAt, nil)
+
+				tokenRepo.EXPECT().
+					SaveRefreshToken(context.Background(), &entity.RefreshToken{
+						Username:  "new_user.1",
+						TokenHash: refreshTokenHash("refresh"),
+						ExpiresAt: expiresAt,
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешная регистрация
+		{
+			name: "пользователь уже существует",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "password1",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				hasher.EXPECT().
+					HashPassword("password1").
+					Return("hashedPass", nil)
+
+				authRepo.EXPECT().
+					Register(context.Background(), gomock.Any()).
+					Return(errs.UserAlreadyExists)
+			},
+			wantErr:     true,
+			requiredErr: errs.UserAlreadyExists,
+		}, // пользователь уже существует
+		{
+			name: "repo register internal error",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "password1",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				hasher.EXPECT().
+					HashPassword("password1").
+					Return("hashedPass", nil)
+
+				authRepo.EXPECT().
+					Register(context.Background(), gomock.Any()).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo register internal error
+		{
+			name: "недопустимые символы в имени",
+			authInfo: &entity.Auth{
+				Username: "пользователь",
+				Password: "password1",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // недопустимые символы в имени
+		{
+			name: "слишком длинное имя",
+			authInfo: &entity.Auth{
+				Username: "abcdefghijklmnopqrstuvwxyz0123456",
+				Password: "password1",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком длинное имя
+		{
+			name: "короткий пароль",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "pass1",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // короткий пароль
+		{
+			name: "пароль без цифр",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "password",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пароль без цифр
+		{
+			name: "пароль без букв",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "12345678",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пароль без букв
+		{
+			name:        "nil",
+			authInfo:    nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo, *hasher)
+			}
+
+			tokens, err := svc.Register(context.Background(), tt.authInfo)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, tokens)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, &entity.TokenPair{AccessToken: "token", RefreshToken: "refresh"}, tokens)
+			}
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
+	hasher := mocks.NewMockIHashCrypto(ctrl)
+	tokenManager := mocks.NewMockITokenManager(ctrl)
+
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, true)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		beforeTest   func(tokenRepo mocks.MockITokenRepository, tokenManager mocks.MockITokenManager)
+		tokens       *entity.TokenPair
+		wantErr      bool
+		requiredErr  error
+	}{
+		{
+			name:         "успешное обновление токенов",
+			refreshToken: "old",
+			beforeTest: func(tokenRepo mocks.MockITokenRepository, tokenManager mocks.MockITokenManager) {
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("new", expiresAt, nil)
+
+				tokenRepo.EXPECT().
+					RotateRefreshToken(context.Background(), refreshTokenHash("old"), &entity.RefreshToken{
+						TokenHash: refreshTokenHash("new"),
+						ExpiresAt: expiresAt,
+					}).
+					Return(&entity.Auth{Username: "user", Role: entity.RoleUser}, nil)
+
+				tokenManager.EXPECT().
+					CreateToken("user", entity.RoleUser).
+					Return("token", nil)
+			},
+			tokens: &entity.TokenPair{
+				AccessToken:  "token",
+				RefreshToken: "new",
+			},
+			wantErr: false,
+		}, // успешное обновление токенов
+		{
+			name:         "недействительный refresh токен",
+			refreshToken: "old",
+			beforeTest: func(tokenRepo mocks.MockITokenRepository, tokenManager mocks.MockITokenManager) {
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("new", expiresAt, nil)
+
+				tokenRepo.EXPECT().
+					RotateRefreshToken(context.Background(), refreshTokenHash("old"), gomock.Any()).
+					Return(nil, errs.InvalidToken)
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidToken,
+		}, // недействительный refresh токен
+		{
+			name:         "repo rotate refresh token error",
+			refreshToken: "old",
+			beforeTest: func(tokenRepo mocks.MockITokenRepository, tokenManager mocks.MockITokenManager) {
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("new", expiresAt, nil)
+
+				tokenRepo.EXPECT().
+					RotateRefreshToken(context.Background(), refreshTokenHash("old"), gomock.Any()).
+					Return(nil, fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo rotate refresh token error
+		{
+			name:         "ошибка создания refresh токена",
+			refreshToken: "old",
+			beforeTest: func(tokenRepo mocks.MockITokenRepository, tokenManager mocks.MockITokenManager) {
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("", time.Time{}, fmt.Errorf("rand error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // ошибка создания refresh токена
+		{
+			name:         "ошибка создания токена доступа",
+			refreshToken: "old",
+			beforeTest: func(tokenRepo mocks.MockITokenRepository, tokenManager mocks.MockITokenManager) {
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("new", expiresAt, nil)
+
+				tokenRepo.EXPECT().
+					RotateRefreshToken(context.Background(), refreshTokenHash("old"), gomock.Any()).
+					Return(&entity.Auth{Username: "user", Role: entity.RoleUser}, nil)
+
+				tokenManager.EXPECT().
+					CreateToken("user", entity.RoleUser).
+					Return("", fmt.Errorf("creating token error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // ошибка создания токена доступа
+		{
+			name:         "пустой refresh токен",
+			refreshToken: "",
+			wantErr:      true,
+			requiredErr:  errs.InvalidData,
+		}, // пустой refresh токен
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*tokenRepo, *tokenManager)
+			}
+
+			tokens, err := svc.Refresh(context.Background(), tt.refreshToken)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, tokens)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.tokens, tokens)
+			}
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
+	hasher := mocks.NewMockIHashCrypto(ctrl)
+	tokenManager := mocks.NewMockITokenManager(ctrl)
+
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, true)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		logout      *entity.Logout
+		beforeTest  func(tokenRepo mocks.MockITokenRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешный выход с refresh токеном",
+			logout: &entity.Logout{
+				Username:     "user",
+				JTI:          "jti",
+				ExpiresAt:    expiresAt,
+				RefreshToken: "refresh",
+			},
+			beforeTest: func(tokenRepo mocks.MockITokenRepository) {
+				tokenRepo.EXPECT().
+					RevokeAccessToken(context.Background(), &entity.RevokedToken{
+						JTI:       "jti",
+						Username:  "user",
+						ExpiresAt: expiresAt,
+					}).
+					Return(nil)
+
+				tokenRepo.EXPECT().
+					RevokeRefreshToken(context.Background(), "user", refreshTokenHash("refresh")).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешный выход с refresh токеном
+		{
+			name: "успешный выход без refresh токена",
+			logout: &entity.Logout{
+				Username:  "user",
+				JTI:       "jti",
+				ExpiresAt: expiresAt,
+			},
+			beforeTest: func(tokenRepo mocks.MockITokenRepository) {
+				tokenRepo.EXPECT().
+					RevokeAccessToken(context.Background(), &entity.RevokedToken{
+						JTI:       "jti",
+						Username:  "user",
+						ExpiresAt: expiresAt,
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешный выход без refresh токена
+		{
+			name: "repo revoke access token error",
+			logout: &entity.Logout{
+				Username:  "user",
+				JTI:       "jti",
+				ExpiresAt: expiresAt,
+			},
+			beforeTest: func(tokenRepo mocks.MockITokenRepository) {
+				tokenRepo.EXPECT().
+					RevokeAccessToken(context.Background(), gomock.Any()).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo revoke access token error
+		{
+			name: "repo revoke refresh token error",
+			logout: &entity.Logout{
+				Username:     "user",
+				JTI:          "jti",
+				ExpiresAt:    expiresAt,
+				RefreshToken: "refresh",
+			},
+			beforeTest: func(tokenRepo mocks.MockITokenRepository) {
+				tokenRepo.EXPECT().
+					RevokeAccessToken(context.Background(), gomock.Any()).
+					Return(nil)
+
+				tokenRepo.EXPECT().
+					RevokeRefreshToken(context.Background(), "user", refreshTokenHash("refresh")).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo revoke refresh token error
+		{
+			name: "пустой идентификатор токена",
+			logout: &entity.Logout{
+				Username: "user",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустой идентификатор токена
+		{
+			name:        "nil",
+			logout:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*tokenRepo)
+			}
+
+			err := svc.Logout(context.Background(), tt.logout)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthService_IsTokenRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
+	hasher := mocks.NewMockIHashCrypto(ctrl)
+	tokenManager := mocks.NewMockITokenManager(ctrl)
+
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, true)
+
+	revokedToken := &entity.AccessToken{JTI: "revoked", Username: "user", IssuedAt: time.Now()}
+	tokenRepo.EXPECT().
+		IsAccessTokenRevoked(context.Background(), revokedToken).
+		Return(true, nil)
+	revoked, err := svc.IsTokenRevoked(context.Background(), revokedToken)
+	require.NoError(t, err)
+	require.True(t, revoked)
+
+	token := &entity.AccessToken{JTI: "jti", Username: "user", IssuedAt: time.Now()}
+	tokenRepo.EXPECT().
+		IsAccessTokenRevoked(context.Background(), token).
+		Return(false, fmt.Errorf("db internal error"))
+	_, err = svc.IsTokenRevoked(context.Background(), token)
+	require.Equal(t, errs.InternalError, err)
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
+	hasher := mocks.NewMockIHashCrypto(ctrl)
+	tokenManager := mocks.NewMockITokenManager(ctrl)
+
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, false)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		change      *entity.PasswordChange
+		beforeTest  func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешная смена пароля",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "password1",
+				NewPassword: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(context.Background(), "user").
+					Return(&entity.Auth{
+						Username: "user",
+						Password: "hashedPass",
+						Role:     entity.RoleAdmin,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("password1", "hashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					HashPassword("password2").
+					Return("newHashedPass", nil)
+
+				authRepo.EXPECT().
+					UpdatePassword(context.Background(), &entity.Auth{
+						Username: "user",
+						Password: "newHashedPass",
+					}).
+					Return(nil)
+
+				tokenManager.EXPECT().
+					CreateToken("user", entity.RoleAdmin).
+					Return("token", nil)
+
+				tokenManager.EXPECT().
+					CreateRefreshToken().
+					Return("refresh", expiresAt, nil)
+
+				tokenRepo.EXPECT().
+					SaveRefreshToken(context.Background(), &entity.RefreshToken{
+						Username:  "user",
+						TokenHash: refreshTokenHash("refresh"),
+						ExpiresAt: expiresAt,
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешная смена пароля
+		{
+			name: "неверный старый пароль",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "wrongPass1",
+				NewPassword: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(context.Background(), "user").
+					Return(&entity.Auth{
+						Username: "user",
+						Password: "hashedPass",
+						Role:     entity.RoleUser,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("wrongPass1", "hashedPass").
+					Return(false)
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidCredentials,
+		}, // неверный старый пароль
+		{
+			name: "пользователь не найден",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "password1",
+				NewPassword: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(context.Background(), "user").
+					Return(nil, nil)
+			},
+			wantErr:     true,
+			requiredErr: errs.UserNotFound,
+		}, // пользователь не найден
+		{
+			name: "repo get user internal error",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "password1",
+				NewPassword: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(context.Background(), "user").
+					Return(nil, fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo get user internal error
+		{
+			name: "hasher internal error",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "password1",
+				NewPassword: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(context.Background(), "user").
+					Return(&entity.Auth{
+						Username: "user",
+						Password: "hashedPass",
+						Role:     entity.RoleUser,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("password1", "hashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					HashPassword("password2").
+					Return("", fmt.Errorf("hasher internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // hasher internal error
+		{
+			name: "repo update password internal error",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "password1",
+				NewPassword: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				authRepo.EXPECT().
+					GetByUsername(context.Background(), "user").
+					Return(&entity.Auth{
+						Username: "user",
+						Password: "hashedPass",
+						Role:     entity.RoleUser,
+					}, nil)
+
+				hasher.EXPECT().
+					VerifyPassword("password1", "hashedPass").
+					Return(true)
+
+				hasher.EXPECT().
+					HashPassword("password2").
+					Return("newHashedPass", nil)
+
+				authRepo.EXPECT().
+					UpdatePassword(context.Background(), gomock.Any()).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo update password internal error
+		{
+			name: "новый пароль совпадает со старым",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "password1",
+				NewPassword: "password1",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // новый пароль совпадает со старым
+		{
+			name: "слабый новый пароль",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				OldPassword: "password1",
+				NewPassword: "password",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слабый новый пароль
+		{
+			name: "пустой старый пароль",
+			change: &entity.PasswordChange{
+				Username:    "user",
+				NewPassword: "password2",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустой старый пароль
+		{
+			name:        "nil",
+			change:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo, *hasher)
+			}
+
+			tokens, err := svc.ChangePassword(context.Background(), tt.change)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, tokens)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, &entity.TokenPair{
+					AccessToken:  "token",
+					RefreshToken: "refresh",
+				}, tokens)
+			}
+		})
+	}
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIAuthRepository(ctrl)
+	tokenRepo := mocks.NewMockITokenRepository(ctrl)
+	hasher := mocks.NewMockIHashCrypto(ctrl)
+	tokenManager := mocks.NewMockITokenManager(ctrl)
+
+	svc := service.NewAuthService(repo, tokenRepo, logger, hasher, tokenManager, false)
+
+	tests := []struct {
+		name        string
+		authInfo    *entity.Auth
+		beforeTest  func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешный сброс пароля",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				hasher.EXPECT().
+					HashPassword("password2").
+					Return("newHashedPass", nil)
+
+				authRepo.EXPECT().
+					UpdatePassword(context.Background(), &entity.Auth{
+						Username: "user",
+						Password: "newHashedPass",
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешный сброс пароля
+		{
+			name: "пользователь не найден",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				hasher.EXPECT().
+					HashPassword("password2").
+					Return("newHashedPass", nil)
+
+				authRepo.EXPECT().
+					UpdatePassword(context.Background(), gomock.Any()).
+					Return(errs.UserNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.UserNotFound,
+		}, // пользователь не найден
+		{
+			name: "hasher internal error",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				hasher.EXPECT().
+					HashPassword("password2").
+					Return("", fmt.Errorf("hasher internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // hasher internal error
+		{
+			name: "repo update password internal error",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "password2",
+			},
+			beforeTest: func(authRepo mocks.MockIAuthRepository, hasher mocks.MockIHashCrypto) {
+				hasher.EXPECT().
+					HashPassword("password2").
+					Return("newHashedPass", nil)
+
+				authRepo.EXPECT().
+					UpdatePassword(context.Background(), gomock.Any()).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo update password internal error
+		{
+			name: "слабый пароль",
+			authInfo: &entity.Auth{
+				Username: "user",
+				Password: "12345678",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слабый пароль
+		{
+			name: "пустое имя",
+			authInfo: &entity.Auth{
+				Password: "password2",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя
+		{
+			name:        "nil",
+			authInfo:    nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -248,7 +1344,7 @@ func TestAuthService_Auth(t *testing.T) {
 				tt.beforeTest(*repo, *hasher)
 			}
 
-			_, err := svc.Auth(context.Background(), tt.authInfo)
+			err := svc.ResetPassword(context.Background(), tt.authInfo)
 
 			if tt.wantErr {
 				require.Equal(t, tt.requiredErr, err)
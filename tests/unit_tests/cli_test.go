@@ -0,0 +1,202 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/cli"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLI_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userService := mocks.NewMockIUserService(ctrl)
+	itemService := mocks.NewMockIItemService(ctrl)
+	testApp := &app.App{
+		Logger:      mocks.NewMockLogger(),
+		UserService: userService,
+		ItemService: itemService,
+	}
+	ctx := context.Background()
+	txTime := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	stock := int32(5)
+
+	tests := []struct {
+		name        string
+		args        []string
+		beforeTest  func()
+		wantOut     string
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "просмотр пользователя",
+			args: []string{"user", "show", "user"},
+			beforeTest: func() {
+				userService.EXPECT().
+					GetCoinsHistory(ctx, "user").
+					Return(int32(900), &entity.CoinsHistory{
+						Received: []*entity.CoinsHistoryEntry{{Username: "", Coins: 100, Comment: "bonus"}},
+						Sent:     []*entity.CoinsHistoryEntry{{Username: "other", Coins: 200}},
+					}, nil)
+				itemService.EXPECT().
+					GetInventory(ctx, "user").
+					Return([]*entity.Item{{Name: "cup", Quantity: 2}}, nil)
+			},
+			wantOut: "USERNAME  COINS\n" +
+				"user      900\n" +
+				"\n" +
+				"ITEM  QUANTITY\n" +
+				"cup   2\n" +
+				"\n" +
+				"DIRECTION  USER   AMOUNT  COMMENT\n" +
+				"received   -      100     bonus\n" +
+				"sent       other  200     -\n",
+		}, // просмотр пользователя
+		{
+			name: "начисление монет в json",
+			args: []string{"-o", "json", "coins", "grant", "user", "100", "-comment", "bonus"},
+			beforeTest: func() {
+				userService.EXPECT().
+					GrantCoins(ctx, &entity.GrantCoins{Username: "user", Amount: 100, Comment: "bonus"}).
+					Return(nil)
+				userService.EXPECT().
+					GetCoinsHistory(ctx, "user").
+					Return(int32(1100), &entity.CoinsHistory{}, nil)
+			},
+			wantOut: "{\n  \"username\": \"user\",\n  \"coins\": 1100\n}\n",
+		}, // начисление монет в json
+		{
+			name: "начисление несуществующему пользователю",
+			args: []string{"coins", "grant", "nobody", "100"},
+			beforeTest: func() {
+				userService.EXPECT().
+					GrantCoins(ctx, &entity.GrantCoins{Username: "nobody", Amount: 100}).
+					Return(errs.UserNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.UserNotFound,
+		}, // начисление несуществующему пользователю
+		{
+			name: "добавление товара с остатком",
+			args: []string{"item", "add", "-stock", "5", "cap", "30"},
+			beforeTest: func() {
+				itemService.EXPECT().
+					CreateItem(ctx, &entity.Item{Name: "cap", Price: 30, Stock: &stock}).
+					Return(nil)
+			},
+			wantOut: "NAME  PRICE  STOCK\ncap   30     5\n",
+		}, // добавление товара с остатком
+		{
+			name: "выгрузка истории по страницам",
+			args: []string{"-o", "json", "history", "export", "-direction", "received", "user"},
+			beforeTest: func() {
+				next := &entity.TransactionsCursor{Time: txTime, ID: "1"}
+				userService.EXPECT().
+					GetTransactions(ctx, &entity.TransactionsFilter{
+						Username:  "user",
+						Direction: entity.DirectionReceived,
+						Limit:     100,
+					}).
+					Return(&entity.TransactionsPage{
+						Transactions: []*entity.Transaction{{ID: "1", Time: txTime, ToUser: "user", Amount: 100}},
+						Next:         next,
+					}, nil)
+				userService.EXPECT().
+					GetTransactions(ctx, &entity.TransactionsFilter{
+						Username:  "user",
+						Direction: entity.DirectionReceived,
+						After:     next,
+						Limit:     100,
+					}).
+					Return(&entity.TransactionsPage{
+						Transactions: []*entity.Transaction{{ID: "2", Time: txTime, FromUser: "other", ToUser: "user", Amount: 10}},
+					}, nil)
+			},
+			wantOut: `[
+  {
+    "id": "1",
+    "time": "2025-02-01T12:00:00Z",
+    "fromUser": "",
+    "toUser": "user",
+    "amount": 100,
+    "source": "grant"
+  },
+  {
+    "id": "2",
+    "time": "2025-02-01T12:00:00Z",
+    "fromUser": "other",
+    "toUser": "user",
+    "amount": 10,
+    "source": "user"
+  }
+]
+`,
+		}, // выгрузка истории по страницам
+		{
+			name: "выгрузка истории таблицей",
+			args: []string{"history", "export", "user"},
+			beforeTest: func() {
+				userService.EXPECT().
+					GetTransactions(ctx, &entity.TransactionsFilter{
+						Username: "user",
+						Limit:    100,
+					}).
+					Return(&entity.TransactionsPage{
+						Transactions: []*entity.Transaction{
+							{ID: "2", Time: txTime, FromUser: "user", ToUser: "other", Amount: 10, Comment: "thanks"},
+							{ID: "1", Time: txTime, ToUser: "user", Amount: 100},
+						},
+					}, nil)
+			},
+			wantOut: "TIME                  ID  SOURCE  FROM  TO     AMOUNT  COMMENT\n" +
+				"2025-02-01T12:00:00Z  2   user    user  other  10      thanks\n" +
+				"2025-02-01T12:00:00Z  1   grant   -     user   100     -\n",
+		}, // выгрузка истории таблицей
+		{
+			name:    "цена не число",
+			args:    []string{"item", "add", "cap", "free"},
+			wantErr: true,
+		}, // цена не число
+		{
+			name:    "неизвестная команда",
+			args:    []string{"user", "delete", "user"},
+			wantErr: true,
+		}, // неизвестная команда
+		{
+			name:    "неизвестный формат вывода",
+			args:    []string{"-o", "xml", "user", "show", "user"},
+			wantErr: true,
+		}, // неизвестный формат вывода
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest()
+			}
+			out := new(bytes.Buffer)
+
+			err := cli.Run(ctx, testApp, tt.args, out)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.requiredErr != nil {
+					require.True(t, errors.Is(err, tt.requiredErr), err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
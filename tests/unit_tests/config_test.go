@@ -0,0 +1,175 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/config"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigYAML = `
+logger:
+  level: 'info'
+  file: 'logs.log'
+database:
+  host: 'db'
+  port: 5432
+  user: 'postgres'
+  dbname: 'shop'
+jwt:
+  accessTTL: '15m'
+rateLimit:
+  routes:
+    - path: '/api/auth'
+      limit: 30
+      window: '1m'
+`
+
+func writeTestFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReadConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		yaml        string // no config file if empty
+		env         map[string]string
+		check       func(t *testing.T, cfg *config.Config)
+		wantInvalid []string // fields listed in validation error
+		wantErr     bool
+	}{
+		{
+			name: "переопределение из окружения",
+			yaml: testConfigYAML,
+			env: map[string]string{
+				"AVITO_SHOP_DATABASE_PASSWORD":          "secret",
+				"AVITO_SHOP_JWT_KEY":                    "jwt-secret",
+				"AVITO_SHOP_JWT_ACCESS_TTL":             "5m",
+				"AVITO_SHOP_AUTH_AUTO_REGISTER":         "false",
+				"AVITO_SHOP_AUTH_BRUTE_FORCE_MAX_DELAY": "1h",
+				"AVITO_SHOP_HASH_ARGON2_THREADS":        "4",
+				"AVITO_SHOP_TRACING_SAMPLE_RATIO":       "0.5",
+				"AVITO_SHOP_RATE_LIMIT_ROUTES_0_LIMIT":  "60",
+				"AVITO_SHOP_RATE_LIMIT_ROUTES_1_PATH":   "/api/register",
+				"AVITO_SHOP_RATE_LIMIT_ROUTES_1_LIMIT":  "10",
+				"AVITO_SHOP_RATE_LIMIT_ROUTES_1_WINDOW": "1m",
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, "postgres", cfg.Database.User)
+				require.Equal(t, "secret", cfg.Database.Password)
+				require.Equal(t, "jwt-secret", cfg.Jwt.Key)
+				require.Equal(t, 5*time.Minute, cfg.Jwt.AccessTTL)
+				require.False(t, cfg.Auth.AutoRegister)
+				require.Equal(t, time.Hour, cfg.Auth.BruteForce.MaxDelay)
+				require.Equal(t, uint8(4), cfg.Hash.Argon2.Threads)
+				require.Equal(t, 0.5, cfg.Tracing.SampleRatio)
+				require.Equal(t, []config.RouteRateLimitConfig{
+					{Path: "/api/auth", Limit: 60, Window: time.Minute},
+					{Path: "/api/register", Limit: 10, Window: time.Minute},
+				}, cfg.RateLimit.Routes)
+			},
+		}, // переопределение из окружения
+		{
+			name: "значения по умолчанию без файла",
+			env: map[string]string{
+				"AVITO_SHOP_DATABASE_USER":   "postgres",
+				"AVITO_SHOP_DATABASE_DBNAME": "shop",
+				"AVITO_SHOP_JWT_KEY":         "jwt-secret",
+			},
+			check: func(t *testing.T, cfg *config.Config) {
+				require.Equal(t, 8080, cfg.HTTP.Port)
+				require.Equal(t, "localhost", cfg.Database.Host)
+				require.Equal(t, 5432, cfg.Database.Port)
+				require.Equal(t, 15*time.Minute, cfg.Jwt.AccessTTL)
+				require.Equal(t, "bcrypt", cfg.Hash.Algorithm)
+				require.Equal(t, "postgres", cfg.RateLimit.Store)
+				require.True(t, cfg.Auth.AutoRegister)
+			},
+		}, // значения по умолчанию без файла
+		{
+			name: "все ошибки перечислены",
+			yaml: testConfigYAML,
+			env: map[string]string{
+				"AVITO_SHOP_HTTP_PORT":                  "0",
+				"AVITO_SHOP_DATABASE_HOST":              "",
+				"AVITO_SHOP_HASH_ALGORITHM":             "md5",
+				"AVITO_SHOP_RATE_LIMIT_ROUTES_0_WINDOW": "0s",
+				"AVITO_SHOP_TRACING_EXPORTER":           "otlp",
+				"AVITO_SHOP_LOGGER_LEVEL":               "verbose",
+				"AVITO_SHOP_SHOP_REFUND_WINDOW":         "soon",
+			},
+			wantInvalid: []string{
+				"logger.level",
+				"http.port",
+				"database.host",
+				"jwt.key",
+				"hash.algorithm",
+				"rateLimit.routes[0].window",
+				"tracing.endpoint",
+				"shop.refundWindow",
+			},
+		}, // все ошибки перечислены
+		{
+			name:    "неизвестный ключ",
+			yaml:    testConfigYAML + "\nunknown:\n  key: 'value'\n",
+			wantErr: true,
+		}, // неизвестный ключ
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			path := ""
+			if tt.yaml != "" {
+				path = writeTestFile(t, "config.yaml", tt.yaml)
+			}
+
+			cfg, err := config.ReadConfig(path)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			if tt.wantInvalid != nil {
+				var verr *config.ValidationError
+				require.True(t, errors.As(err, &verr), err)
+				require.Len(t, verr.Fields, len(tt.wantInvalid), verr.Error())
+				for _, field := range tt.wantInvalid {
+					require.Contains(t, verr.Error(), field+":")
+				}
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestReadConfig_SecretFiles(t *testing.T) {
+	t.Setenv("AVITO_SHOP_DATABASE_USER", "postgres")
+	t.Setenv("AVITO_SHOP_DATABASE_DBNAME", "shop")
+	t.Setenv("AVITO_SHOP_DATABASE_PASSWORD_FILE", writeTestFile(t, "db_password", "secret\n"))
+	t.Setenv("AVITO_SHOP_JWT_KEY_FILE", writeTestFile(t, "jwt_key", "jwt-secret"))
+
+	cfg, err := config.ReadConfig("")
+	require.NoError(t, err)
+	require.Equal(t, "secret", cfg.Database.Password)
+	require.Equal(t, "jwt-secret", cfg.Jwt.Key)
+
+	// value and file of one field are ambiguous
+	t.Setenv("AVITO_SHOP_JWT_KEY", "other")
+	_, err = config.ReadConfig("")
+	var verr *config.ValidationError
+	require.True(t, errors.As(err, &verr), err)
+	require.NotEmpty(t, verr.Fields)
+	for _, field := range verr.Fields {
+		require.Contains(t, field, "jwt.key")
+	}
+}
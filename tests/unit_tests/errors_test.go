@@ -0,0 +1,112 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/middlewares"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantStatus     int
+		wantCode       string
+		wantMessage    string
+		wantRetryAfter string
+		wantLogged     bool
+	}{
+		{
+			name:        "известная ошибка",
+			err:         errs.New("Buying item", errs.NotEnoughCoins),
+			wantStatus:  fiber.StatusBadRequest,
+			wantCode:    errs.CodeNotEnoughCoins,
+			wantMessage: "Buying item: not enough coins",
+		}, // известная ошибка
+		{
+			name:        "статус изменен обработчиком",
+			err:         errs.New("Buying item", errs.ItemNotFound).WithStatus(fiber.StatusBadRequest, errs.ItemNotFound),
+			wantStatus:  fiber.StatusBadRequest,
+			wantCode:    errs.CodeItemNotFound,
+			wantMessage: "Buying item: item not found",
+		}, // статус изменен обработчиком
+		{
+			name:        "внутренняя ошибка",
+			err:         errs.New("Buying item", errs.InternalError),
+			wantStatus:  fiber.StatusInternalServerError,
+			wantCode:    errs.CodeInternalError,
+			wantMessage: "Buying item: internal error",
+			wantLogged:  true,
+		}, // внутренняя ошибка
+		{
+			name:        "неизвестная ошибка скрыта",
+			err:         fmt.Errorf("connecting to 10.0.0.1:5432: password authentication failed"),
+			wantStatus:  fiber.StatusInternalServerError,
+			wantCode:    errs.CodeInternalError,
+			wantMessage: "internal error",
+			wantLogged:  true,
+		}, // неизвестная ошибка скрыта
+		{
+			name:           "повтор после ожидания",
+			err:            errs.New("Authorization", &errs.RetryAfterError{Err: errs.TooManyAttempts, RetryAfter: 1500 * time.Millisecond}),
+			wantStatus:     fiber.StatusTooManyRequests,
+			wantCode:       errs.CodeTooManyAttempts,
+			wantMessage:    "Authorization: too many failed attempts",
+			wantRetryAfter: "2",
+		}, // повтор после ожидания
+		{
+			name:        "ошибка fiber",
+			err:         fiber.ErrRequestEntityTooLarge,
+			wantStatus:  fiber.StatusRequestEntityTooLarge,
+			wantCode:    "request_entity_too_large",
+			wantMessage: fiber.ErrRequestEntityTooLarge.Message,
+		}, // ошибка fiber
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			testApp := &app.App{Logger: logger.NewLogger(logger.LoggerInfoLevel, buf)}
+
+			r := fiber.New(fiber.Config{ErrorHandler: middlewares.ErrorHandler(testApp)})
+			r.Use(middlewares.RequestIDMiddleware())
+			r.Get("/api/buy/:item", func(ctx *fiber.Ctx) error {
+				return tt.err
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/api/buy/cup", nil)
+			req.Header.Set(fiber.HeaderXRequestID, "req-1")
+			resp, err := r.Test(req)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+			require.Equal(t, tt.wantRetryAfter, resp.Header.Get(fiber.HeaderRetryAfter))
+			var body models.ErrorResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			require.Equal(t, models.ErrorResponse{
+				Errors:    tt.wantMessage,
+				Code:      tt.wantCode,
+				RequestID: "req-1",
+			}, body)
+
+			if tt.wantLogged {
+				records := decodeLogRecords(t, buf)
+				require.Len(t, records, 1)
+				require.Equal(t, "error", records[0]["level"])
+				require.Equal(t, "req-1", records[0]["request_id"])
+			} else {
+				require.Empty(t, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/jwt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashCrypto(t *testing.T) {
+	bcryptHasher := jwt.NewHashCrypto(jwt.HashParams{
+		Algorithm:  jwt.AlgorithmBcrypt,
+		BcryptCost: 4,
+	})
+	argon2Params := jwt.Argon2Params{Time: 1, Memory: 1024, Threads: 1}
+	argon2Hasher := jwt.NewHashCrypto(jwt.HashParams{
+		Algorithm: jwt.AlgorithmArgon2id,
+		Argon2:    argon2Params,
+	})
+
+	tests := []struct {
+		name         string
+		hasher       jwt.IHashCrypto
+		verifier     jwt.IHashCrypto
+		prefix       string
+		wantRehashed bool
+	}{
+		{
+			name:     "bcrypt",
+			hasher:   bcryptHasher,
+			verifier: bcryptHasher,
+			prefix:   "$2a$04$",
+		}, // bcrypt
+		{
+			name:     "argon2id",
+			hasher:   argon2Hasher,
+			verifier: argon2Hasher,
+			prefix:   "$argon2id$v=19$m=1024,t=1,p=1$",
+		}, // argon2id
+		{
+			name:   "bcrypt с устаревшей стоимостью",
+			hasher: bcryptHasher,
+			verifier: jwt.NewHashCrypto(jwt.HashParams{
+				Algorithm:  jwt.AlgorithmBcrypt,
+				BcryptCost: 5,
+			}),
+			prefix:       "$2a$04$",
+			wantRehashed: true,
+		}, // bcrypt с устаревшей стоимостью
+		{
+			name:   "argon2id с устаревшими параметрами",
+			hasher: argon2Hasher,
+			verifier: jwt.NewHashCrypto(jwt.HashParams{
+				Algorithm: jwt.AlgorithmArgon2id,
+				Argon2:    jwt.Argon2Params{Time: 2, Memory: 1024, Threads: 1},
+			}),
+			prefix:       "$argon2id$",
+			wantRehashed: true,
+		}, // argon2id с устаревшими параметрами
+		{
+			name:         "переход с bcrypt на argon2id",
+			hasher:       bcryptHasher,
+			verifier:     argon2Hasher,
+			prefix:       "$2a$",
+			wantRehashed: true,
+		}, // переход с bcrypt на argon2id
+		{
+			name:         "переход с argon2id на bcrypt",
+			hasher:       argon2Hasher,
+			verifier:     bcryptHasher,
+			prefix:       "$argon2id$",
+			wantRehashed: true,
+		}, // переход с argon2id на bcrypt
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.HashPassword("password1")
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+
+			require.True(t, tt.verifier.VerifyPassword("password1", hash))
+			require.False(t, tt.verifier.VerifyPassword("password2", hash))
+			require.Equal(t, tt.wantRehashed, tt.verifier.NeedsRehash(hash))
+		})
+	}
+
+	require.False(t, argon2Hasher.VerifyPassword("password1", "$argon2id$v=19$m=1024,t=1,p=1$invalid"))
+	// malformed stored hash must not panic
+	for _, params := range []string{"m=1024,t=1,p=0", "m=1024,t=0,p=1", "m=0,t=1,p=1"} {
+		hash := "$argon2id$v=19$" + params + "$c29tZXNhbHRzb21lc2FsdA$Zm9vYmFyZm9vYmFyZm9vYmFyZm9vYmFyZm9vYmFyZm9vYg"
+		require.NotPanics(t, func() {
+			require.False(t, argon2Hasher.VerifyPassword("password1", hash))
+		}, params)
+	}
+	require.False(t, bcryptHasher.VerifyPassword("password1", "hashedPass"))
+}
@@ -0,0 +1,156 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchemaVersion = 13
+
+func TestHealthService_Ready(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIHealthRepository(ctrl)
+
+	tests := []struct {
+		name         string
+		beforeTest   func(repo mocks.MockIHealthRepository)
+		shuttingDown bool
+		wantStatus   string
+		wantChecks   map[string]string // check name -> expected error, empty if ok
+	}{
+		{
+			name: "все проверки пройдены",
+			beforeTest: func(repo mocks.MockIHealthRepository) {
+				repo.EXPECT().
+					Ping(gomock.Any()).
+					Return(nil)
+				repo.EXPECT().
+					GetSchemaVersion(gomock.Any()).
+					Return(&entity.SchemaVersion{Version: testSchemaVersion}, nil)
+			},
+			wantStatus: entity.HealthStatusOK,
+			wantChecks: map[string]string{
+				service.HealthCheckShutdown:   "",
+				service.HealthCheckDatabase:   "",
+				service.HealthCheckMigrations: "",
+			},
+		}, // все проверки пройдены
+		{
+			name: "бд недоступна",
+			beforeTest: func(repo mocks.MockIHealthRepository) {
+				repo.EXPECT().
+					Ping(gomock.Any()).
+					Return(fmt.Errorf("connection refused"))
+				repo.EXPECT().
+					GetSchemaVersion(gomock.Any()).
+					Return(nil, fmt.Errorf("connection refused"))
+			},
+			wantStatus: entity.HealthStatusFail,
+			wantChecks: map[string]string{
+				service.HealthCheckShutdown:   "",
+				service.HealthCheckDatabase:   "database is unavailable",
+				service.HealthCheckMigrations: "schema version is not up to date",
+			},
+		}, // бд недоступна
+		{
+			name: "миграции не применены",
+			beforeTest: func(repo mocks.MockIHealthRepository) {
+				repo.EXPECT().
+					Ping(gomock.Any()).
+					Return(nil)
+				repo.EXPECT().
+					GetSchemaVersion(gomock.Any()).
+					Return(&entity.SchemaVersion{Version: testSchemaVersion - 1}, nil)
+			},
+			wantStatus: entity.HealthStatusFail,
+			wantChecks: map[string]string{
+				service.HealthCheckShutdown:   "",
+				service.HealthCheckDatabase:   "",
+				service.HealthCheckMigrations: "schema version is not up to date",
+			},
+		}, // миграции не применены
+		{
+			name: "миграция завершилась ошибкой",
+			beforeTest: func(repo mocks.MockIHealthRepository) {
+				repo.EXPECT().
+					Ping(gomock.Any()).
+					Return(nil)
+				repo.EXPECT().
+					GetSchemaVersion(gomock.Any()).
+					Return(&entity.SchemaVersion{Version: testSchemaVersion, Dirty: true}, nil)
+			},
+			wantStatus: entity.HealthStatusFail,
+			wantChecks: map[string]string{
+				service.HealthCheckShutdown:   "",
+				service.HealthCheckDatabase:   "",
+				service.HealthCheckMigrations: "schema version is not up to date",
+			},
+		}, // миграция завершилась ошибкой
+		{
+			name: "остановка сервера",
+			beforeTest: func(repo mocks.MockIHealthRepository) {
+				repo.EXPECT().
+					Ping(gomock.Any()).
+					Return(nil)
+				repo.EXPECT().
+					GetSchemaVersion(gomock.Any()).
+					Return(&entity.SchemaVersion{Version: testSchemaVersion}, nil)
+			},
+			shuttingDown: true,
+			wantStatus:   entity.HealthStatusFail,
+			wantChecks: map[string]string{
+				service.HealthCheckShutdown:   "server is shutting down",
+				service.HealthCheckDatabase:   "",
+				service.HealthCheckMigrations: "",
+			},
+		}, // остановка сервера
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewHealthService(repo, logger, testSchemaVersion, time.Second)
+			tt.beforeTest(*repo)
+			if tt.shuttingDown {
+				svc.SetShuttingDown()
+			}
+
+			report := svc.Ready(context.Background())
+
+			require.Equal(t, tt.wantStatus, report.Status)
+			require.Len(t, report.Checks, len(tt.wantChecks))
+			for _, check := range report.Checks {
+				wantErr, ok := tt.wantChecks[check.Name]
+				require.True(t, ok, check.Name)
+				if wantErr == "" {
+					require.Equal(t, entity.HealthStatusOK, check.Status, check.Name)
+					require.Empty(t, check.Error, check.Name)
+				} else {
+					require.Equal(t, entity.HealthStatusFail, check.Status, check.Name)
+					require.Equal(t, wantErr, check.Error, check.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthService_Live(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// liveness must not depend on database, so repository is never called
+	svc := service.NewHealthService(mocks.NewMockIHealthRepository(ctrl), mocks.NewMockLogger(), testSchemaVersion, 0)
+	svc.SetShuttingDown()
+
+	report := svc.Live(context.Background())
+	require.Equal(t, entity.HealthStatusOK, report.Status)
+}
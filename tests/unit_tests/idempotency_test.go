@@ -0,0 +1,387 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyService_Reserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIIdempotencyRepository(ctrl)
+
+	svc := service.NewIdempotencyService(repo, logger)
+
+	tests := []struct {
+		name        string
+		record      *entity.IdempotencyRecord
+		beforeTest  func(repo mocks.MockIIdempotencyRepository)
+		wantStored  bool
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "ключ зарезервирован",
+			record: &entity.IdempotencyRecord{
+				Username:    "user",
+				Key:         "key",
+				RequestHash: "hash",
+			},
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				repo.EXPECT().
+					Reserve(context.Background(), &entity.IdempotencyRecord{
+						Username:    "user",
+						Key:         "key",
+						RequestHash: "hash",
+					}).
+					Return(nil, nil)
+			},
+			wantStored: false,
+			wantErr:    false,
+		}, // ключ зарезервирован
+		{
+			name: "повтор запроса",
+			record: &entity.IdempotencyRecord{
+				Username:    "user",
+				Key:         "key",
+				RequestHash: "hash",
+			},
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				repo.EXPECT().
+					Reserve(context.Background(), &entity.IdempotencyRecord{
+						Username:    "user",
+						Key:         "key",
+						RequestHash: "hash",
+					}).
+					Return(&entity.IdempotencyRecord{
+						Username:    "user",
+						Key:         "key",
+						RequestHash: "hash",
+						StatusCode:  200,
+					}, nil)
+			},
+			wantStored: true,
+			wantErr:    false,
+		}, // повтор запроса
+		{
+			name: "ключ использован для другого запроса",
+			record: &entity.IdempotencyRecord{
+				Username:    "user",
+				Key:         "key",
+				RequestHash: "hash",
+			},
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				repo.EXPECT().
+					Reserve(context.Background(), &entity.IdempotencyRecord{
+						Username:    "user",
+						Key:         "key",
+						RequestHash: "hash",
+					}).
+					Return(nil, errs.KeyReused)
+			},
+			wantErr:     true,
+			requiredErr: errs.KeyReused,
+		}, // ключ использован для другого запроса
+		{
+			name: "запрос с тем же ключом выполняется",
+			record: &entity.IdempotencyRecord{
+				Username:    "user",
+				Key:         "key",
+				RequestHash: "hash",
+			},
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				repo.EXPECT().
+					Reserve(context.Background(), &entity.IdempotencyRecord{
+						Username:    "user",
+						Key:         "key",
+						RequestHash: "hash",
+					}).
+					Return(nil, errs.RequestInProgress)
+			},
+			wantErr:     true,
+			requiredErr: errs.RequestInProgress,
+		}, // запрос с тем же ключом выполняется
+		{
+			name: "repo reserve error",
+			record: &entity.IdempotencyRecord{
+				Username:    "user",
+				Key:         "key",
+				RequestHash: "hash",
+			},
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				repo.EXPECT().
+					Reserve(context.Background(), &entity.IdempotencyRecord{
+						Username:    "user",
+						Key:         "key",
+						RequestHash: "hash",
+					}).
+					Return(nil, fmt.Errorf("repo reserve error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo reserve error
+		{
+			name: "слишком длинный ключ",
+			record: &entity.IdempotencyRecord{
+				Username:    "user",
+				Key:         strings.Repeat("k", 65),
+				RequestHash: "hash",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком длинный ключ
+		{
+			name: "непечатные символы в ключе",
+			record: &entity.IdempotencyRecord{
+				Username:    "user",
+				Key:         "key\n",
+				RequestHash: "hash",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // непечатные символы в ключе
+		{
+			name: "пустой хеш запроса",
+			record: &entity.IdempotencyRecord{
+				Username: "user",
+				Key:      "key",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустой хеш запроса
+		{
+			name: "пустое имя пользователя",
+			record: &entity.IdempotencyRecord{
+				Key:         "key",
+				RequestHash: "hash",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя пользователя
+		{
+			name:        "nil",
+			record:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			stored, err := svc.Reserve(context.Background(), tt.record)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, stored)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tt.wantStored, stored != nil)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIIdempotencyRepository(ctrl)
+
+	svc := service.NewIdempotencyService(repo, logger)
+
+	record := &entity.IdempotencyRecord{
+		Username:    "user",
+		Key:         "key",
+		RequestHash: "hash",
+	}
+	errHandle := fmt.Errorf("handle error")
+	inTransaction := func(repo mocks.MockIIdempotencyRepository) {
+		repo.EXPECT().
+			InTransaction(context.Background(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	tests := []struct {
+		name        string
+		record      *entity.IdempotencyRecord
+		handleErr   error
+		beforeTest  func(repo mocks.MockIIdempotencyRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:   "ответ сохранен в транзакции обработчика",
+			record: record,
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				inTransaction(repo)
+				repo.EXPECT().
+					SaveResponse(context.Background(), record).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // ответ сохранен в транзакции обработчика
+		{
+			name:      "ошибка обработчика",
+			record:    record,
+			handleErr: errHandle,
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				inTransaction(repo)
+			},
+			wantErr:     true,
+			requiredErr: errHandle,
+		}, // ошибка обработчика
+		{
+			name:   "ключ перехвачен повтором",
+			record: record,
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				inTransaction(repo)
+				repo.EXPECT().
+					SaveResponse(context.Background(), record).
+					Return(fmt.Errorf("idempotency key was taken over: %w", errs.RequestInProgress))
+			},
+			wantErr:     true,
+			requiredErr: errs.RequestInProgress,
+		}, // ключ перехвачен повтором
+		{
+			name:   "repo save response error",
+			record: record,
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				inTransaction(repo)
+				repo.EXPECT().
+					SaveResponse(context.Background(), record).
+					Return(fmt.Errorf("repo save response error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo save response error
+		{
+			name:        "nil",
+			record:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			err := svc.Execute(context.Background(), tt.record, func(ctx context.Context) error {
+				return tt.handleErr
+			})
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestIdempotencyService_DeleteExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIIdempotencyRepository(ctrl)
+
+	svc := service.NewIdempotencyService(repo, logger)
+
+	now := time.Now()
+	repo.EXPECT().
+		DeleteExpired(context.Background(), now).
+		Return(nil)
+	require.NoError(t, svc.DeleteExpired(context.Background(), now))
+
+	repo.EXPECT().
+		DeleteExpired(context.Background(), now).
+		Return(fmt.Errorf("db internal error"))
+	require.Equal(t, errs.InternalError, svc.DeleteExpired(context.Background(), now))
+}
+
+func TestIdempotencyService_Release(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIIdempotencyRepository(ctrl)
+
+	svc := service.NewIdempotencyService(repo, logger)
+
+	record := &entity.IdempotencyRecord{
+		Username:    "user",
+		Key:         "key",
+		RequestHash: "hash",
+	}
+
+	tests := []struct {
+		name        string
+		record      *entity.IdempotencyRecord
+		beforeTest  func(repo mocks.MockIIdempotencyRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:   "успешное освобождение ключа",
+			record: record,
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				repo.EXPECT().
+					Release(context.Background(), record).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешное освобождение ключа
+		{
+			name:   "repo release error",
+			record: record,
+			beforeTest: func(repo mocks.MockIIdempotencyRepository) {
+				repo.EXPECT().
+					Release(context.Background(), record).
+					Return(fmt.Errorf("repo release error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo release error
+		{
+			name:        "nil",
+			record:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			err := svc.Release(context.Background(), tt.record)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
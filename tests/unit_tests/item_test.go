@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -20,7 +21,7 @@ func TestItemService_BuyItem(t *testing.T) {
 	logger := mocks.NewMockLogger()
 	itemRepo := mocks.NewMockIItemRepository(ctrl)
 
-	svc := service.NewItemService(itemRepo, logger)
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
 
 	tests := []struct {
 		name        string
@@ -100,6 +101,24 @@ func TestItemService_BuyItem(t *testing.T) {
 			wantErr:     true,
 			requiredErr: errs.NotEnoughCoins,
 		}, // пользователю не хватает монет
+		{
+			name: "предмет закончился",
+			purchase: &entity.Purchase{
+				Username: "user",
+				ItemName: "cup",
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					BuyItem(context.Background(),
+						&entity.Purchase{
+							Username: "user",
+							ItemName: "cup",
+						}).
+					Return(errs.OutOfStock)
+			},
+			wantErr:     true,
+			requiredErr: errs.OutOfStock,
+		}, // предмет закончился
 		{
 			name: "repo buy item error",
 			purchase: &entity.Purchase{
@@ -160,6 +179,209 @@ func TestItemService_BuyItem(t *testing.T) {
 	}
 }
 
+func TestItemService_BuyItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	tooManyLines := make([]*entity.CartLine, 51)
+	for i := range tooManyLines {
+		tooManyLines[i] = &entity.CartLine{ItemName: fmt.Sprintf("item%d", i), Quantity: 1}
+	}
+
+	tests := []struct {
+		name        string
+		cart        *entity.Cart
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешная покупка корзины",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "socks", Quantity: 5},
+					{ItemName: "cup", Quantity: 1},
+				},
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					BuyItems(context.Background(), &entity.Cart{
+						Username: "user",
+						Lines: []*entity.CartLine{
+							{ItemName: "socks", Quantity: 5},
+							{ItemName: "cup", Quantity: 1},
+						},
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешная покупка корзины
+		{
+			name: "одинаковые предметы объединяются",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "socks", Quantity: 2},
+					{ItemName: "cup", Quantity: 1},
+					{ItemName: "socks", Quantity: 3},
+				},
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					BuyItems(context.Background(), &entity.Cart{
+						Username: "user",
+						Lines: []*entity.CartLine{
+							{ItemName: "socks", Quantity: 5},
+							{ItemName: "cup", Quantity: 1},
+						},
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // одинаковые предметы объединяются
+		{
+			name: "пользователю не хватает монет",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "pink-hoody", Quantity: 3},
+				},
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					BuyItems(context.Background(), &entity.Cart{
+						Username: "user",
+						Lines: []*entity.CartLine{
+							{ItemName: "pink-hoody", Quantity: 3},
+						},
+					}).
+					Return(errs.NotEnoughCoins)
+			},
+			wantErr:     true,
+			requiredErr: errs.NotEnoughCoins,
+		}, // пользователю не хватает монет
+		{
+			name: "repo buy items error",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "cup", Quantity: 1},
+				},
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					BuyItems(context.Background(), &entity.Cart{
+						Username: "user",
+						Lines: []*entity.CartLine{
+							{ItemName: "cup", Quantity: 1},
+						},
+					}).
+					Return(fmt.Errorf("repo buy items error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo buy items error
+		{
+			name: "пустая корзина",
+			cart: &entity.Cart{
+				Username: "user",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустая корзина
+		{
+			name: "слишком много позиций",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines:    tooManyLines,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком много позиций
+		{
+			name: "одинаковые позиции превышают лимит единиц",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "pen", Quantity: 600},
+					{ItemName: "pen", Quantity: 600},
+				},
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // одинаковые позиции превышают лимит единиц
+		{
+			name: "нулевое количество",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "cup", Quantity: 0},
+				},
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // нулевое количество
+		{
+			name: "пустое название предмета",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines: []*entity.CartLine{
+					{ItemName: "", Quantity: 1},
+				},
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое название предмета
+		{
+			name: "nil позиция",
+			cart: &entity.Cart{
+				Username: "user",
+				Lines:    []*entity.CartLine{nil},
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil позиция
+		{
+			name: "пустое имя пользователя",
+			cart: &entity.Cart{
+				Username: "",
+				Lines: []*entity.CartLine{
+					{ItemName: "cup", Quantity: 1},
+				},
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя пользователя
+		{
+			name:        "nil",
+			cart:        nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			err := svc.BuyItems(context.Background(), tt.cart)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
 func TestItemService_GetInventory(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -167,7 +389,7 @@ func TestItemService_GetInventory(t *testing.T) {
 	logger := mocks.NewMockLogger()
 	itemRepo := mocks.NewMockIItemRepository(ctrl)
 
-	svc := service.NewItemService(itemRepo, logger)
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
 
 	tests := []struct {
 		name        string
@@ -227,3 +449,898 @@ func TestItemService_GetInventory(t *testing.T) {
 		})
 	}
 }
+
+func TestItemService_GetItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	minPrice := int32(10)
+	maxPrice := int32(100)
+	negativePrice := int32(-1)
+
+	tests := []struct {
+		name        string
+		filter      *entity.ItemsFilter
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешное получение каталога",
+			filter: &entity.ItemsFilter{
+				MinPrice: &minPrice,
+				MaxPrice: &maxPrice,
+				Sort:     entity.SortByPriceAsc,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetItems(context.Background(), &entity.ItemsFilter{
+						MinPrice: &minPrice,
+						MaxPrice: &maxPrice,
+						Sort:     entity.SortByPriceAsc,
+					}).
+					Return([]*entity.Item{
+						{
+							Name:  "cup",
+							Price: 20,
+						},
+					}, nil)
+			},
+			wantErr: false,
+		}, // успешное получение каталога
+		{
+			name:   "успешное получение каталога без фильтров",
+			filter: &entity.ItemsFilter{},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetItems(context.Background(), &entity.ItemsFilter{}).
+					Return([]*entity.Item{}, nil)
+			},
+			wantErr: false,
+		}, // успешное получение каталога без фильтров
+		{
+			name:   "repo get items error",
+			filter: &entity.ItemsFilter{},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetItems(context.Background(), &entity.ItemsFilter{}).
+					Return(nil, fmt.Errorf("repo get items error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo get items error
+		{
+			name: "минимальная цена больше максимальной",
+			filter: &entity.ItemsFilter{
+				MinPrice: &maxPrice,
+				MaxPrice: &minPrice,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // минимальная цена больше максимальной
+		{
+			name: "отрицательная минимальная цена",
+			filter: &entity.ItemsFilter{
+				MinPrice: &negativePrice,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // отрицательная минимальная цена
+		{
+			name: "отрицательная максимальная цена",
+			filter: &entity.ItemsFilter{
+				MaxPrice: &negativePrice,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // отрицательная максимальная цена
+		{
+			name: "неизвестная сортировка",
+			filter: &entity.ItemsFilter{
+				Sort: "name_desc",
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // неизвестная сортировка
+		{
+			name:        "nil",
+			filter:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			items, err := svc.GetItems(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, items)
+			} else {
+				require.Nil(t, err)
+				require.NotNil(t, items)
+			}
+		})
+	}
+}
+
+func TestItemService_GetItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	tests := []struct {
+		name        string
+		itemName    string
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:     "успешное получение предмета",
+			itemName: "cup",
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetItem(context.Background(), "cup").
+					Return(&entity.Item{
+						Name:  "cup",
+						Price: 20,
+					}, nil)
+			},
+			wantErr: false,
+		}, // успешное получение предмета
+		{
+			name:     "предмет не найден",
+			itemName: "undefined",
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetItem(context.Background(), "undefined").
+					Return(nil, errs.ItemNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // предмет не найден
+		{
+			name:     "repo get item error",
+			itemName: "cup",
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetItem(context.Background(), "cup").
+					Return(nil, fmt.Errorf("repo get item error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo get item error
+		{
+			name:        "пустое название предмета",
+			itemName:    "",
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое название предмета
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			item, err := svc.GetItem(context.Background(), tt.itemName)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, item)
+			} else {
+				require.Nil(t, err)
+				require.NotNil(t, item)
+			}
+		})
+	}
+}
+
+func TestItemService_CreateItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	negativeStock := int32(-1)
+
+	tests := []struct {
+		name        string
+		item        *entity.Item
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешное создание предмета",
+			item: &entity.Item{
+				Name:  "sticker",
+				Price: 5,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					CreateItem(context.Background(), &entity.Item{
+						Name:  "sticker",
+						Price: 5,
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешное создание предмета
+		{
+			name: "предмет уже существует",
+			item: &entity.Item{
+				Name:  "cup",
+				Price: 20,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					CreateItem(context.Background(), &entity.Item{
+						Name:  "cup",
+						Price: 20,
+					}).
+					Return(errs.ItemAlreadyExists)
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemAlreadyExists,
+		}, // предмет уже существует
+		{
+			name: "repo create item error",
+			item: &entity.Item{
+				Name:  "sticker",
+				Price: 5,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					CreateItem(context.Background(), &entity.Item{
+						Name:  "sticker",
+						Price: 5,
+					}).
+					Return(fmt.Errorf("repo create item error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo create item error
+		{
+			name: "пустое название предмета",
+			item: &entity.Item{
+				Name:  "",
+				Price: 5,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое название предмета
+		{
+			name: "слишком длинное название предмета",
+			item: &entity.Item{
+				Name:  "very-very-long-item-name-over-32-chars",
+				Price: 5,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком длинное название предмета
+		{
+			name: "отрицательная цена",
+			item: &entity.Item{
+				Name:  "sticker",
+				Price: -5,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // отрицательная цена
+		{
+			name: "отрицательный остаток",
+			item: &entity.Item{
+				Name:  "sticker",
+				Price: 5,
+				Stock: &negativeStock,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // отрицательный остаток
+		{
+			name:        "nil",
+			item:        nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			err := svc.CreateItem(context.Background(), tt.item)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestItemService_UpdateItemPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	tests := []struct {
+		name        string
+		item        *entity.Item
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешное изменение цены",
+			item: &entity.Item{
+				Name:  "pink-hoody",
+				Price: 450,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					UpdateItemPrice(context.Background(), &entity.Item{
+						Name:  "pink-hoody",
+						Price: 450,
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешное изменение цены
+		{
+			name: "предмет не найден",
+			item: &entity.Item{
+				Name:  "undefined",
+				Price: 450,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					UpdateItemPrice(context.Background(), &entity.Item{
+						Name:  "undefined",
+						Price: 450,
+					}).
+					Return(errs.ItemNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // предмет не найден
+		{
+			name: "repo update item price error",
+			item: &entity.Item{
+				Name:  "pink-hoody",
+				Price: 450,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					UpdateItemPrice(context.Background(), &entity.Item{
+						Name:  "pink-hoody",
+						Price: 450,
+					}).
+					Return(fmt.Errorf("repo update item price error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo update item price error
+		{
+			name: "отрицательная цена",
+			item: &entity.Item{
+				Name:  "pink-hoody",
+				Price: -1,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // отрицательная цена
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			err := svc.UpdateItemPrice(context.Background(), tt.item)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestItemService_UpdateItemStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	stock := int32(50)
+	negativeStock := int32(-1)
+
+	tests := []struct {
+		name        string
+		item        *entity.Item
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешное изменение остатка",
+			item: &entity.Item{
+				Name:  "pink-hoody",
+				Stock: &stock,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					UpdateItemStock(context.Background(), &entity.Item{
+						Name:  "pink-hoody",
+						Stock: &stock,
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешное изменение остатка
+		{
+			name: "снятие ограничения остатка",
+			item: &entity.Item{
+				Name: "pink-hoody",
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					UpdateItemStock(context.Background(), &entity.Item{
+						Name: "pink-hoody",
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // снятие ограничения остатка
+		{
+			name: "предмет не найден",
+			item: &entity.Item{
+				Name:  "undefined",
+				Stock: &stock,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					UpdateItemStock(context.Background(), &entity.Item{
+						Name:  "undefined",
+						Stock: &stock,
+					}).
+					Return(errs.ItemNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // предмет не найден
+		{
+			name: "repo update item stock error",
+			item: &entity.Item{
+				Name:  "pink-hoody",
+				Stock: &stock,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					UpdateItemStock(context.Background(), &entity.Item{
+						Name:  "pink-hoody",
+						Stock: &stock,
+					}).
+					Return(fmt.Errorf("repo update item stock error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo update item stock error
+		{
+			name: "отрицательный остаток",
+			item: &entity.Item{
+				Name:  "pink-hoody",
+				Stock: &negativeStock,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // отрицательный остаток
+		{
+			name:        "nil",
+			item:        nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			err := svc.UpdateItemStock(context.Background(), tt.item)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestItemService_RetireItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	tests := []struct {
+		name        string
+		itemName    string
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:     "успешное снятие с продажи",
+			itemName: "umbrella",
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RetireItem(context.Background(), "umbrella").
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешное снятие с продажи
+		{
+			name:     "предмет не найден",
+			itemName: "undefined",
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RetireItem(context.Background(), "undefined").
+					Return(errs.ItemNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.ItemNotFound,
+		}, // предмет не найден
+		{
+			name:     "repo retire item error",
+			itemName: "umbrella",
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RetireItem(context.Background(), "umbrella").
+					Return(fmt.Errorf("repo retire item error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo retire item error
+		{
+			name:        "пустое название предмета",
+			itemName:    "",
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое название предмета
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			err := svc.RetireItem(context.Background(), tt.itemName)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestItemService_GetPurchases(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	purchases := []*entity.PurchaseRecord{
+		{ID: "3", Time: now, ItemName: "cup", Price: 20},
+		{ID: "2", Time: now.Add(-time.Minute), ItemName: "pen", Price: 10},
+		{ID: "1", Time: now.Add(-2 * time.Minute), ItemName: "cup", Price: 15},
+	}
+
+	tests := []struct {
+		name        string
+		filter      *entity.PurchasesFilter
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		page        *entity.PurchasesPage
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "страница с продолжением",
+			filter: &entity.PurchasesFilter{
+				Username: "user",
+				Limit:    2,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetPurchases(context.Background(), &entity.PurchasesFilter{
+						Username: "user",
+						Limit:    3,
+					}).
+					Return(purchases, nil)
+			},
+			page: &entity.PurchasesPage{
+				Purchases: purchases[:2],
+				Next: &entity.PurchasesCursor{
+					Time: purchases[1].Time,
+					ID:   purchases[1].ID,
+				},
+			},
+			wantErr: false,
+		}, // страница с продолжением
+		{
+			name: "последняя страница",
+			filter: &entity.PurchasesFilter{
+				Username: "user",
+				From:     &earlier,
+				To:       &now,
+				Limit:    3,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetPurchases(context.Background(), &entity.PurchasesFilter{
+						Username: "user",
+						From:     &earlier,
+						To:       &now,
+						Limit:    4,
+					}).
+					Return(purchases, nil)
+			},
+			page: &entity.PurchasesPage{
+				Purchases: purchases,
+			},
+			wantErr: false,
+		}, // последняя страница
+		{
+			name: "repo get purchases error",
+			filter: &entity.PurchasesFilter{
+				Username: "user",
+				Limit:    2,
+			},
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					GetPurchases(context.Background(), &entity.PurchasesFilter{
+						Username: "user",
+						Limit:    3,
+					}).
+					Return(nil, fmt.Errorf("repo error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo get purchases error
+		{
+			name: "начало периода позже конца",
+			filter: &entity.PurchasesFilter{
+				Username: "user",
+				From:     &now,
+				To:       &earlier,
+				Limit:    2,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // начало периода позже конца
+		{
+			name: "слишком большая страница",
+			filter: &entity.PurchasesFilter{
+				Username: "user",
+				Limit:    101,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком большая страница
+		{
+			name: "пустой курсор",
+			filter: &entity.PurchasesFilter{
+				Username: "user",
+				After:    &entity.PurchasesCursor{Time: now},
+				Limit:    2,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустой курсор
+		{
+			name: "пустое имя пользователя",
+			filter: &entity.PurchasesFilter{
+				Limit: 2,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя пользователя
+		{
+			name:        "nil",
+			filter:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			page, err := svc.GetPurchases(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, page)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tt.page, page)
+			}
+		})
+	}
+}
+
+func TestItemService_RefundPurchase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	itemRepo := mocks.NewMockIItemRepository(ctrl)
+
+	svc := service.NewItemService(itemRepo, logger, time.Hour)
+
+	const purchaseID = "6f1b6c1e-2f4a-4c3e-9a57-1d2f3b4c5d6e"
+	purchase := &entity.PurchaseRecord{
+		ID:       purchaseID,
+		Time:     time.Now(),
+		ItemName: "cup",
+		Price:    20,
+		Refunded: true,
+	}
+
+	tests := []struct {
+		name        string
+		username    string
+		purchaseID  string
+		beforeTest  func(itemRepo mocks.MockIItemRepository)
+		purchase    *entity.PurchaseRecord
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:       "успешный возврат",
+			username:   "user",
+			purchaseID: purchaseID,
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RefundPurchase(context.Background(), &entity.Refund{
+						Username:   "user",
+						PurchaseID: purchaseID,
+						Window:     time.Hour,
+					}).
+					Return(purchase, nil)
+			},
+			purchase: purchase,
+			wantErr:  false,
+		}, // успешный возврат
+		{
+			name:       "покупка не найдена",
+			username:   "user",
+			purchaseID: purchaseID,
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RefundPurchase(context.Background(), gomock.Any()).
+					Return(nil, errs.PurchaseNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.PurchaseNotFound,
+		}, // покупка не найдена
+		{
+			name:       "повторный возврат",
+			username:   "user",
+			purchaseID: purchaseID,
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RefundPurchase(context.Background(), gomock.Any()).
+					Return(nil, errs.AlreadyRefunded)
+			},
+			wantErr:     true,
+			requiredErr: errs.AlreadyRefunded,
+		}, // повторный возврат
+		{
+			name:       "срок возврата истек",
+			username:   "user",
+			purchaseID: purchaseID,
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RefundPurchase(context.Background(), gomock.Any()).
+					Return(nil, errs.RefundExpired)
+			},
+			wantErr:     true,
+			requiredErr: errs.RefundExpired,
+		}, // срок возврата истек
+		{
+			name:       "repo refund purchase error",
+			username:   "user",
+			purchaseID: purchaseID,
+			beforeTest: func(itemRepo mocks.MockIItemRepository) {
+				itemRepo.EXPECT().
+					RefundPurchase(context.Background(), gomock.Any()).
+					Return(nil, fmt.Errorf("repo error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo refund purchase error
+		{
+			name:        "некорректный идентификатор покупки",
+			username:    "user",
+			purchaseID:  "123",
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // некорректный идентификатор покупки
+		{
+			name:        "пустое имя пользователя",
+			username:    "",
+			purchaseID:  purchaseID,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя пользователя
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*itemRepo)
+			}
+
+			refunded, err := svc.RefundPurchase(context.Background(), tt.username, tt.purchaseID)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, refunded)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tt.purchase, refunded)
+			}
+		})
+	}
+
+	t.Run("возвраты отключены", func(t *testing.T) {
+		disabledSvc := service.NewItemService(itemRepo, logger, 0)
+
+		refunded, err := disabledSvc.RefundPurchase(context.Background(), "user", purchaseID)
+		require.Equal(t, errs.RefundExpired, err)
+		require.Nil(t, refunded)
+	})
+}
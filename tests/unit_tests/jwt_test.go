@@ -0,0 +1,207 @@
+package unit_tests
+
+import (
+	pkgjwt "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/jwt"
+	webjwt "Avito-Backend-trainee-assignment-winter-2025/internal/web/jwt"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaKeyPEM(t *testing.T) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func ed25519KeyPEM(t *testing.T) []byte {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func newTokenManager(t *testing.T, secret string, keys ...*pkgjwt.SigningKey) pkgjwt.ITokenManager {
+	keySet, err := pkgjwt.NewKeySet(secret, keys...)
+	require.NoError(t, err)
+	return pkgjwt.NewTokenManager(keySet, time.Minute, time.Hour)
+}
+
+func TestTokenManager_SigningKeys(t *testing.T) {
+	rsaKey, err := pkgjwt.ParseSigningKey("rsa", rsaKeyPEM(t))
+	require.NoError(t, err)
+	require.Equal(t, "RS256", rsaKey.Method.Alg())
+	edKey, err := pkgjwt.ParseSigningKey("ed", ed25519KeyPEM(t))
+	require.NoError(t, err)
+	require.Equal(t, "EdDSA", edKey.Method.Alg())
+
+	tests := []struct {
+		name        string
+		signer      pkgjwt.ITokenManager
+		verifier    pkgjwt.ITokenManager
+		expectedAlg string
+		expectedKID string
+		wantErr     bool
+	}{
+		{
+			name:        "HS256",
+			signer:      newTokenManager(t, "secret"),
+			verifier:    newTokenManager(t, "secret"),
+			expectedAlg: "HS256",
+		}, // HS256
+		{
+			name:        "RS256",
+			signer:      newTokenManager(t, "", rsaKey),
+			verifier:    newTokenManager(t, "", rsaKey),
+			expectedAlg: "RS256",
+			expectedKID: "rsa",
+		}, // RS256
+		{
+			name:        "EdDSA",
+			signer:      newTokenManager(t, "", edKey),
+			verifier:    newTokenManager(t, "", edKey),
+			expectedAlg: "EdDSA",
+			expectedKID: "ed",
+		}, // EdDSA
+		{
+			name:        "ротация ключа: токен старого ключа действителен",
+			signer:      newTokenManager(t, "", rsaKey),
+			verifier:    newTokenManager(t, "", edKey, rsaKey),
+			expectedAlg: "RS256",
+			expectedKID: "rsa",
+		}, // ротация ключа: токен старого ключа действителен
+		{
+			name:        "переход с HS256: старый токен действителен",
+			signer:      newTokenManager(t, "secret"),
+			verifier:    newTokenManager(t, "secret", edKey),
+			expectedAlg: "HS256",
+		}, // переход с HS256: старый токен действителен
+		{
+			name:     "удаленный ключ",
+			signer:   newTokenManager(t, "", rsaKey),
+			verifier: newTokenManager(t, "", edKey),
+			wantErr:  true,
+		}, // удаленный ключ
+		{
+			name:     "HS256 токен без секрета",
+			signer:   newTokenManager(t, "secret"),
+			verifier: newTokenManager(t, "", edKey),
+			wantErr:  true,
+		}, // HS256 токен без секрета
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenString, err := tt.signer.CreateToken("user", "user")
+			require.NoError(t, err)
+
+			token, err := tt.verifier.VerifyToken(tokenString)
+
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedAlg, token.Method.Alg())
+				if tt.expectedKID == "" {
+					require.NotContains(t, token.Header, "kid")
+				} else {
+					require.Equal(t, tt.expectedKID, token.Header["kid"])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenManager_AlgorithmConfusion(t *testing.T) {
+	rsaKey, err := pkgjwt.ParseSigningKey("rsa", rsaKeyPEM(t))
+	require.NoError(t, err)
+	verifier := newTokenManager(t, "secret", rsaKey)
+
+	// HS256 token signed with public key bytes, pretending to be signed by RSA key
+	publicDER, err := x509.MarshalPKIXPublicKey(rsaKey.Key.Public())
+	require.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "admin"})
+	token.Header["kid"] = "rsa"
+	tokenString, err := token.SignedString(publicDER)
+	require.NoError(t, err)
+
+	_, err = verifier.VerifyToken(tokenString)
+	require.Error(t, err)
+}
+
+func TestFGetIssuedAtFromJWT(t *testing.T) {
+	tokenManager := newTokenManager(t, "secret")
+	before := time.Now().Truncate(time.Microsecond)
+	tokenString, err := tokenManager.CreateToken("user", "user")
+	require.NoError(t, err)
+	after := time.Now()
+	token, err := tokenManager.VerifyToken(tokenString)
+	require.NoError(t, err)
+
+	var iat time.Time
+	r := fiber.New()
+	r.Get("/", func(ctx *fiber.Ctx) error {
+		ctx.Locals("user", token)
+		iat, err = webjwt.FGetIssuedAtFromJWT(ctx)
+		return err
+	})
+	resp, err := r.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// fraction of second is kept, otherwise iat would be before the moment token was created
+	require.False(t, iat.Before(before), "iat %v before %v", iat, before)
+	require.False(t, iat.After(after), "iat %v after %v", iat, after)
+}
+
+func TestKeySet_Invalid(t *testing.T) {
+	edKey, err := pkgjwt.ParseSigningKey("ed", ed25519KeyPEM(t))
+	require.NoError(t, err)
+
+	_, err = pkgjwt.NewKeySet("")
+	require.Error(t, err)
+
+	_, err = pkgjwt.NewKeySet("", edKey, edKey)
+	require.Error(t, err)
+
+	_, err = pkgjwt.ParseSigningKey("", ed25519KeyPEM(t))
+	require.Error(t, err)
+
+	_, err = pkgjwt.ParseSigningKey("bad", []byte("not a pem"))
+	require.Error(t, err)
+}
+
+func TestToJWKSTransport(t *testing.T) {
+	rsaKey, err := pkgjwt.ParseSigningKey("rsa", rsaKeyPEM(t))
+	require.NoError(t, err)
+	edKey, err := pkgjwt.ParseSigningKey("ed", ed25519KeyPEM(t))
+	require.NoError(t, err)
+
+	keySet, err := pkgjwt.NewKeySet("secret", edKey, rsaKey)
+	require.NoError(t, err)
+
+	jwks := models.ToJWKSTransport(keySet.PublicKeys())
+	require.Len(t, jwks.Keys, 2)
+
+	require.Equal(t, "OKP", jwks.Keys[0].Kty)
+	require.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	require.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	require.Equal(t, "ed", jwks.Keys[0].Kid)
+	require.NotEmpty(t, jwks.Keys[0].X)
+
+	require.Equal(t, "RSA", jwks.Keys[1].Kty)
+	require.Equal(t, "RS256", jwks.Keys[1].Alg)
+	require.Equal(t, "rsa", jwks.Keys[1].Kid)
+	require.Equal(t, "AQAB", jwks.Keys[1].E)
+	require.NotEmpty(t, jwks.Keys[1].N)
+}
@@ -0,0 +1,142 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/logger"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/middlewares"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	records := make([]map[string]interface{}, 0)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		record := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger_WithContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantFields map[string]interface{}
+		noFields   []string
+	}{
+		{
+			name: "id запроса и пользователь",
+			ctx:  logger.WithUsername(logger.WithRequestID(context.Background(), "req-1"), "user"),
+			wantFields: map[string]interface{}{
+				"request_id": "req-1",
+				"username":   "user",
+			},
+		}, // id запроса и пользователь
+		{
+			name: "запрос без авторизации",
+			ctx:  logger.WithRequestID(context.Background(), "req-2"),
+			wantFields: map[string]interface{}{
+				"request_id": "req-2",
+			},
+			noFields: []string{"username"},
+		}, // запрос без авторизации
+		{
+			name:     "контекст вне запроса",
+			ctx:      context.Background(),
+			noFields: []string{"request_id", "username"},
+		}, // контекст вне запроса
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			l := logger.NewLogger(logger.LoggerInfoLevel, buf)
+
+			l.WithContext(tt.ctx).Warnf("buying item %s", "cup")
+
+			records := decodeLogRecords(t, buf)
+			require.Len(t, records, 1)
+			require.Equal(t, "buying item cup", records[0]["message"])
+			require.Equal(t, "warn", records[0]["level"])
+			for key, value := range tt.wantFields {
+				require.Equal(t, value, records[0][key], key)
+			}
+			for _, key := range tt.noFields {
+				require.NotContains(t, records[0], key)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string
+		wantRequestID string // empty if new id must be generated
+	}{
+		{
+			name:          "id от клиента",
+			requestID:     "0f8fad5b-d9cb-469f-a165-70867728950e",
+			wantRequestID: "0f8fad5b-d9cb-469f-a165-70867728950e",
+		}, // id от клиента
+		{
+			name: "нет id",
+		}, // нет id
+		{
+			name:      "недопустимые символы",
+			requestID: "id\" injected=\"value",
+		}, // недопустимые символы
+		{
+			name:      "слишком длинный id",
+			requestID: strings.Repeat("a", 129),
+		}, // слишком длинный id
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			testApp := &app.App{Logger: logger.NewLogger(logger.LoggerInfoLevel, buf)}
+
+			r := fiber.New()
+			r.Use(middlewares.RequestIDMiddleware())
+			r.Use(middlewares.AccessLogMiddleware(testApp))
+			r.Get("/api/items/:name", func(ctx *fiber.Ctx) error {
+				testApp.Logger.WithContext(ctx.UserContext()).Infof("getting item")
+				return ctx.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/api/items/cup", nil)
+			if tt.requestID != "" {
+				req.Header.Set(fiber.HeaderXRequestID, tt.requestID)
+			}
+			resp, err := r.Test(req)
+			require.NoError(t, err)
+
+			requestID := resp.Header.Get(fiber.HeaderXRequestID)
+			if tt.wantRequestID != "" {
+				require.Equal(t, tt.wantRequestID, requestID)
+			} else {
+				require.NotEmpty(t, requestID)
+				require.NotEqual(t, tt.requestID, requestID)
+			}
+
+			// service record and access record of one request share request id
+			records := decodeLogRecords(t, buf)
+			require.Len(t, records, 2)
+			require.Equal(t, "getting item", records[0]["message"])
+			require.Equal(t, requestID, records[0]["request_id"])
+			require.Equal(t, requestID, records[1]["request_id"])
+			require.Equal(t, "/api/items/:name", records[1]["route"])
+			require.Equal(t, float64(fiber.StatusOK), records[1]["status"])
+		})
+	}
+}
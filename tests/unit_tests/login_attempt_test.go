@@ -0,0 +1,433 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var bruteForcePolicy = entity.BruteForcePolicy{
+	Window:            15 * time.Minute,
+	UsernameThreshold: 3,
+	IPThreshold:       10,
+	BaseDelay:         time.Second,
+	MaxDelay:          time.Minute,
+}
+
+func TestLoginAttemptService_Reserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockILoginAttemptRepository(ctrl)
+
+	svc := service.NewLoginAttemptService(repo, logger, bruteForcePolicy)
+
+	now := time.Now()
+	attempt := &entity.LoginAttempt{
+		ID:       1,
+		Username: "user",
+		IP:       "127.0.0.1",
+		Time:     now,
+	}
+	since := now.Add(-bruteForcePolicy.Window)
+
+	tests := []struct {
+		name           string
+		attempt        *entity.LoginAttempt
+		beforeTest     func(repo mocks.MockILoginAttemptRepository)
+		wantErr        bool
+		requiredErr    error
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:    "нет неудачных попыток",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(&entity.LoginFailures{}, nil)
+			},
+			wantErr: false,
+		}, // нет неудачных попыток
+		{
+			name:    "меньше порога по имени",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(&entity.LoginFailures{
+						UsernameCount:       2,
+						UsernameLastFailure: now,
+						IPCount:             2,
+						IPLastFailure:       now,
+					}, nil)
+			},
+			wantErr: false,
+		}, // меньше порога по имени
+		{
+			name:    "порог по имени достигнут",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(&entity.LoginFailures{
+						UsernameCount:       3,
+						UsernameLastFailure: now,
+						IPCount:             3,
+						IPLastFailure:       now,
+					}, nil)
+				repo.EXPECT().
+					DeleteAttempt(context.Background(), int64(1)).
+					Return(nil)
+			},
+			wantErr:        true,
+			requiredErr:    errs.TooManyAttempts,
+			wantRetryAfter: time.Second,
+		}, // порог по имени достигнут
+		{
+			name:    "экспоненциальная задержка",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(&entity.LoginFailures{
+						UsernameCount:       6,
+						UsernameLastFailure: now.Add(-time.Second),
+						IPCount:             6,
+						IPLastFailure:       now.Add(-time.Second),
+					}, nil)
+				repo.EXPECT().
+					DeleteAttempt(context.Background(), int64(1)).
+					Return(nil)
+			},
+			wantErr:        true,
+			requiredErr:    errs.TooManyAttempts,
+			wantRetryAfter: 7 * time.Second,
+		}, // экспоненциальная задержка
+		{
+			name:    "задержка истекла",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(&entity.LoginFailures{
+						UsernameCount:       4,
+						UsernameLastFailure: now.Add(-5 * time.Second),
+						IPCount:             4,
+						IPLastFailure:       now.Add(-5 * time.Second),
+					}, nil)
+			},
+			wantErr: false,
+		}, // задержка истекла
+		{
+			name:    "блокировка на максимальную задержку",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(&entity.LoginFailures{
+						UsernameCount:       100,
+						UsernameLastFailure: now,
+						IPCount:             100,
+						IPLastFailure:       now,
+					}, nil)
+				repo.EXPECT().
+					DeleteAttempt(context.Background(), int64(1)).
+					Return(nil)
+			},
+			wantErr:        true,
+			requiredErr:    errs.TooManyAttempts,
+			wantRetryAfter: time.Minute,
+		}, // блокировка на максимальную задержку
+		{
+			name:    "порог по ip достигнут",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(&entity.LoginFailures{
+						IPCount:       10,
+						IPLastFailure: now,
+					}, nil)
+				repo.EXPECT().
+					DeleteAttempt(context.Background(), int64(1)).
+					Return(nil)
+			},
+			wantErr:        true,
+			requiredErr:    errs.TooManyAttempts,
+			wantRetryAfter: time.Second,
+		}, // порог по ip достигнут
+		{
+			name:    "repo internal error",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ReserveAttempt(context.Background(), attempt, since).
+					Return(nil, fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo internal error
+		{
+			name: "пустой ip",
+			attempt: &entity.LoginAttempt{
+				Username: "user",
+				Time:     now,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустой ip
+		{
+			name:        "nil",
+			attempt:     nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			err := svc.Reserve(context.Background(), tt.attempt)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, tt.requiredErr)
+				var retryErr *errs.RetryAfterError
+				if errors.As(err, &retryErr) {
+					require.Equal(t, tt.wantRetryAfter, retryErr.RetryAfter)
+				} else {
+					require.Zero(t, tt.wantRetryAfter)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoginAttemptService_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockILoginAttemptRepository(ctrl)
+
+	svc := service.NewLoginAttemptService(repo, logger, entity.BruteForcePolicy{})
+
+	// repository must not be called
+	require.NoError(t, svc.Reserve(context.Background(), nil))
+	require.NoError(t, svc.Release(context.Background(), nil))
+	require.NoError(t, svc.Succeeded(context.Background(), nil))
+	require.NoError(t, svc.DeleteExpired(context.Background(), time.Now()))
+}
+
+func TestLoginAttemptService_Release(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockILoginAttemptRepository(ctrl)
+
+	svc := service.NewLoginAttemptService(repo, logger, bruteForcePolicy)
+
+	attempt := &entity.LoginAttempt{
+		ID:       1,
+		Username: "user",
+		IP:       "127.0.0.1",
+		Time:     time.Now(),
+	}
+
+	tests := []struct {
+		name        string
+		attempt     *entity.LoginAttempt
+		beforeTest  func(repo mocks.MockILoginAttemptRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:    "успешное удаление",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					DeleteAttempt(context.Background(), int64(1)).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешное удаление
+		{
+			name:    "repo internal error",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					DeleteAttempt(context.Background(), int64(1)).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo internal error
+		{
+			name: "попытка не зарезервирована",
+			attempt: &entity.LoginAttempt{
+				Username: "user",
+				IP:       "127.0.0.1",
+				Time:     time.Now(),
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // попытка не зарезервирована
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			err := svc.Release(context.Background(), tt.attempt)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLoginAttemptService_DeleteExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockILoginAttemptRepository(ctrl)
+
+	svc := service.NewLoginAttemptService(repo, logger, bruteForcePolicy)
+
+	now := time.Now()
+	repo.EXPECT().
+		DeleteFailuresBefore(context.Background(), now.Add(-bruteForcePolicy.Window)).
+		Return(nil)
+	require.NoError(t, svc.DeleteExpired(context.Background(), now))
+
+	repo.EXPECT().
+		DeleteFailuresBefore(context.Background(), gomock.Any()).
+		Return(fmt.Errorf("db internal error"))
+	require.Equal(t, errs.InternalError, svc.DeleteExpired(context.Background(), now))
+}
+
+func TestLoginAttemptService_Succeeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockILoginAttemptRepository(ctrl)
+
+	svc := service.NewLoginAttemptService(repo, logger, bruteForcePolicy)
+
+	attempt := &entity.LoginAttempt{
+		Username: "user",
+		IP:       "127.0.0.1",
+		Time:     time.Now(),
+	}
+
+	tests := []struct {
+		name        string
+		attempt     *entity.LoginAttempt
+		beforeTest  func(repo mocks.MockILoginAttemptRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name:    "успешная очистка",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ClearUsernameFailures(context.Background(), "user").
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешная очистка
+		{
+			name: "удаление зарезервированной попытки",
+			attempt: &entity.LoginAttempt{
+				ID:       1,
+				Username: "user",
+				IP:       "127.0.0.1",
+				Time:     time.Now(),
+			},
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				gomock.InOrder(
+					repo.EXPECT().
+						DeleteAttempt(context.Background(), int64(1)).
+						Return(nil),
+					repo.EXPECT().
+						ClearUsernameFailures(context.Background(), "user").
+						Return(nil),
+				)
+			},
+			wantErr: false,
+		}, // удаление зарезервированной попытки
+		{
+			name: "ошибка удаления зарезервированной попытки",
+			attempt: &entity.LoginAttempt{
+				ID:       1,
+				Username: "user",
+				IP:       "127.0.0.1",
+				Time:     time.Now(),
+			},
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					DeleteAttempt(context.Background(), int64(1)).
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // ошибка удаления зарезервированной попытки
+		{
+			name:    "repo internal error",
+			attempt: attempt,
+			beforeTest: func(repo mocks.MockILoginAttemptRepository) {
+				repo.EXPECT().
+					ClearUsernameFailures(context.Background(), "user").
+					Return(fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo internal error
+		{
+			name: "пустое имя",
+			attempt: &entity.LoginAttempt{
+				IP:   "127.0.0.1",
+				Time: time.Now(),
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			err := svc.Succeeded(context.Background(), tt.attempt)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
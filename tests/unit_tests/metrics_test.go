@@ -0,0 +1,232 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/metrics"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func findMetric(families []*dto.MetricFamily, name string, labels map[string]string) *dto.Metric {
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metricLoop:
+		for _, metric := range family.Metric {
+			for _, label := range metric.Label {
+				if labels[label.GetName()] != label.GetValue() {
+					continue metricLoop
+				}
+			}
+			return metric
+		}
+	}
+	return nil
+}
+
+func TestMetrics_Merge(t *testing.T) {
+	// metrics of two prefork processes
+	first := metrics.NewMetrics()
+	second := metrics.NewMetrics()
+
+	first.Purchases.WithLabelValues("cup").Add(2)
+	second.Purchases.WithLabelValues("cup").Add(3)
+	second.Purchases.WithLabelValues("hoody").Add(1)
+	first.CoinsTransferred.Add(100)
+	second.CoinsTransferred.Add(50)
+	first.HTTPRequestDuration.WithLabelValues("GET", "/api/info", "200").Observe(0.002)
+	second.HTTPRequestDuration.WithLabelValues("GET", "/api/info", "200").Observe(0.2)
+	second.HTTPRequestDuration.WithLabelValues("GET", "/api/info", "500").Observe(3)
+
+	firstFamilies, err := first.Gather()
+	require.NoError(t, err)
+	secondFamilies, err := second.Gather()
+	require.NoError(t, err)
+	merged := metrics.Merge(firstFamilies, secondFamilies)
+
+	require.Equal(t, 5.0,
+		findMetric(merged, "shop_purchases_total", map[string]string{"item": "cup"}).GetCounter().GetValue())
+	require.Equal(t, 1.0,
+		findMetric(merged, "shop_purchases_total", map[string]string{"item": "hoody"}).GetCounter().GetValue())
+	require.Equal(t, 150.0,
+		findMetric(merged, "shop_coins_transferred_total", nil).GetCounter().GetValue())
+
+	histogram := findMetric(merged, "http_request_duration_seconds",
+		map[string]string{"method": "GET", "route": "/api/info", "status": "200"}).GetHistogram()
+	require.Equal(t, uint64(2), histogram.GetSampleCount())
+	require.InDelta(t, 0.202, histogram.GetSampleSum(), 1e-9)
+	for _, bucket := range histogram.Bucket {
+		switch {
+		case bucket.GetUpperBound() < 0.002:
+			require.Equal(t, uint64(0), bucket.GetCumulativeCount(), bucket.GetUpperBound())
+		case bucket.GetUpperBound() < 0.2:
+			require.Equal(t, uint64(1), bucket.GetCumulativeCount(), bucket.GetUpperBound())
+		default:
+			require.Equal(t, uint64(2), bucket.GetCumulativeCount(), bucket.GetUpperBound())
+		}
+	}
+	require.Equal(t, uint64(1), findMetric(merged, "http_request_duration_seconds",
+		map[string]string{"method": "GET", "route": "/api/info", "status": "500"}).GetHistogram().GetSampleCount())
+}
+
+func TestSnapshots_Gather(t *testing.T) {
+	dir := t.TempDir()
+	own := metrics.NewMetrics()
+	own.CoinsTransferred.Add(100)
+	other := metrics.NewMetrics()
+	other.CoinsTransferred.Add(50)
+
+	// snapshots are named by pid, so snapshot of other process is renamed
+	err := metrics.NewSnapshots(other, dir, time.Second).Write()
+	require.NoError(t, err)
+	otherPath := filepath.Join(dir, "1.prom")
+	require.NoError(t, os.Rename(filepath.Join(dir, strconv.Itoa(os.Getpid())+".prom"), otherPath))
+
+	snapshots := metrics.NewSnapshots(own, dir, time.Second)
+	merged, err := snapshots.Gather()
+	require.NoError(t, err)
+	require.Equal(t, 150.0, findMetric(merged, "shop_coins_transferred_total", nil).GetCounter().GetValue())
+
+	// process exited and stopped replacing its snapshot
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(otherPath, old, old))
+	merged, err = snapshots.Gather()
+	require.NoError(t, err)
+	require.Equal(t, 100.0, findMetric(merged, "shop_coins_transferred_total", nil).GetCounter().GetValue())
+	require.NoFileExists(t, otherPath)
+}
+
+func TestMetrics_ServiceDecorators(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	itemService := mocks.NewMockIItemService(ctrl)
+	userService := mocks.NewMockIUserService(ctrl)
+
+	tests := []struct {
+		name         string
+		beforeTest   func(itemService *mocks.MockIItemService, userService *mocks.MockIUserService)
+		run          func(itemService entity.IItemService, userService entity.IUserService) error
+		wantErr      bool
+		wantCheck    func(m *metrics.Metrics)
+		errorMessage string
+	}{
+		{
+			name: "покупка предмета",
+			beforeTest: func(itemService *mocks.MockIItemService, userService *mocks.MockIUserService) {
+				itemService.EXPECT().
+					BuyItem(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			run: func(itemService entity.IItemService, userService entity.IUserService) error {
+				return itemService.BuyItem(context.Background(), &entity.Purchase{Username: "user", ItemName: "cup"})
+			},
+			wantCheck: func(m *metrics.Metrics) {
+				require.Equal(t, 1.0, testutil.ToFloat64(m.Purchases.WithLabelValues("cup")))
+			},
+		}, // покупка предмета
+		{
+			name: "покупка корзины",
+			beforeTest: func(itemService *mocks.MockIItemService, userService *mocks.MockIUserService) {
+				itemService.EXPECT().
+					BuyItems(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			run: func(itemService entity.IItemService, userService entity.IUserService) error {
+				return itemService.BuyItems(context.Background(), &entity.Cart{
+					Username: "user",
+					Lines: []*entity.CartLine{
+						{ItemName: "cup", Quantity: 3},
+						{ItemName: "hoody", Quantity: 1},
+					},
+				})
+			},
+			wantCheck: func(m *metrics.Metrics) {
+				require.Equal(t, 3.0, testutil.ToFloat64(m.Purchases.WithLabelValues("cup")))
+				require.Equal(t, 1.0, testutil.ToFloat64(m.Purchases.WithLabelValues("hoody")))
+			},
+		}, // покупка корзины
+		{
+			name: "неудачная покупка",
+			beforeTest: func(itemService *mocks.MockIItemService, userService *mocks.MockIUserService) {
+				itemService.EXPECT().
+					BuyItem(gomock.Any(), gomock.Any()).
+					Return(errs.NotEnoughCoins)
+			},
+			run: func(itemService entity.IItemService, userService entity.IUserService) error {
+				return itemService.BuyItem(context.Background(), &entity.Purchase{Username: "user", ItemName: "cup"})
+			},
+			wantErr: true,
+			wantCheck: func(m *metrics.Metrics) {
+				require.Equal(t, 0, testutil.CollectAndCount(m.Purchases))
+				require.Equal(t, 1.0,
+					testutil.ToFloat64(m.Failures.WithLabelValues("buy", errs.NotEnoughCoins.Error())))
+			},
+			errorMessage: errs.NotEnoughCoins.Error(),
+		}, // неудачная покупка
+		{
+			name: "перевод монет",
+			beforeTest: func(itemService *mocks.MockIItemService, userService *mocks.MockIUserService) {
+				userService.EXPECT().
+					SendCoins(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			run: func(itemService entity.IItemService, userService entity.IUserService) error {
+				return userService.SendCoins(context.Background(),
+					&entity.TransferCoins{FromUser: "from", ToUser: "to", Amount: 100})
+			},
+			wantCheck: func(m *metrics.Metrics) {
+				require.Equal(t, 100.0, testutil.ToFloat64(m.CoinsTransferred))
+			},
+		}, // перевод монет
+		{
+			name: "неизвестная ошибка перевода",
+			beforeTest: func(itemService *mocks.MockIItemService, userService *mocks.MockIUserService) {
+				userService.EXPECT().
+					SendCoins(gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("connection refused"))
+			},
+			run: func(itemService entity.IItemService, userService entity.IUserService) error {
+				return userService.SendCoins(context.Background(),
+					&entity.TransferCoins{FromUser: "from", ToUser: "to", Amount: 100})
+			},
+			wantErr: true,
+			wantCheck: func(m *metrics.Metrics) {
+				require.Equal(t, 0.0, testutil.ToFloat64(m.CoinsTransferred))
+				require.Equal(t, 1.0,
+					testutil.ToFloat64(m.Failures.WithLabelValues("send_coins", errs.InternalError.Error())))
+			},
+			errorMessage: "connection refused",
+		}, // неизвестная ошибка перевода
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := metrics.NewMetrics()
+			tt.beforeTest(itemService, userService)
+
+			err := tt.run(service.NewItemServiceMetrics(itemService, m), service.NewUserServiceMetrics(userService, m))
+
+			if tt.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.errorMessage)
+			} else {
+				require.NoError(t, err)
+			}
+			tt.wantCheck(m)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/postgres"
+	"Avito-Backend-trainee-assignment-winter-2025/migrations"
+	"io/fs"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/stretchr/testify/require"
+)
+
+// every migration must be reversible by migrate down, versions must go without gaps
+func TestEmbeddedMigrations(t *testing.T) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	require.NoError(t, err)
+
+	up := make(map[uint]string)
+	down := make(map[uint]string)
+	for _, entry := range entries {
+		parsed, err := source.DefaultParse(entry.Name())
+		require.NoError(t, err, entry.Name())
+		if parsed.Direction == source.Up {
+			up[parsed.Version] = parsed.Identifier
+		} else {
+			down[parsed.Version] = parsed.Identifier
+		}
+	}
+
+	require.Len(t, up, int(postgres.SchemaVersion))
+	for version := uint(1); version <= uint(postgres.SchemaVersion); version++ {
+		require.Contains(t, up, version)
+		require.Equal(t, up[version], down[version], "down migration of version %d", version)
+	}
+	require.Len(t, down, len(up))
+}
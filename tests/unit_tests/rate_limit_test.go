@@ -0,0 +1,243 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/storage/memory"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitService_Allow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIRateLimitRepository(ctrl)
+
+	svc := service.NewRateLimitService(repo, logger, entity.RateLimitPolicy{
+		Default: entity.RateLimitBudget{Limit: 100, Window: time.Minute},
+		Routes: []entity.RouteRateLimit{
+			{Path: "/api/auth", RateLimitBudget: entity.RateLimitBudget{Limit: 10, Window: time.Minute}},
+			{Path: "/api/auth/refresh", RateLimitBudget: entity.RateLimitBudget{Limit: 5, Window: time.Hour}},
+			{Path: "/api/items", RateLimitBudget: entity.RateLimitBudget{}},
+		},
+	})
+
+	now := time.Date(2025, 2, 15, 12, 30, 15, 0, time.UTC)
+	minuteStart := time.Date(2025, 2, 15, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		request        *entity.RateLimitRequest
+		beforeTest     func(repo mocks.MockIRateLimitRepository)
+		expectedStatus *entity.RateLimitStatus
+		wantErr        bool
+		requiredErr    error
+	}{
+		{
+			name:    "бюджет по умолчанию",
+			request: &entity.RateLimitRequest{Subject: "user:user", Path: "/api/info", Time: now},
+			beforeTest: func(repo mocks.MockIRateLimitRepository) {
+				repo.EXPECT().
+					Increment(context.Background(), "user:user *", minuteStart).
+					Return(1, nil)
+			},
+			expectedStatus: &entity.RateLimitStatus{
+				Allowed:   true,
+				Limit:     100,
+				Remaining: 99,
+				Reset:     minuteStart.Add(time.Minute),
+			},
+			wantErr: false,
+		}, // бюджет по умолчанию
+		{
+			name:    "бюджет маршрута",
+			request: &entity.RateLimitRequest{Subject: "ip:127.0.0.1", Path: "/api/auth", Time: now},
+			beforeTest: func(repo mocks.MockIRateLimitRepository) {
+				repo.EXPECT().
+					Increment(context.Background(), "ip:127.0.0.1 /api/auth", minuteStart).
+					Return(10, nil)
+			},
+			expectedStatus: &entity.RateLimitStatus{
+				Allowed:   true,
+				Limit:     10,
+				Remaining: 0,
+				Reset:     minuteStart.Add(time.Minute),
+			},
+			wantErr: false,
+		}, // бюджет маршрута
+		{
+			name:    "вложенный путь использует бюджет родителя",
+			request: &entity.RateLimitRequest{Subject: "user:user", Path: "/api/auth/logout", Time: now},
+			beforeTest: func(repo mocks.MockIRateLimitRepository) {
+				repo.EXPECT().
+					Increment(context.Background(), "user:user /api/auth", minuteStart).
+					Return(1, nil)
+			},
+			expectedStatus: &entity.RateLimitStatus{
+				Allowed:   true,
+				Limit:     10,
+				Remaining: 9,
+				Reset:     minuteStart.Add(time.Minute),
+			},
+			wantErr: false,
+		}, // вложенный путь использует бюджет родителя
+		{
+			name:    "наиболее точный бюджет",
+			request: &entity.RateLimitRequest{Subject: "ip:127.0.0.1", Path: "/api/auth/refresh", Time: now},
+			beforeTest: func(repo mocks.MockIRateLimitRepository) {
+				repo.EXPECT().
+					Increment(context.Background(), "ip:127.0.0.1 /api/auth/refresh",
+						time.Date(2025, 2, 15, 12, 0, 0, 0, time.UTC)).
+					Return(6, nil)
+			},
+			expectedStatus: &entity.RateLimitStatus{
+				Allowed:   false,
+				Limit:     5,
+				Remaining: 0,
+				Reset:     time.Date(2025, 2, 15, 13, 0, 0, 0, time.UTC),
+			},
+			wantErr: false,
+		}, // наиболее точный бюджет
+		{
+			name:           "маршрут без ограничения",
+			request:        &entity.RateLimitRequest{Subject: "ip:127.0.0.1", Path: "/api/items/cup", Time: now},
+			expectedStatus: nil,
+			wantErr:        false,
+		}, // маршрут без ограничения
+		{
+			name:    "префикс без границы сегмента",
+			request: &entity.RateLimitRequest{Subject: "user:user", Path: "/api/authx", Time: now},
+			beforeTest: func(repo mocks.MockIRateLimitRepository) {
+				repo.EXPECT().
+					Increment(context.Background(), "user:user *", minuteStart).
+					Return(101, nil)
+			},
+			expectedStatus: &entity.RateLimitStatus{
+				Allowed:   false,
+				Limit:     100,
+				Remaining: 0,
+				Reset:     minuteStart.Add(time.Minute),
+			},
+			wantErr: false,
+		}, // префикс без границы сегмента
+		{
+			name:    "repo internal error",
+			request: &entity.RateLimitRequest{Subject: "user:user", Path: "/api/info", Time: now},
+			beforeTest: func(repo mocks.MockIRateLimitRepository) {
+				repo.EXPECT().
+					Increment(context.Background(), "user:user *", minuteStart).
+					Return(0, fmt.Errorf("db internal error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo internal error
+		{
+			name:        "пустой ключ",
+			request:     &entity.RateLimitRequest{Path: "/api/info", Time: now},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустой ключ
+		{
+			name:        "nil",
+			request:     nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			status, err := svc.Allow(context.Background(), tt.request)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, status)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedStatus, status)
+			}
+		})
+	}
+}
+
+func TestRateLimitService_DeleteExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIRateLimitRepository(ctrl)
+
+	svc := service.NewRateLimitService(repo, logger, entity.RateLimitPolicy{
+		Default: entity.RateLimitBudget{Limit: 100, Window: time.Minute},
+		Routes: []entity.RouteRateLimit{
+			{Path: "/api/auth/refresh", RateLimitBudget: entity.RateLimitBudget{Limit: 5, Window: time.Hour}},
+		},
+	})
+
+	now := time.Date(2025, 2, 15, 12, 30, 15, 0, time.UTC)
+	// counters of the longest window must survive until it ends
+	repo.EXPECT().
+		DeleteWindowsBefore(context.Background(), now.Add(-time.Hour)).
+		Return(nil)
+	require.NoError(t, svc.DeleteExpired(context.Background(), now))
+
+	repo.EXPECT().
+		DeleteWindowsBefore(context.Background(), gomock.Any()).
+		Return(fmt.Errorf("db internal error"))
+	require.Equal(t, errs.InternalError, svc.DeleteExpired(context.Background(), now))
+
+	disabled := service.NewRateLimitService(repo, logger, entity.RateLimitPolicy{})
+	require.NoError(t, disabled.DeleteExpired(context.Background(), now))
+}
+
+func TestMemoryRateLimitRepository_Increment(t *testing.T) {
+	repo := memory.NewRateLimitRepository()
+	window := time.Date(2025, 2, 15, 12, 30, 0, 0, time.UTC)
+
+	for i := 1; i <= 3; i++ {
+		count, err := repo.Increment(context.Background(), "user:user *", window)
+		require.NoError(t, err)
+		require.Equal(t, i, count)
+	}
+
+	count, err := repo.Increment(context.Background(), "user:other *", window)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	count, err = repo.Increment(context.Background(), "user:user *", window.Add(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func TestMemoryRateLimitRepository_DeleteWindowsBefore(t *testing.T) {
+	repo := memory.NewRateLimitRepository()
+	window := time.Date(2025, 2, 15, 12, 30, 0, 0, time.UTC)
+
+	_, err := repo.Increment(context.Background(), "user:user *", window.Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = repo.Increment(context.Background(), "user:other *", window)
+	require.NoError(t, err)
+
+	err = repo.DeleteWindowsBefore(context.Background(), window)
+	require.NoError(t, err)
+
+	// expired counter starts from scratch in the same window
+	count, err := repo.Increment(context.Background(), "user:user *", window.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	count, err = repo.Increment(context.Background(), "user:other *", window)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
@@ -0,0 +1,116 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/app"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/handlers"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/models"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/server"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// serverAddrEnv makes test binary run prefork server instead of test, fiber starts children
+// with the same arguments and environment, so they run server too
+const serverAddrEnv = "TEST_PREFORK_SERVER_ADDR"
+
+const (
+	testShutdownDelay   = 2 * time.Second
+	testShutdownTimeout = 2 * time.Second
+)
+
+func TestServer_PreforkShutdown(t *testing.T) {
+	if addr := os.Getenv(serverAddrEnv); addr != "" {
+		runPreforkServer(t, addr)
+		return
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	master := exec.Command(os.Args[0], "-test.run=^TestServer_PreforkShutdown$")
+	// fiber starts child per GOMAXPROCS
+	master.Env = append(os.Environ(), serverAddrEnv+"="+addr, "GOMAXPROCS=2")
+	require.NoError(t, master.Start())
+	defer master.Process.Kill()
+
+	readyz := func() (int, *models.HealthReport) {
+		resp, err := http.Get("http://" + addr + "/readyz")
+		if err != nil {
+			return 0, nil
+		}
+		defer resp.Body.Close()
+		report := &models.HealthReport{}
+		if json.NewDecoder(resp.Body).Decode(report) != nil {
+			return resp.StatusCode, nil
+		}
+		return resp.StatusCode, report
+	}
+	require.Eventually(t, func() bool {
+		status, _ := readyz()
+		return status == http.StatusOK
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// only master receives signal, like with docker stop
+	require.NoError(t, master.Process.Signal(syscall.SIGTERM))
+
+	var report *models.HealthReport
+	require.Eventually(t, func() bool {
+		var status int
+		status, report = readyz()
+		return status == http.StatusServiceUnavailable && report != nil
+	}, testShutdownDelay, 50*time.Millisecond)
+	require.Equal(t, entity.HealthStatusFail, report.Checks[service.HealthCheckShutdown].Status)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- master.Wait()
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * (testShutdownDelay + testShutdownTimeout)):
+		t.Fatal("master did not exit")
+	}
+}
+
+func runPreforkServer(t *testing.T, addr string) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockIHealthRepository(ctrl)
+	repo.EXPECT().
+		Ping(gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	repo.EXPECT().
+		GetSchemaVersion(gomock.Any()).
+		Return(&entity.SchemaVersion{Version: testSchemaVersion}, nil).
+		AnyTimes()
+	testApp := &app.App{
+		Logger:        mocks.NewMockLogger(),
+		HealthService: service.NewHealthService(repo, mocks.NewMockLogger(), testSchemaVersion, time.Second),
+	}
+
+	r := fiber.New(fiber.Config{Prefork: true, DisableStartupMessage: true})
+	r.Get("/readyz", handlers.ReadinessHandler(testApp))
+
+	srv := server.New(r, testApp.HealthService, testShutdownDelay, testShutdownTimeout)
+	err := srv.Run(addr, func() {}, syscall.SIGTERM)
+	require.NoError(t, err)
+	require.NoError(t, srv.Done())
+}
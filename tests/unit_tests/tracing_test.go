@@ -0,0 +1,184 @@
+package unit_tests
+
+import (
+	"Avito-Backend-trainee-assignment-winter-2025/internal/entity"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/mocks"
+	errs "Avito-Backend-trainee-assignment-winter-2025/internal/pkg/errors"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/pkg/tracing"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
+	"Avito-Backend-trainee-assignment-winter-2025/internal/web/middlewares"
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	incomingTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incomingParentSpan  = "00f067aa0ba902b7"
+	incomingTraceparent = "00-" + incomingTraceID + "-" + incomingParentSpan + "-01"
+)
+
+func setupTestTracing() *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+func findSpan(spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestTracing_HandlerToService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	itemService := mocks.NewMockIItemService(ctrl)
+
+	tests := []struct {
+		name        string
+		beforeTest  func(itemService *mocks.MockIItemService)
+		traceparent string
+		wantStatus  int
+		wantErr     bool
+	}{
+		{
+			name: "продолжение трассировки клиента",
+			beforeTest: func(itemService *mocks.MockIItemService) {
+				itemService.EXPECT().
+					BuyItem(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			traceparent: incomingTraceparent,
+			wantStatus:  fiber.StatusOK,
+		}, // продолжение трассировки клиента
+		{
+			name: "новая трассировка",
+			beforeTest: func(itemService *mocks.MockIItemService) {
+				itemService.EXPECT().
+					BuyItem(gomock.Any(), gomock.Any()).
+					Return(nil)
+			},
+			wantStatus: fiber.StatusOK,
+		}, // новая трассировка
+		{
+			name: "ошибка сервиса",
+			beforeTest: func(itemService *mocks.MockIItemService) {
+				itemService.EXPECT().
+					BuyItem(gomock.Any(), gomock.Any()).
+					Return(errs.InternalError)
+			},
+			traceparent: incomingTraceparent,
+			wantStatus:  fiber.StatusInternalServerError,
+			wantErr:     true,
+		}, // ошибка сервиса
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := setupTestTracing()
+			tt.beforeTest(itemService)
+
+			tracedService := service.NewItemServiceTracing(itemService)
+			r := fiber.New()
+			r.Use(middlewares.TracingMiddleware())
+			r.Get("/api/buy/:item", func(ctx *fiber.Ctx) error {
+				err := tracedService.BuyItem(ctx.UserContext(), &entity.Purchase{
+					Username: "user",
+					ItemName: ctx.Params("item"),
+				})
+				if err != nil {
+					return ctx.SendStatus(fiber.StatusInternalServerError)
+				}
+				return ctx.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/api/buy/cup", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			resp, err := r.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 2)
+			serverSpan := findSpan(spans, "GET /api/buy/:item")
+			require.NotNil(t, serverSpan)
+			serviceSpan := findSpan(spans, "ItemService.BuyItem")
+			require.NotNil(t, serviceSpan)
+
+			require.Equal(t, trace.SpanKindServer, serverSpan.SpanKind())
+			if tt.traceparent != "" {
+				require.Equal(t, incomingTraceID, serverSpan.SpanContext().TraceID().String())
+				require.Equal(t, incomingParentSpan, serverSpan.Parent().SpanID().String())
+			} else {
+				require.False(t, serverSpan.Parent().IsValid())
+			}
+			require.Equal(t, serverSpan.SpanContext().TraceID(), serviceSpan.SpanContext().TraceID())
+			require.Equal(t, serverSpan.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+
+			if tt.wantErr {
+				require.Equal(t, codes.Error, serverSpan.Status().Code)
+				require.Equal(t, codes.Error, serviceSpan.Status().Code)
+			} else {
+				require.Equal(t, codes.Unset, serverSpan.Status().Code)
+				require.Equal(t, codes.Unset, serviceSpan.Status().Code)
+			}
+		})
+	}
+}
+
+func TestTracing_QueryTracer(t *testing.T) {
+	recorder := setupTestTracing()
+	tracer := tracing.NewQueryTracer()
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "parent")
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{
+		SQL:  "select coins from users where username = $1 for update",
+		Args: []any{"user"},
+	})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{
+		CommandTag: pgconn.NewCommandTag("SELECT 1"),
+	})
+	failedCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "UPDATE users SET coins = $1"})
+	tracer.TraceQueryEnd(failedCtx, nil, pgx.TraceQueryEndData{Err: errs.InternalError})
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	selectSpan := findSpan(spans, "postgres select")
+	require.NotNil(t, selectSpan)
+	require.Equal(t, parent.SpanContext().SpanID(), selectSpan.Parent().SpanID())
+	require.Equal(t, trace.SpanKindClient, selectSpan.SpanKind())
+	attributes := make(map[string]string)
+	for _, attr := range selectSpan.Attributes() {
+		attributes[string(attr.Key)] = attr.Value.Emit()
+	}
+	require.Equal(t, "select coins from users where username = $1 for update", attributes["db.query.text"])
+	require.Equal(t, "1", attributes[string(tracing.RowsAffectedKey)])
+	for _, attr := range selectSpan.Attributes() {
+		require.NotEqual(t, "user", attr.Value.Emit(), "query arguments must not be recorded")
+	}
+
+	updateSpan := findSpan(spans, "postgres update")
+	require.NotNil(t, updateSpan)
+	require.Equal(t, codes.Error, updateSpan.Status().Code)
+}
@@ -7,7 +7,9 @@ import (
 	"Avito-Backend-trainee-assignment-winter-2025/internal/service"
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -38,8 +40,8 @@ func TestUserService_GetCoinsHistory(t *testing.T) {
 					Return(
 						int32(0),
 						&entity.CoinsHistory{
-							Received: make([]*entity.User, 0),
-							Sent:     make([]*entity.User, 0),
+							Received: make([]*entity.CoinsHistoryEntry, 0),
+							Sent:     make([]*entity.CoinsHistoryEntry, 0),
 						},
 						nil,
 					)
@@ -138,6 +140,57 @@ func TestUserService_SendCoins(t *testing.T) {
 			},
 			wantErr: false,
 		}, // успешная отправка монет
+		{
+			name: "отправка монет с комментарием",
+			transfer: &entity.TransferCoins{
+				FromUser: "1",
+				ToUser:   "2",
+				Amount:   100,
+				Comment:  " за\tпомощь\x00 с ревью\n",
+			},
+			beforeTest: func(authRepo mocks.MockIUserRepository) {
+				repo.EXPECT().
+					SendCoins(context.Background(), &entity.TransferCoins{
+						FromUser: "1",
+						ToUser:   "2",
+						Amount:   100,
+						Comment:  "запомощь с ревью",
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // отправка монет с комментарием
+		{
+			name: "комментарий максимальной длины",
+			transfer: &entity.TransferCoins{
+				FromUser: "1",
+				ToUser:   "2",
+				Amount:   100,
+				Comment:  strings.Repeat("ы", 128),
+			},
+			beforeTest: func(authRepo mocks.MockIUserRepository) {
+				repo.EXPECT().
+					SendCoins(context.Background(), &entity.TransferCoins{
+						FromUser: "1",
+						ToUser:   "2",
+						Amount:   100,
+						Comment:  strings.Repeat("ы", 128),
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // комментарий максимальной длины
+		{
+			name: "слишком длинный комментарий",
+			transfer: &entity.TransferCoins{
+				FromUser: "1",
+				ToUser:   "2",
+				Amount:   100,
+				Comment:  strings.Repeat("ы", 129),
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком длинный комментарий
 		{
 			name: "repo send coins error",
 			transfer: &entity.TransferCoins{
@@ -268,3 +321,283 @@ func TestUserService_SendCoins(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_GrantCoins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIUserRepository(ctrl)
+
+	svc := service.NewUserService(repo, logger)
+
+	tests := []struct {
+		name        string
+		grant       *entity.GrantCoins
+		beforeTest  func(userRepo mocks.MockIUserRepository)
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "успешное начисление",
+			grant: &entity.GrantCoins{
+				Username: "user",
+				Amount:   100,
+				Comment:  " бонус\n",
+			},
+			beforeTest: func(userRepo mocks.MockIUserRepository) {
+				userRepo.EXPECT().
+					GrantCoins(context.Background(), &entity.GrantCoins{
+						Username: "user",
+						Amount:   100,
+						Comment:  "бонус",
+					}).
+					Return(nil)
+			},
+			wantErr: false,
+		}, // успешное начисление
+		{
+			name: "пустое имя пользователя",
+			grant: &entity.GrantCoins{
+				Amount: 100,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя пользователя
+		{
+			name: "неположительная сумма",
+			grant: &entity.GrantCoins{
+				Username: "user",
+				Amount:   0,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // неположительная сумма
+		{
+			name: "слишком длинный комментарий",
+			grant: &entity.GrantCoins{
+				Username: "user",
+				Amount:   100,
+				Comment:  strings.Repeat("a", 129),
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком длинный комментарий
+		{
+			name: "пользователь не найден",
+			grant: &entity.GrantCoins{
+				Username: "unknown",
+				Amount:   100,
+			},
+			beforeTest: func(userRepo mocks.MockIUserRepository) {
+				userRepo.EXPECT().
+					GrantCoins(context.Background(), gomock.Any()).
+					Return(errs.UserNotFound)
+			},
+			wantErr:     true,
+			requiredErr: errs.UserNotFound,
+		}, // пользователь не найден
+		{
+			name: "repo grant coins error",
+			grant: &entity.GrantCoins{
+				Username: "user",
+				Amount:   100,
+			},
+			beforeTest: func(userRepo mocks.MockIUserRepository) {
+				userRepo.EXPECT().
+					GrantCoins(context.Background(), gomock.Any()).
+					Return(fmt.Errorf("repo error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo grant coins error
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			err := svc.GrantCoins(context.Background(), tt.grant)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_GetTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := mocks.NewMockLogger()
+	repo := mocks.NewMockIUserRepository(ctrl)
+
+	svc := service.NewUserService(repo, logger)
+
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	transactions := []*entity.Transaction{
+		{ID: "3", Time: now, FromUser: "user", ToUser: "first", Amount: 10},
+		{ID: "2", Time: now.Add(-time.Minute), FromUser: "first", ToUser: "user", Amount: 20},
+		{ID: "1", Time: now.Add(-2 * time.Minute), FromUser: "user", ToUser: "second", Amount: 30},
+	}
+
+	tests := []struct {
+		name        string
+		filter      *entity.TransactionsFilter
+		beforeTest  func(repo mocks.MockIUserRepository)
+		page        *entity.TransactionsPage
+		wantErr     bool
+		requiredErr error
+	}{
+		{
+			name: "страница с продолжением",
+			filter: &entity.TransactionsFilter{
+				Username: "user",
+				Limit:    2,
+			},
+			beforeTest: func(repo mocks.MockIUserRepository) {
+				repo.EXPECT().
+					GetTransactions(context.Background(), &entity.TransactionsFilter{
+						Username: "user",
+						Limit:    3,
+					}).
+					Return(transactions, nil)
+			},
+			page: &entity.TransactionsPage{
+				Transactions: transactions[:2],
+				Next: &entity.TransactionsCursor{
+					Time: transactions[1].Time,
+					ID:   transactions[1].ID,
+				},
+			},
+			wantErr: false,
+		}, // страница с продолжением
+		{
+			name: "последняя страница",
+			filter: &entity.TransactionsFilter{
+				Username:  "user",
+				Direction: entity.DirectionSent,
+				From:      &earlier,
+				To:        &now,
+				Limit:     3,
+			},
+			beforeTest: func(repo mocks.MockIUserRepository) {
+				repo.EXPECT().
+					GetTransactions(context.Background(), &entity.TransactionsFilter{
+						Username:  "user",
+						Direction: entity.DirectionSent,
+						From:      &earlier,
+						To:        &now,
+						Limit:     4,
+					}).
+					Return(transactions, nil)
+			},
+			page: &entity.TransactionsPage{
+				Transactions: transactions,
+			},
+			wantErr: false,
+		}, // последняя страница
+		{
+			name: "repo get transactions error",
+			filter: &entity.TransactionsFilter{
+				Username: "user",
+				Limit:    2,
+			},
+			beforeTest: func(repo mocks.MockIUserRepository) {
+				repo.EXPECT().
+					GetTransactions(context.Background(), &entity.TransactionsFilter{
+						Username: "user",
+						Limit:    3,
+					}).
+					Return(nil, fmt.Errorf("repo error"))
+			},
+			wantErr:     true,
+			requiredErr: errs.InternalError,
+		}, // repo get transactions error
+		{
+			name: "неизвестное направление",
+			filter: &entity.TransactionsFilter{
+				Username:  "user",
+				Direction: "both",
+				Limit:     2,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // неизвестное направление
+		{
+			name: "начало периода позже конца",
+			filter: &entity.TransactionsFilter{
+				Username: "user",
+				From:     &now,
+				To:       &earlier,
+				Limit:    2,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // начало периода позже конца
+		{
+			name: "слишком большая страница",
+			filter: &entity.TransactionsFilter{
+				Username: "user",
+				Limit:    101,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // слишком большая страница
+		{
+			name: "нулевой размер страницы",
+			filter: &entity.TransactionsFilter{
+				Username: "user",
+				Limit:    0,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // нулевой размер страницы
+		{
+			name: "пустой курсор",
+			filter: &entity.TransactionsFilter{
+				Username: "user",
+				After:    &entity.TransactionsCursor{Time: now},
+				Limit:    2,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустой курсор
+		{
+			name: "пустое имя пользователя",
+			filter: &entity.TransactionsFilter{
+				Limit: 2,
+			},
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // пустое имя пользователя
+		{
+			name:        "nil",
+			filter:      nil,
+			wantErr:     true,
+			requiredErr: errs.InvalidData,
+		}, // nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.beforeTest != nil {
+				tt.beforeTest(*repo)
+			}
+
+			page, err := svc.GetTransactions(context.Background(), tt.filter)
+
+			if tt.wantErr {
+				require.Equal(t, tt.requiredErr, err)
+				require.Nil(t, page)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tt.page, page)
+			}
+		})
+	}
+}